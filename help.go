@@ -54,14 +54,14 @@ func Usage() {
 		Description:   "Options controlling the HTTP request and its parts.",
 		Flags:         make([]UsageFlag, 0),
 		Hidden:        false,
-		ExpectedFlags: []string{"cc", "ck", "H", "X", "b", "d", "r", "u", "raw", "recursion", "recursion-depth", "recursion-strategy", "replay-proxy", "timeout", "ignore-body", "x", "sni", "http2", "ecr"},
+		ExpectedFlags: []string{"cc", "ck", "H", "X", "b", "cookie-jar", "cookie-jar-file", "csrf-keyword", "csrf-rule", "csrf-url", "d", "r", "u", "raw", "raw-socket", "recursion", "recursion-depth", "recursion-strategy", "replay-proxy", "retries", "retry-backoff", "retry-on", "session-request", "session-extract", "session-mc", "session-mr", "timeout", "ignore-body", "u-list", "x", "sni", "http2", "http3", "ecr"},
 	}
 	u_general := UsageSection{
 		Name:          "GENERAL OPTIONS",
 		Description:   "",
 		Flags:         make([]UsageFlag, 0),
 		Hidden:        false,
-		ExpectedFlags: []string{"ac", "acc", "ack", "ach", "acs", "c", "config", "json", "maxtime", "maxtime-job", "noninteractive", "p", "rate", "scraperfile", "scrapers", "search", "s", "sa", "se", "sf", "t", "v", "V"},
+		ExpectedFlags: []string{"ac", "acc", "ack", "ach", "acr", "acs", "c", "chunk-size", "config", "coordinator", "coordinator-secret", "json", "maxtime", "maxtime-job", "noninteractive", "p", "rate", "rate-adaptive", "resume", "scraperfile", "scrapers", "search", "s", "sa", "se", "sf", "t", "v", "V"},
 	}
 	u_compat := UsageSection{
 		Name:          "COMPATIBILITY OPTIONS",
@@ -82,21 +82,21 @@ func Usage() {
 		Description:   "Filters for the response filtering.",
 		Flags:         make([]UsageFlag, 0),
 		Hidden:        false,
-		ExpectedFlags: []string{"fmode", "fc", "fl", "fr", "fs", "ft", "fw"},
+		ExpectedFlags: []string{"fmode", "fc", "fl", "fr", "fs", "fsim", "ft", "fw"},
 	}
 	u_input := UsageSection{
 		Name:          "INPUT OPTIONS",
 		Description:   "Options for input data for fuzzing. Wordlists and input generators.",
 		Flags:         make([]UsageFlag, 0),
 		Hidden:        false,
-		ExpectedFlags: []string{"D", "enc", "ic", "input-cmd", "input-num", "input-shell", "mode", "request", "request-proto", "e", "w"},
+		ExpectedFlags: []string{"D", "enc", "ic", "input-cmd", "input-cmd-mode", "input-cmd-nul", "input-num", "input-shell", "mask", "mask-charset", "mode", "range", "rules", "replay-errors", "request", "request-proto", "e", "w", "ws"},
 	}
 	u_output := UsageSection{
 		Name:          "OUTPUT OPTIONS",
 		Description:   "Options for output. Output file formats, file names and debug file locations.",
 		Flags:         make([]UsageFlag, 0),
 		Hidden:        false,
-		ExpectedFlags: []string{"debug-log", "o", "of", "od", "or"},
+		ExpectedFlags: []string{"debug-log", "har-all", "o", "oe", "of", "od", "or", "output-provider", "sqlite-bodies"},
 	}	
 
 	sections := []UsageSection{u_http, u_general, u_compat, u_matcher, u_filter, u_input, u_output}
@@ -148,5 +148,9 @@ func Usage() {
 	fmt.Printf("  Fuzz multiple locations. Match only responses reflecting the value of \"VAL\" keyword. Colored.\n")
 	fmt.Printf("    ffuf -w params.txt:PARAM -w values.txt:VAL -u https://example.org/?PARAM=VAL -mr \"VAL\" -c\n\n")
 
+	fmt.Printf("  Split the job between workers on other machines, which connect to the coordinator and send back the results.\n")
+	fmt.Printf("    ffuf -w wordlist.txt -u https://example.org/FUZZ -coordinator 0.0.0.0:8700 -coordinator-secret s3cret\n")
+	fmt.Printf("    ffuf worker -coordinator http://10.0.0.1:8700 -secret s3cret\n\n")
+
 	fmt.Printf("  More information and examples: https://github.com/ffuf/ffuf\n\n")
 }
@@ -11,12 +11,10 @@ import (
 	"time"
 
 	"github.com/ffuf/ffuf/v2/pkg/ffuf"
-	"github.com/ffuf/ffuf/v2/pkg/filter"
 	"github.com/ffuf/ffuf/v2/pkg/input"
 	"github.com/ffuf/ffuf/v2/pkg/interactive"
-	"github.com/ffuf/ffuf/v2/pkg/output"
 	"github.com/ffuf/ffuf/v2/pkg/runner"
-	"github.com/ffuf/ffuf/v2/pkg/scraper"
+	"github.com/ffuf/ffuf/v2/pkg/scanner"
 )
 
 type multiStringFlag []string
@@ -51,25 +49,35 @@ func (m *wordlistFlag) Set(value string) error {
 func ParseFlags(opts *ffuf.ConfigOptions) *ffuf.ConfigOptions {
 	var ignored bool
 
-	var cookies, autocalibrationstrings, autocalibrationstrategies, headers, inputcommands multiStringFlag
+	var cookies, autocalibrationstrings, autocalibrationstrategies, headers, inputcommands, maskcharsets, masks, outputproviders, ranges, rules, sessionextract multiStringFlag
 	var wordlists, encoders wordlistFlag
 
 	cookies = opts.HTTP.Cookies
 	autocalibrationstrings = opts.General.AutoCalibrationStrings
 	headers = opts.HTTP.Headers
 	inputcommands = opts.Input.Inputcommands
+	maskcharsets = opts.Input.MaskCharsets
+	masks = opts.Input.Masks
+	outputproviders = opts.Output.OutputProviders
+	ranges = opts.Input.Ranges
+	rules = opts.Input.Rules
+	sessionextract = opts.HTTP.SessionExtract
 	wordlists = opts.Input.Wordlists
 	encoders = opts.Input.Encoders
 
 	flag.BoolVar(&ignored, "compressed", true, "Dummy flag for copy as curl functionality (ignored)")
 	flag.BoolVar(&ignored, "i", true, "Dummy flag for copy as curl functionality (ignored)")
 	flag.BoolVar(&ignored, "k", false, "Dummy flag for backwards compatibility")
+	flag.BoolVar(&opts.Input.InputCmdNul, "input-cmd-nul", opts.Input.InputCmdNul, "The payloads of the input command are NUL-delimited instead of newline-delimited (-input-cmd-mode stream or request)")
+	flag.BoolVar(&opts.Output.HarAll, "har-all", opts.Output.HarAll, "Record all of the requests to the HAR output file (-of har), not only the results")
+	flag.BoolVar(&opts.Output.SqliteBodies, "sqlite-bodies", opts.Output.SqliteBodies, "Store the raw requests and responses of the results in the sqlite output provider database")
 	flag.BoolVar(&opts.Output.OutputSkipEmptyFile, "or", opts.Output.OutputSkipEmptyFile, "Don't create the output file if we don't have results")
 	flag.BoolVar(&opts.General.AutoCalibration, "ac", opts.General.AutoCalibration, "Automatically calibrate filtering options")
 	flag.BoolVar(&opts.General.AutoCalibrationPerHost, "ach", opts.General.AutoCalibration, "Per host autocalibration")
 	flag.BoolVar(&opts.General.Colors, "c", opts.General.Colors, "Colorize output.")
 	flag.BoolVar(&opts.General.Json, "json", opts.General.Json, "JSON output, printing newline-delimited JSON records")
 	flag.BoolVar(&opts.General.Noninteractive, "noninteractive", opts.General.Noninteractive, "Disable the interactive console functionality")
+	flag.BoolVar(&opts.General.RateAdaptive, "rate-adaptive", opts.General.RateAdaptive, "Adapt the rate of requests per host, backing off on 429 and 503 responses, Retry-After headers and response time spikes. -rate sets the maximum rate per host")
 	flag.BoolVar(&opts.General.Quiet, "s", opts.General.Quiet, "Do not print additional information (silent mode)")
 	flag.BoolVar(&opts.General.ShowVersion, "V", opts.General.ShowVersion, "Show version information.")
 	flag.BoolVar(&opts.General.StopOn403, "sf", opts.General.StopOn403, "Stop when > 95% of responses return 403 Forbidden")
@@ -79,32 +87,48 @@ func ParseFlags(opts *ffuf.ConfigOptions) *ffuf.ConfigOptions {
 	flag.BoolVar(&opts.HTTP.FollowRedirects, "r", opts.HTTP.FollowRedirects, "Follow redirects")
 	flag.BoolVar(&opts.HTTP.IgnoreBody, "ignore-body", opts.HTTP.IgnoreBody, "Do not fetch the response content.")
 	flag.BoolVar(&opts.HTTP.Raw, "raw", opts.HTTP.Raw, "Do not encode URI")
+	flag.BoolVar(&opts.HTTP.RawSocket, "raw-socket", opts.HTTP.RawSocket, "Send the request file (-request) byte-for-byte over a TCP or TLS socket, only replacing the keywords")
 	flag.BoolVar(&opts.HTTP.Recursion, "recursion", opts.HTTP.Recursion, "Scan recursively. Only FUZZ keyword is supported, and URL (-u) has to end in it.")
 	flag.BoolVar(&opts.HTTP.Http2, "http2", opts.HTTP.Http2, "Use HTTP2 protocol")
+	flag.BoolVar(&opts.HTTP.Http3, "http3", opts.HTTP.Http3, "Use HTTP3 protocol over QUIC. Proxies are not supported.")
 	flag.BoolVar(&opts.Input.DirSearchCompat, "D", opts.Input.DirSearchCompat, "DirSearch wordlist compatibility mode. Used in conjunction with -e flag.")
 	flag.BoolVar(&opts.Input.IgnoreWordlistComments, "ic", opts.Input.IgnoreWordlistComments, "Ignore wordlist comments")
+	flag.BoolVar(&opts.Input.WordlistStream, "ws", opts.Input.WordlistStream, "Stream wordlists from disk instead of reading them to memory. Useful for very large wordlists and stdin input.")
+	flag.IntVar(&opts.General.ChunkSize, "chunk-size", opts.General.ChunkSize, "Number of inputs in each task handed to the workers by the coordinator (-coordinator)")
+	flag.IntVar(&opts.General.AutoCalibrationRecheck, "acr", opts.General.AutoCalibrationRecheck, "Send a random probe every N requests to detect changes in the autocalibration baseline, and re-run autocalibration if it changed. Implies -ac")
 	flag.IntVar(&opts.General.MaxTime, "maxtime", opts.General.MaxTime, "Maximum running time in seconds for entire process.")
 	flag.IntVar(&opts.General.MaxTimeJob, "maxtime-job", opts.General.MaxTimeJob, "Maximum running time in seconds per job.")
 	flag.IntVar(&opts.General.Rate, "rate", opts.General.Rate, "Rate of requests per second")
 	flag.IntVar(&opts.General.Threads, "t", opts.General.Threads, "Number of concurrent threads.")
 	flag.IntVar(&opts.HTTP.RecursionDepth, "recursion-depth", opts.HTTP.RecursionDepth, "Maximum recursion depth.")
+	flag.IntVar(&opts.HTTP.Retries, "retries", opts.HTTP.Retries, "Number of times to retry a request after an error or a -retry-on status code")
+	flag.Float64Var(&opts.HTTP.RetryBackoff, "retry-backoff", opts.HTTP.RetryBackoff, "Initial delay in seconds before retrying a request, doubled on each retry and randomized by up to half")
 	flag.IntVar(&opts.HTTP.Timeout, "timeout", opts.HTTP.Timeout, "HTTP request timeout in seconds.")
 	flag.IntVar(&opts.Input.InputNum, "input-num", opts.Input.InputNum, "Number of inputs to test. Used in conjunction with --input-cmd.")
 	flag.StringVar(&opts.General.AutoCalibrationKeyword, "ack", opts.General.AutoCalibrationKeyword, "Autocalibration keyword")
 	flag.StringVar(&opts.HTTP.ClientCert, "cc", "", "Client cert for authentication. Client key needs to be defined as well for this to work")
 	flag.StringVar(&opts.HTTP.ClientKey, "ck", "", "Client key for authentication. Client certificate needs to be defined as well for this to work")
 	flag.StringVar(&opts.General.ConfigFile, "config", "", "Load configuration from a file")
+	flag.StringVar(&opts.General.Coordinator, "coordinator", opts.General.Coordinator, "Listen for ffuf worker processes on this address, eg. 0.0.0.0:8700, and split the job between them instead of sending the requests")
+	flag.StringVar(&opts.General.CoordinatorSecret, "coordinator-secret", opts.General.CoordinatorSecret, "Shared secret the workers need to connect to the coordinator (-coordinator). Read from FFUF_COORDINATOR_SECRET if not set, and generated if neither is set")
 	flag.StringVar(&opts.General.ScraperFile, "scraperfile", "", "Custom scraper file path")
 	flag.StringVar(&opts.General.Scrapers, "scrapers", opts.General.Scrapers, "Active scraper groups")
 	flag.StringVar(&opts.Filter.Mode, "fmode", opts.Filter.Mode, "Filter set operator. Either of: and, or")
 	flag.StringVar(&opts.Filter.Lines, "fl", opts.Filter.Lines, "Filter by amount of lines in response. Comma separated list of line counts and ranges")
 	flag.StringVar(&opts.Filter.Regexp, "fr", opts.Filter.Regexp, "Filter regexp")
+	flag.StringVar(&opts.Filter.Similarity, "fsim", opts.Filter.Similarity, "Filter responses with body similar to a baseline, by similarity percentage. The baselines are collected by autocalibration, or read from a file: 95:baseline.html")
 	flag.StringVar(&opts.Filter.Size, "fs", opts.Filter.Size, "Filter HTTP response size. Comma separated list of sizes and ranges")
 	flag.StringVar(&opts.Filter.Status, "fc", opts.Filter.Status, "Filter HTTP status codes from response. Comma separated list of codes and ranges")
 	flag.StringVar(&opts.Filter.Time, "ft", opts.Filter.Time, "Filter by number of milliseconds to the first response byte, either greater or less than. EG: >100 or <100")
 	flag.StringVar(&opts.Filter.Words, "fw", opts.Filter.Words, "Filter by amount of words in response. Comma separated list of word counts and ranges")
 	flag.StringVar(&opts.General.Delay, "p", opts.General.Delay, "Seconds of `delay` between requests, or a range of random delay. For example \"0.1\" or \"0.1-2.0\"")
 	flag.StringVar(&opts.General.Searchhash, "search", opts.General.Searchhash, "Search for a FFUFHASH payload from ffuf history")
+	flag.StringVar(&opts.General.Resume, "resume", opts.General.Resume, "File to periodically save the job state to. If the file exists, the interrupted job is continued using the options stored in it.")
+	flag.StringVar(&opts.HTTP.CookieJar, "cookie-jar", opts.HTTP.CookieJar, "Store the cookies set by the responses and send them with the following requests. Either of: shared, thread (a cookie jar for each thread)")
+	flag.StringVar(&opts.HTTP.CookieJarFile, "cookie-jar-file", opts.HTTP.CookieJarFile, "Netscape cookies.txt file to seed the cookie jar with. Implies -cookie-jar shared")
+	flag.StringVar(&opts.HTTP.CSRFKeyword, "csrf-keyword", opts.HTTP.CSRFKeyword, "Keyword replaced with the anti-CSRF token fetched with -csrf-url")
+	flag.StringVar(&opts.HTTP.CSRFRule, "csrf-rule", opts.HTTP.CSRFRule, "Rule extracting the anti-CSRF token from the -csrf-url response, eg. 'regexp:name=\"csrf\" value=\"([^\"]+)\"' or 'query:input[name=csrf]'")
+	flag.StringVar(&opts.HTTP.CSRFURL, "csrf-url", opts.HTTP.CSRFURL, "URL to fetch a fresh anti-CSRF token from before each request. The cookies set by the response are sent with the request")
 	flag.StringVar(&opts.HTTP.Data, "d", opts.HTTP.Data, "POST data")
 	flag.StringVar(&opts.HTTP.Data, "data", opts.HTTP.Data, "POST data (alias of -d)")
 	flag.StringVar(&opts.HTTP.Data, "data-ascii", opts.HTTP.Data, "POST data (alias of -d)")
@@ -112,12 +136,19 @@ func ParseFlags(opts *ffuf.ConfigOptions) *ffuf.ConfigOptions {
 	flag.StringVar(&opts.HTTP.Method, "X", opts.HTTP.Method, "HTTP method to use")
 	flag.StringVar(&opts.HTTP.ProxyURL, "x", opts.HTTP.ProxyURL, "Proxy URL (SOCKS5 or HTTP). For example: http://127.0.0.1:8080 or socks5://127.0.0.1:8080")
 	flag.StringVar(&opts.HTTP.ReplayProxyURL, "replay-proxy", opts.HTTP.ReplayProxyURL, "Replay matched requests using this proxy.")
+	flag.StringVar(&opts.HTTP.RetryOn, "retry-on", opts.HTTP.RetryOn, "Retry requests returning these HTTP status codes. Comma separated list of codes and ranges, eg. 429,502-504")
 	flag.StringVar(&opts.HTTP.RecursionStrategy, "recursion-strategy", opts.HTTP.RecursionStrategy, "Recursion strategy: \"default\" for a redirect based, and \"greedy\" to recurse on all matches")
+	flag.StringVar(&opts.HTTP.SessionMatchRegexp, "session-mr", opts.HTTP.SessionMatchRegexp, "Log in again with -session-request when the response body matches this regexp")
+	flag.StringVar(&opts.HTTP.SessionMatchStatus, "session-mc", opts.HTTP.SessionMatchStatus, "Log in again with -session-request when the response has one of these HTTP status codes, eg. 401,403")
+	flag.StringVar(&opts.HTTP.SessionRequest, "session-request", opts.HTTP.SessionRequest, "File containing a raw http login request, sent before the job and when the session has expired. Cookies set by the response are sent with the requests")
 	flag.StringVar(&opts.HTTP.URL, "u", opts.HTTP.URL, "Target URL")
+	flag.StringVar(&opts.HTTP.URLList, "u-list", opts.HTTP.URLList, "File containing a list of target URLs, fuzzed one after another. FUZZ keyword is appended to the URLs without a keyword")
 	flag.StringVar(&opts.HTTP.SNI, "sni", opts.HTTP.SNI, "Target TLS SNI, does not support FUZZ keyword")
 	flag.StringVar(&opts.Input.Extensions, "e", opts.Input.Extensions, "Comma separated list of extensions. Extends FUZZ keyword.")
 	flag.StringVar(&opts.Input.InputMode, "mode", opts.Input.InputMode, "Multi-wordlist operation mode. Available modes: clusterbomb, pitchfork, sniper")
+	flag.StringVar(&opts.Input.InputCmdMode, "input-cmd-mode", opts.Input.InputCmdMode, "How the input command is run: exec (once for each input, with the position in $FFUF_NUM), stream (once, writing the payloads to stdout) or request (once, replying with the payload for each position written to stdin)")
 	flag.StringVar(&opts.Input.InputShell, "input-shell", opts.Input.InputShell, "Shell to be used for running command")
+	flag.StringVar(&opts.Input.ReplayErrors, "replay-errors", opts.Input.ReplayErrors, "Only rerun the failed requests recorded in an errors file (-oe)")
 	flag.StringVar(&opts.Input.Request, "request", opts.Input.Request, "File containing the raw http request")
 	flag.StringVar(&opts.Input.RequestProto, "request-proto", opts.Input.RequestProto, "Protocol to use along with raw request")
 	flag.StringVar(&opts.Matcher.Mode, "mmode", opts.Matcher.Mode, "Matcher set operator. Either of: and, or")
@@ -128,15 +159,22 @@ func ParseFlags(opts *ffuf.ConfigOptions) *ffuf.ConfigOptions {
 	flag.StringVar(&opts.Matcher.Time, "mt", opts.Matcher.Time, "Match how many milliseconds to the first response byte, either greater or less than. EG: >100 or <100")
 	flag.StringVar(&opts.Matcher.Words, "mw", opts.Matcher.Words, "Match amount of words in response")
 	flag.StringVar(&opts.Output.DebugLog, "debug-log", opts.Output.DebugLog, "Write all of the internal logging to the specified file.")
+	flag.StringVar(&opts.Output.ErrorFile, "oe", opts.Output.ErrorFile, "Write the inputs of failed requests to a JSONL file, to be rerun with -replay-errors")
 	flag.StringVar(&opts.Output.OutputDirectory, "od", opts.Output.OutputDirectory, "Directory path to store matched results to.")
 	flag.StringVar(&opts.Output.OutputFile, "o", opts.Output.OutputFile, "Write output to file")
-	flag.StringVar(&opts.Output.OutputFormat, "of", opts.Output.OutputFormat, "Output file format. Available formats: json, ejson, html, md, csv, ecsv (or, 'all' for all formats)")
+	flag.StringVar(&opts.Output.OutputFormat, "of", opts.Output.OutputFormat, "Output file format. Available formats: json, ejson, html, md, csv, ecsv, jsonl, sarif, junit, burp, har (or, 'all' for all formats)")
 	flag.Var(&autocalibrationstrings, "acc", "Custom auto-calibration string. Can be used multiple times. Implies -ac")
 	flag.Var(&autocalibrationstrategies, "acs", "Custom auto-calibration strategies. Can be used multiple times. Implies -ac")
 	flag.Var(&cookies, "b", "Cookie data `\"NAME1=VALUE1; NAME2=VALUE2\"` for copy as curl functionality.")
 	flag.Var(&cookies, "cookie", "Cookie data (alias of -b)")
 	flag.Var(&headers, "H", "Header `\"Name: Value\"`, separated by colon. Multiple -H flags are accepted.")
+	flag.Var(&outputproviders, "output-provider", "Output provider for the results: stdout (default), jsonl:FILE (or jsonl for stdout), sqlite:FILE or webhook:URL. Multiple -output-provider flags are accepted.")
+	flag.Var(&sessionextract, "session-extract", "Value from the login response to replace a keyword in the requests, eg. 'CSRF=regexp:name=\"csrf\" value=\"([^\"]+)\"' or 'TOKEN=json:data.token'. Multiple -session-extract flags are accepted.")
 	flag.Var(&inputcommands, "input-cmd", "Command producing the input. --input-num is required when using this input method. Overrides -w.")
+	flag.Var(&masks, "mask", "Hashcat style mask generating the input and (optional) keyword separated by colon, eg. '?u?l?l?d?d:KEYWORD'. Charsets: ?l ?u ?d ?h ?H ?s ?a ?b and custom charsets ?1 - ?9")
+	flag.Var(&maskcharsets, "mask-charset", "Custom charset for -mask, eg. '?l?d-'. The first one is ?1, the second one ?2 and so on.")
+	flag.Var(&rules, "rules", "Wordlist mutation rules file for a keyword, eg. 'FUZZ:/path/to/rules'. Supports a subset of the hashcat rule syntax.")
+	flag.Var(&ranges, "range", "Numeric range generating the input and (optional) keyword separated by colon, eg. '1-1000:KEYWORD', '0001-9999', '0-1000/5' or '0x00-0xff'")
 	flag.Var(&wordlists, "w", "Wordlist file path and (optional) keyword separated by colon. eg. '/path/to/wordlist:KEYWORD'")
 	flag.Var(&encoders, "enc", "Encoders for keywords, eg. 'FUZZ:urlencode b64encode'")
 	flag.Usage = Usage
@@ -151,7 +189,13 @@ func ParseFlags(opts *ffuf.ConfigOptions) *ffuf.ConfigOptions {
 	}
 	opts.HTTP.Cookies = cookies
 	opts.HTTP.Headers = headers
+	opts.HTTP.SessionExtract = sessionextract
 	opts.Input.Inputcommands = inputcommands
+	opts.Input.MaskCharsets = maskcharsets
+	opts.Input.Masks = masks
+	opts.Input.Ranges = ranges
+	opts.Input.Rules = rules
+	opts.Output.OutputProviders = outputproviders
 	opts.Input.Wordlists = wordlists
 	opts.Input.Encoders = encoders
 	return opts
@@ -165,6 +209,12 @@ func main() {
 	var err, optserr error
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
+	if len(os.Args) > 1 && os.Args[1] == "worker" {
+		os.Exit(runWorker(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "query" {
+		os.Exit(runQuery(os.Args[2:]))
+	}
 	// prepare the default config options from default config file
 	var opts *ffuf.ConfigOptions
 	opts, optserr = ffuf.ReadDefaultConfig()
@@ -234,6 +284,18 @@ func main() {
 		opts = ParseFlags(opts)
 	}
 
+	// Continue an interrupted job using the options stored in the resume file
+	if opts.General.Resume != "" && ffuf.FileExists(opts.General.Resume) {
+		resumefile := opts.General.Resume
+		state, err := ffuf.ReadResumeState(resumefile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Encountered error while reading resume file: %s\n", err)
+			os.Exit(1)
+		}
+		opts = &state.Options
+		opts.General.Resume = resumefile
+	}
+
 	// Set up Config struct
 	conf, err := ffuf.ConfigFromOptions(opts, ctx, cancel)
 	if err != nil {
@@ -243,19 +305,20 @@ func main() {
 		os.Exit(1)
 	}
 
-	job, err := prepareJob(conf)
+	job, err := scanner.PrepareJob(conf)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Encountered error(s): %s\n", err)
 		Usage()
 		fmt.Fprintf(os.Stderr, "Encountered error(s): %s\n", err)
 		os.Exit(1)
 	}
-	if err := SetupFilters(opts, conf); err != nil {
+	if err := scanner.SetupFilters(opts, conf, flagSet); err != nil {
 		fmt.Fprintf(os.Stderr, "Encountered error(s): %s\n", err)
 		Usage()
 		fmt.Fprintf(os.Stderr, "Encountered error(s): %s\n", err)
 		os.Exit(1)
 	}
+	warnIgnoreBody(opts, conf)
 
 	if !conf.Noninteractive {
 		go func() {
@@ -270,135 +333,24 @@ func main() {
 	job.Start()
 }
 
-func prepareJob(conf *ffuf.Config) (*ffuf.Job, error) {
-	var err error
-	job := ffuf.NewJob(conf)
-	var errs ffuf.Multierror
-	job.Input, errs = input.NewInputProvider(conf)
-	// TODO: implement error handling for runnerprovider and outputprovider
-	// We only have http runner right now
-	job.Runner = runner.NewRunnerByName("http", conf, false)
-	if len(conf.ReplayProxyURL) > 0 {
-		job.ReplayRunner = runner.NewRunnerByName("http", conf, true)
-	}
-	// We only have stdout outputprovider right now
-	job.Output = output.NewOutputProviderByName("stdout", conf)
-
-	// Initialize scraper
-	newscraper, scraper_err := scraper.FromDir(ffuf.SCRAPERDIR, conf.Scrapers)
-	if scraper_err.ErrorOrNil() != nil {
-		errs.Add(scraper_err.ErrorOrNil())
-	}
-	job.Scraper = newscraper
-	if conf.ScraperFile != "" {
-		err = job.Scraper.AppendFromFile(conf.ScraperFile)
-		if err != nil {
-			errs.Add(err)
-		}
-	}
-	return job, errs.ErrorOrNil()
-}
-
-func SetupFilters(parseOpts *ffuf.ConfigOptions, conf *ffuf.Config) error {
-	errs := ffuf.NewMultierror()
-	conf.MatcherManager = filter.NewMatcherManager()
-	// If any other matcher is set, ignore -mc default value
-	matcherSet := false
-	statusSet := false
-	warningIgnoreBody := false
+// flagSet tells if a flag was set on the command line
+func flagSet(name string) bool {
+	set := false
 	flag.Visit(func(f *flag.Flag) {
-		if f.Name == "mc" {
-			statusSet = true
-		}
-		if f.Name == "ms" {
-			matcherSet = true
-			warningIgnoreBody = true
-		}
-		if f.Name == "ml" {
-			matcherSet = true
-			warningIgnoreBody = true
-		}
-		if f.Name == "mr" {
-			matcherSet = true
-		}
-		if f.Name == "mt" {
-			matcherSet = true
-		}
-		if f.Name == "mw" {
-			matcherSet = true
-			warningIgnoreBody = true
+		if f.Name == name {
+			set = true
 		}
 	})
-	// Only set default matchers if no
-	if statusSet || !matcherSet {
-		if err := conf.MatcherManager.AddMatcher("status", parseOpts.Matcher.Status); err != nil {
-			errs.Add(err)
-		}
-	}
+	return set
+}
 
-	
-	if parseOpts.Filter.Status != "" {
-		if err := conf.MatcherManager.AddFilter("status", parseOpts.Filter.Status, false); err != nil {
-			errs.Add(err)
-		}
-	}
-	if parseOpts.Filter.Size != "" {
-		warningIgnoreBody = true
-		if err := conf.MatcherManager.AddFilter("size", parseOpts.Filter.Size, false); err != nil {
-			errs.Add(err)
-		}
-	}
-	if parseOpts.Filter.Regexp != "" {
-		if err := conf.MatcherManager.AddFilter("regexp", parseOpts.Filter.Regexp, false); err != nil {
-			errs.Add(err)
-		}
-	}
-	if parseOpts.Filter.Words != "" {
-		warningIgnoreBody = true
-		if err := conf.MatcherManager.AddFilter("word", parseOpts.Filter.Words, false); err != nil {
-			errs.Add(err)
-		}
-	}
-	if parseOpts.Filter.Lines != "" {
-		warningIgnoreBody = true
-		if err := conf.MatcherManager.AddFilter("line", parseOpts.Filter.Lines, false); err != nil {
-			errs.Add(err)
-		}
-	}
-	if parseOpts.Filter.Time != "" {
-		if err := conf.MatcherManager.AddFilter("time", parseOpts.Filter.Time, false); err != nil {
-			errs.Add(err)
-		}
-	}
-	if parseOpts.Matcher.Size != "" {
-		if err := conf.MatcherManager.AddMatcher("size", parseOpts.Matcher.Size); err != nil {
-			errs.Add(err)
-		}
-	}
-	if parseOpts.Matcher.Regexp != "" {
-		if err := conf.MatcherManager.AddMatcher("regexp", parseOpts.Matcher.Regexp); err != nil {
-			errs.Add(err)
-		}
-	}
-	if parseOpts.Matcher.Words != "" {
-		if err := conf.MatcherManager.AddMatcher("word", parseOpts.Matcher.Words); err != nil {
-			errs.Add(err)
-		}
-	}
-	if parseOpts.Matcher.Lines != "" {
-		if err := conf.MatcherManager.AddMatcher("line", parseOpts.Matcher.Lines); err != nil {
-			errs.Add(err)
-		}
-	}
-	if parseOpts.Matcher.Time != "" {
-		if err := conf.MatcherManager.AddMatcher("time", parseOpts.Matcher.Time); err != nil {
-			errs.Add(err)
-		}
-	}
-	if conf.IgnoreBody && warningIgnoreBody {
+// warnIgnoreBody warns if matchers or filters depending on the response body are used with -ignore-body
+func warnIgnoreBody(parseOpts *ffuf.ConfigOptions, conf *ffuf.Config) {
+	bodyOptions := flagSet("ms") || flagSet("ml") || flagSet("mw") || parseOpts.Filter.Size != "" ||
+		parseOpts.Filter.Words != "" || parseOpts.Filter.Lines != "" || parseOpts.Filter.Similarity != ""
+	if conf.IgnoreBody && bodyOptions {
 		fmt.Printf("*** Warning: possible undesired combination of -ignore-body and the response options: fl,fs,fw,ml,ms and mw.\n")
 	}
-	return errs.ErrorOrNil()
 }
 
 func printSearchResults(conf *ffuf.Config, pos int, exectime time.Time, hash string) {
@@ -413,7 +365,13 @@ func printSearchResults(conf *ffuf.Config, pos int, exectime time.Time, hash str
 	inputdata := inp.Value()
 	inputdata["FFUFHASH"] = []byte(hash)
 	basereq := ffuf.BaseRequest(conf)
-	dummyrunner := runner.NewRunnerByName("simple", conf, false)
+	dummyrunner, rerr := runner.NewRunnerByName("simple", conf, false)
+	if rerr != nil {
+		fmt.Printf("-------------------------------------------\n")
+		fmt.Println("Encountered error that prevents reproduction of the request:")
+		fmt.Println(rerr)
+		return
+	}
 	ffufreq, _ := dummyrunner.Prepare(inputdata, &basereq)
 	rawreq, _ := dummyrunner.Dump(&ffufreq)
 	fmt.Printf("-------------------------------------------\n")
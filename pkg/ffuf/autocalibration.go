@@ -3,11 +3,9 @@ package ffuf
 import (
 	"encoding/json"
 	"fmt"
-	"log"
 	"math/rand"
 	"os"
 	"path/filepath"
-	"strconv"
 	"time"
 )
 
@@ -25,6 +23,11 @@ func (j *Job) autoCalibrationStrings() map[string][]string {
 
 	for _, strategy := range j.Config.AutoCalibrationStrategies {
 		jsonStrategy, err := os.ReadFile(filepath.Join(AUTOCALIBDIR, strategy+".json"))
+		if builtin, ok := defaultAutocalibrationStrategies()[strategy]; ok && os.IsNotExist(err) {
+			// The configuration directory is not set up, eg. when ffuf is used as a library
+			cInputs = mergeMaps(cInputs, builtin)
+			continue
+		}
 		if err != nil {
 			j.Output.Warning(fmt.Sprintf("Skipping strategy \"%s\" because of error: %s\n", strategy, err))
 			continue
@@ -43,17 +46,13 @@ func (j *Job) autoCalibrationStrings() map[string][]string {
 	return cInputs
 }
 
-func setupDefaultAutocalibrationStrategies() error {
+// defaultAutocalibrationStrategies returns the built-in autocalibration strategies by name
+func defaultAutocalibrationStrategies() map[string]AutocalibrationStrategy {
 	basic_strategy := AutocalibrationStrategy{
 		"basic_admin":  []string{"admin" + RandomString(16), "admin" + RandomString(8)},
 		"htaccess":     []string{".htaccess" + RandomString(16), ".htaccess" + RandomString(8)},
 		"basic_random": []string{RandomString(16), RandomString(8)},
 	}
-	basic_strategy_json, err := json.Marshal(basic_strategy)
-	if err != nil {
-		return err
-	}
-
 	advanced_strategy := AutocalibrationStrategy{
 		"basic_admin":  []string{"admin" + RandomString(16), "admin" + RandomString(8)},
 		"htaccess":     []string{".htaccess" + RandomString(16), ".htaccess" + RandomString(8)},
@@ -61,7 +60,16 @@ func setupDefaultAutocalibrationStrategies() error {
 		"admin_dir":    []string{"admin" + RandomString(16) + "/", "admin" + RandomString(8) + "/"},
 		"random_dir":   []string{RandomString(16) + "/", RandomString(8) + "/"},
 	}
-	advanced_strategy_json, err := json.Marshal(advanced_strategy)
+	return map[string]AutocalibrationStrategy{"basic": basic_strategy, "advanced": advanced_strategy}
+}
+
+func setupDefaultAutocalibrationStrategies() error {
+	strategies := defaultAutocalibrationStrategies()
+	basic_strategy_json, err := json.Marshal(strategies["basic"])
+	if err != nil {
+		return err
+	}
+	advanced_strategy_json, err := json.Marshal(strategies["advanced"])
 	if err != nil {
 		return err
 	}
@@ -82,18 +90,21 @@ func setupDefaultAutocalibrationStrategies() error {
 
 func (j *Job) calibrationRequest(inputs map[string][]byte) (Response, error) {
 	basereq := BaseRequest(j.Config)
+	if j.Session != nil {
+		basereq, _ = j.Session.Apply(&basereq)
+	}
 	req, err := j.Runner.Prepare(inputs, &basereq)
 	if err != nil {
 		j.Output.Error(fmt.Sprintf("Encountered an error while preparing autocalibration request: %s\n", err))
 		j.incError()
-		log.Printf("%s", err)
+		j.Logger.Printf("%s", err)
 		return Response{}, err
 	}
 	resp, err := j.Runner.Execute(&req)
 	if err != nil {
 		j.Output.Error(fmt.Sprintf("Encountered an error while executing autocalibration request: %s\n", err))
 		j.incError()
-		log.Printf("%s", err)
+		j.Logger.Printf("%s", err)
 		return Response{}, err
 	}
 	// Only calibrate on responses that would be matched otherwise
@@ -116,7 +127,7 @@ func (j *Job) CalibrateForHost(host string, baseinput map[string][]byte) error {
 	for k, v := range baseinput {
 		input[k] = v
 	}
-	for _, v := range cStrings {
+	for group, v := range cStrings {
 		responses := make([]Response, 0)
 		for _, cs := range v {
 			input[j.Config.AutoCalibrationKeyword] = []byte(cs)
@@ -125,13 +136,11 @@ func (j *Job) CalibrateForHost(host string, baseinput map[string][]byte) error {
 				continue
 			}
 			responses = append(responses, resp)
-			err = j.calibrateFilters(responses, true)
-			if err != nil {
-				j.Output.Error(fmt.Sprintf("%s", err))
-			}
 		}
+		_ = j.calibrateFilters(group, responses, true)
 	}
 	j.Config.MatcherManager.SetCalibratedForHost(host, true)
+	j.calibratedHosts = append(j.calibratedHosts, host)
 	return nil
 }
 
@@ -142,7 +151,7 @@ func (j *Job) Calibrate(input map[string][]byte) error {
 	}
 	cInputs := j.autoCalibrationStrings()
 
-	for _, v := range cInputs {
+	for group, v := range cInputs {
 		responses := make([]Response, 0)
 		for _, cs := range v {
 			input[j.Config.AutoCalibrationKeyword] = []byte(cs)
@@ -152,7 +161,7 @@ func (j *Job) Calibrate(input map[string][]byte) error {
 			}
 			responses = append(responses, resp)
 		}
-		_ = j.calibrateFilters(responses, false)
+		_ = j.calibrateFilters(group, responses, false)
 	}
 	j.Config.MatcherManager.SetCalibrated(true)
 	return nil
@@ -173,110 +182,173 @@ func (j *Job) CalibrateIfNeeded(host string, input map[string][]byte) error {
 	return j.Calibrate(input)
 }
 
-func (j *Job) calibrateFilters(responses []Response, perHost bool) error {
-	// Work down from the most specific common denominator
-	if len(responses) > 0 {
-		// Content length
-		baselineSize := responses[0].ContentLength
-		sizeMatch := true
-		for _, r := range responses {
-			if baselineSize != r.ContentLength {
-				sizeMatch = false
-			}
+// checkBaselineDrift sends a probe with a random input to detect if the responses of the target have changed since
+// the autocalibration, eg. because of a WAF or an expired session. The autocalibration is run again if the probe
+// would be reported as a result, and the job gets paused if that does not help.
+func (j *Job) checkBaselineDrift(baseinput map[string][]byte) {
+	if baseinput[j.Config.AutoCalibrationKeyword] == nil {
+		return
+	}
+	// Skip the check if the previous one is still running
+	if !j.driftMutex.TryLock() {
+		return
+	}
+	defer j.driftMutex.Unlock()
+	input := make(map[string][]byte)
+	for k, v := range baseinput {
+		input[k] = v
+	}
+	input[j.Config.AutoCalibrationKeyword] = []byte(RandomString(16))
+	resp, err := j.calibrationRequest(input)
+	if err != nil {
+		// The probe was filtered or failed
+		return
+	}
+	host := HostURLFromRequest(*resp.Request)
+	j.Output.Warning(fmt.Sprintf("Autocalibration baseline has changed, a random probe was matched: %s. Re-running autocalibration.", NewResponseCluster(&resp).Description()))
+	j.calibMutex.Lock()
+	if j.Config.AutoCalibrationPerHost {
+		j.Config.MatcherManager.SetCalibratedForHost(host, false)
+		_ = j.CalibrateForHost(host, input)
+	} else {
+		j.Config.MatcherManager.SetCalibrated(false)
+		_ = j.Calibrate(input)
+	}
+	j.calibMutex.Unlock()
+
+	input[j.Config.AutoCalibrationKeyword] = []byte(RandomString(16))
+	if _, err := j.calibrationRequest(input); err == nil {
+		if j.Config.Noninteractive {
+			j.Output.Warning("Random probes are still matched after re-running autocalibration, the results are likely false positives.")
+		} else {
+			j.Output.Warning("Random probes are still matched after re-running autocalibration. Pausing the job, press ENTER to enter interactive mode and \"resume\" to continue.")
+			j.Pause()
 		}
-		if sizeMatch {
-			if perHost {
-				// Check if already filtered
-				for _, f := range j.Config.MatcherManager.FiltersForDomain(HostURLFromRequest(*responses[0].Request)) {
-					match, _ := f.Filter(&responses[0])
-					if match {
-						// Already filtered
-						return nil
-					}
-				}
-				_ = j.Config.MatcherManager.AddPerDomainFilter(HostURLFromRequest(*responses[0].Request), "size", strconv.FormatInt(baselineSize, 10))
-				return nil
-			} else {
-				// Check if already filtered
-				for _, f := range j.Config.MatcherManager.GetFilters() {
-					match, _ := f.Filter(&responses[0])
-					if match {
-						// Already filtered
-						return nil
-					}
-				}
-				_ = j.Config.MatcherManager.AddFilter("size", strconv.FormatInt(baselineSize, 10), false)
-				return nil
+	}
+}
+
+// calibrateSimilarity adds the autocalibration responses as baselines for the similarity filter, if one is in use
+func (j *Job) calibrateSimilarity(responses []Response, perHost bool) {
+	for _, r := range responses {
+		hash := NewSimhash(r.Data).String()
+		if perHost {
+			host := HostURLFromRequest(*r.Request)
+			if j.Config.MatcherManager.FiltersForDomain(host)["similarity"] != nil {
+				_ = j.Config.MatcherManager.AddPerDomainFilter(host, "similarity", hash)
 			}
+		} else if j.Config.MatcherManager.GetFilters()["similarity"] != nil {
+			_ = j.Config.MatcherManager.AddFilter("similarity", hash, false)
 		}
+	}
+}
 
-		// Content words
-		baselineWords := responses[0].ContentWords
-		wordsMatch := true
-		for _, r := range responses {
-			if baselineWords != r.ContentWords {
-				wordsMatch = false
+// calibrateFilters groups the responses of an autocalibration strategy group to clusters of similar responses,
+// and adds a filter for each cluster that is not filtered already
+func (j *Job) calibrateFilters(group string, responses []Response, perHost bool) error {
+	j.calibrateSimilarity(responses, perHost)
+	if len(responses) == 0 {
+		return fmt.Errorf("No responses to calibrate with")
+	}
+	clusters := make([]ResponseCluster, 0)
+	// the first response of each cluster, used to check if the cluster is filtered already
+	samples := make([]Response, 0)
+	for i := range responses {
+		found := false
+		for c := range clusters {
+			if clusters[c].Similar(&responses[i]) {
+				clusters[c].Add(&responses[i])
+				found = true
+				break
 			}
 		}
-		if wordsMatch {
-			if perHost {
-				// Check if already filtered
-				for _, f := range j.Config.MatcherManager.FiltersForDomain(HostURLFromRequest(*responses[0].Request)) {
-					match, _ := f.Filter(&responses[0])
-					if match {
-						// Already filtered
-						return nil
-					}
-				}
-				_ = j.Config.MatcherManager.AddPerDomainFilter(HostURLFromRequest(*responses[0].Request), "word", strconv.FormatInt(baselineWords, 10))
-				return nil
-			} else {
-				// Check if already filtered
-				for _, f := range j.Config.MatcherManager.GetFilters() {
-					match, _ := f.Filter(&responses[0])
-					if match {
-						// Already filtered
-						return nil
-					}
-				}
-				_ = j.Config.MatcherManager.AddFilter("word", strconv.FormatInt(baselineWords, 10), false)
-				return nil
-			}
+		if !found {
+			clusters = append(clusters, NewResponseCluster(&responses[i]))
+			samples = append(samples, responses[i])
+		}
+	}
+	for i, c := range clusters {
+		host := HostURLFromRequest(*samples[i].Request)
+		filters := j.Config.MatcherManager.GetFilters()
+		if perHost {
+			filters = j.Config.MatcherManager.FiltersForDomain(host)
+		}
+		if j.isFiltered(filters, &samples[i]) {
+			continue
+		}
+		if perHost {
+			_ = j.Config.MatcherManager.AddPerDomainFilter(host, "calibration", c.String())
+			j.Output.Info(fmt.Sprintf("Autocalibration strategy \"%s\" learned a response cluster for %s: %s", group, host, c.Description()))
+		} else {
+			_ = j.Config.MatcherManager.AddFilter("calibration", c.String(), false)
+			j.Output.Info(fmt.Sprintf("Autocalibration strategy \"%s\" learned a response cluster: %s", group, c.Description()))
 		}
+	}
+	return nil
+}
 
-		// Content lines
-		baselineLines := responses[0].ContentLines
-		linesMatch := true
-		for _, r := range responses {
-			if baselineLines != r.ContentLines {
-				linesMatch = false
-			}
+// isFiltered checks if any of the filters matches the response
+func (j *Job) isFiltered(filters map[string]FilterProvider, resp *Response) bool {
+	for _, f := range filters {
+		match, _ := f.Filter(resp)
+		if match {
+			return true
+		}
+	}
+	return false
+}
+
+// CalibrationState is the serializable form of the filters of a job, including the autocalibrated ones
+type CalibrationState struct {
+	Calibrated  bool                         `json:"calibrated"`
+	Filters     map[string]string            `json:"filters"`
+	HostFilters map[string]map[string]string `json:"host_filters"`
+}
+
+// calibrationState collects the filters of the job and the hosts they were calibrated for
+func (j *Job) calibrationState() CalibrationState {
+	mm := j.Config.MatcherManager
+	state := CalibrationState{
+		Calibrated:  mm.Calibrated(),
+		Filters:     make(map[string]string),
+		HostFilters: make(map[string]map[string]string),
+	}
+	for name, f := range mm.GetFilters() {
+		state.Filters[name] = f.Repr()
+	}
+	j.calibMutex.Lock()
+	hosts := append([]string{}, j.calibratedHosts...)
+	j.calibMutex.Unlock()
+	for _, host := range hosts {
+		state.HostFilters[host] = make(map[string]string)
+		for name, f := range mm.FiltersForDomain(host) {
+			state.HostFilters[host][name] = f.Repr()
 		}
-		if linesMatch {
-			if perHost {
-				// Check if already filtered
-				for _, f := range j.Config.MatcherManager.FiltersForDomain(HostURLFromRequest(*responses[0].Request)) {
-					match, _ := f.Filter(&responses[0])
-					if match {
-						// Already filtered
-						return nil
-					}
-				}
-				_ = j.Config.MatcherManager.AddPerDomainFilter(HostURLFromRequest(*responses[0].Request), "line", strconv.FormatInt(baselineLines, 10))
-				return nil
-			} else {
-				// Check if already filtered
-				for _, f := range j.Config.MatcherManager.GetFilters() {
-					match, _ := f.Filter(&responses[0])
-					if match {
-						// Already filtered
-						return nil
-					}
-				}
-				_ = j.Config.MatcherManager.AddFilter("line", strconv.FormatInt(baselineLines, 10), false)
-				return nil
+	}
+	return state
+}
+
+// restoreCalibration sets up the filters of the job from a collected state, so the hosts are not calibrated again
+func (j *Job) restoreCalibration(state CalibrationState) {
+	mm := j.Config.MatcherManager
+	for name, value := range state.Filters {
+		_ = mm.AddFilter(name, value, true)
+	}
+	j.calibMutex.Lock()
+	defer j.calibMutex.Unlock()
+	for host, filters := range state.HostFilters {
+		current := mm.FiltersForDomain(host)
+		for name, value := range filters {
+			if f, ok := current[name]; ok && f.Repr() == value {
+				continue
 			}
+			_ = mm.AddPerDomainFilter(host, name, value)
 		}
+		if !mm.CalibratedForDomain(host) {
+			j.calibratedHosts = append(j.calibratedHosts, host)
+		}
+		mm.SetCalibratedForHost(host, true)
+	}
+	if state.Calibrated {
+		mm.SetCalibrated(true)
 	}
-	return fmt.Errorf("No common filtering values found")
 }
@@ -4,6 +4,9 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
 )
 
@@ -23,6 +26,8 @@ func (o *NullOutput) Warning(warnstring string)              {}
 func (o *NullOutput) Result(resp Response)                   {}
 func (o *NullOutput) PrintResult(res Result)                 {}
 func (o *NullOutput) SaveFile(filename, format string) error { return nil }
+func (o *NullOutput) GetResults() []Result                   { return []Result{} }
+func (o *NullOutput) SetResults(results []Result)            {}
 func (o *NullOutput) GetCurrentResults() []Result            { return o.Results }
 func (o *NullOutput) SetCurrentResults(results []Result)     { o.Results = results }
 func (o *NullOutput) Reset()                                 {}
@@ -106,3 +111,165 @@ func TestAutoCalibrationStrings(t *testing.T) {
 		t.Errorf("Expected malformed strategy to be skipped, but got %v", cInputs)
 	}
 }
+
+// testMatcherManager is a minimal MatcherManager supporting status matchers and autocalibration filters
+type testMatcherManager struct {
+	calibrated bool
+	matchers   map[string]FilterProvider
+	filters    map[string]FilterProvider
+}
+
+type testStatusMatcher struct {
+	status int64
+}
+
+func (m *testStatusMatcher) Filter(response *Response) (bool, error) {
+	return response.StatusCode == m.status, nil
+}
+func (m *testStatusMatcher) Repr() string        { return strconv.FormatInt(m.status, 10) }
+func (m *testStatusMatcher) ReprVerbose() string { return m.Repr() }
+
+type testClusterFilter struct {
+	clusters []ResponseCluster
+}
+
+func (f *testClusterFilter) Filter(response *Response) (bool, error) {
+	for _, c := range f.clusters {
+		if c.Match(response) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+func (f *testClusterFilter) Repr() string {
+	strval := make([]string, 0)
+	for _, c := range f.clusters {
+		strval = append(strval, c.String())
+	}
+	return strings.Join(strval, ",")
+}
+func (f *testClusterFilter) ReprVerbose() string { return "" }
+
+func newTestMatcherManager() *testMatcherManager {
+	return &testMatcherManager{
+		matchers: map[string]FilterProvider{"status": &testStatusMatcher{200}},
+		filters:  make(map[string]FilterProvider),
+	}
+}
+
+func (m *testMatcherManager) SetCalibrated(calibrated bool)                     { m.calibrated = calibrated }
+func (m *testMatcherManager) SetCalibratedForHost(host string, calibrated bool) {}
+func (m *testMatcherManager) AddPerDomainFilter(domain string, name string, option string) error {
+	return nil
+}
+func (m *testMatcherManager) RemoveFilter(name string)                    {}
+func (m *testMatcherManager) AddMatcher(name string, option string) error { return nil }
+func (m *testMatcherManager) GetFilters() map[string]FilterProvider       { return m.filters }
+func (m *testMatcherManager) GetMatchers() map[string]FilterProvider      { return m.matchers }
+func (m *testMatcherManager) FiltersForDomain(domain string) map[string]FilterProvider {
+	return m.filters
+}
+func (m *testMatcherManager) CalibratedForDomain(domain string) bool { return false }
+func (m *testMatcherManager) Calibrated() bool                       { return m.calibrated }
+func (m *testMatcherManager) AddFilter(name string, option string, replace bool) error {
+	f := &testClusterFilter{}
+	if m.filters[name] != nil && !replace {
+		f.clusters = append(f.clusters, m.filters[name].(*testClusterFilter).clusters...)
+	}
+	for _, value := range strings.Split(option, ",") {
+		c, err := ParseResponseCluster(value)
+		if err != nil {
+			return err
+		}
+		f.clusters = append(f.clusters, c)
+	}
+	m.filters[name] = f
+	return nil
+}
+
+// testRunner responds to all requests using the response function
+type testRunner struct {
+	mutex    sync.Mutex
+	response func(input string) string
+}
+
+func (r *testRunner) Prepare(input map[string][]byte, basereq *Request) (Request, error) {
+	req := CopyRequest(basereq)
+	req.Url = strings.ReplaceAll(req.Url, "FUZZ", string(input["FUZZ"]))
+	req.Input = input
+	return req, nil
+}
+
+func (r *testRunner) Execute(req *Request) (Response, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	body := r.response(string(req.Input["FUZZ"]))
+	return Response{StatusCode: 200, Request: req, Data: []byte(body), ContentLength: int64(len(body))}, nil
+}
+
+func (r *testRunner) Dump(req *Request) ([]byte, error) { return []byte{}, nil }
+
+func (r *testRunner) setResponse(response func(input string) string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.response = response
+}
+
+// warningOutput records the warnings
+type warningOutput struct {
+	NullOutput
+	warnings []string
+}
+
+func (o *warningOutput) Warning(warnstring string) { o.warnings = append(o.warnings, warnstring) }
+
+func TestCheckBaselineDrift(t *testing.T) {
+	runner := &testRunner{response: func(input string) string {
+		return "<html><body><h1>The page " + input + " was not found</h1><p>Please check the address and try again.</p></body></html>"
+	}}
+	output := &warningOutput{}
+	conf := &Config{
+		Url:                    "http://example.com/FUZZ",
+		AutoCalibration:        true,
+		AutoCalibrationKeyword: "FUZZ",
+		AutoCalibrationStrings: []string{"kdjfhgkwjehrgkjh", "qpwoeiru"},
+		MatcherManager:         newTestMatcherManager(),
+		MatcherMode:            "or",
+		FilterMode:             "or",
+	}
+	job := NewJob(conf)
+	job.Runner = runner
+	job.Output = output
+	input := map[string][]byte{"FUZZ": []byte("admin")}
+	_ = job.CalibrateIfNeeded("http://example.com", input)
+
+	job.checkBaselineDrift(input)
+	if len(output.warnings) != 0 {
+		t.Errorf("Expected no drift to be detected, got warnings %v", output.warnings)
+	}
+
+	// A WAF starts blocking the requests
+	runner.setResponse(func(input string) string {
+		return "<html><body><h1>Access denied</h1><p>Your request was blocked by the firewall. Incident id " + RandomString(12) + "</p></body></html>"
+	})
+	job.checkBaselineDrift(input)
+	if len(output.warnings) != 1 {
+		t.Errorf("Expected a warning about the changed baseline, got %v", output.warnings)
+	}
+	if c := conf.MatcherManager.GetFilters()["calibration"].(*testClusterFilter).clusters; len(c) != 2 {
+		t.Errorf("Expected autocalibration to learn the new baseline, got %d clusters", len(c))
+	}
+	if job.Paused {
+		t.Errorf("Expected the job not to be paused when the re-calibration succeeded")
+	}
+
+	// Every response is different, re-calibration does not help
+	runner.setResponse(func(input string) string {
+		return strings.Repeat(RandomString(10)+" ", len(input)) + strings.Repeat("\n", len(input))
+	})
+	job.checkBaselineDrift(input)
+	if len(output.warnings) != 3 || !job.Paused {
+		t.Errorf("Expected the job to be paused after a failed re-calibration, got warnings %v", output.warnings)
+	}
+	job.Resume()
+}
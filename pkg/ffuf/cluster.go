@@ -0,0 +1,190 @@
+package ffuf
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Minimum similarity of response bodies in percent to be considered belonging to the same cluster
+const CLUSTER_SIMILARITY = 90
+
+// ResponseCluster describes a group of similar responses learned from autocalibration requests. A response
+// belongs to the cluster if the status code and redirect location are equal, the body is similar and the
+// size, word and line counts are within the observed ranges, with some tolerance for dynamic content.
+type ResponseCluster struct {
+	Status   int64
+	Size     ValueRange
+	Words    ValueRange
+	Lines    ValueRange
+	Redirect string
+	Hash     Simhash
+}
+
+// responseMetrics holds the values compared against a ResponseCluster
+type responseMetrics struct {
+	status   int64
+	size     int64
+	words    int64
+	lines    int64
+	redirect string
+	hash     Simhash
+}
+
+func newResponseMetrics(status int64, data []byte, redirect string) responseMetrics {
+	return responseMetrics{
+		status:   status,
+		size:     int64(len(data)),
+		words:    int64(len(strings.Split(string(data), " "))),
+		lines:    int64(len(strings.Split(string(data), "\n"))),
+		redirect: redirect,
+		hash:     NewSimhash(data),
+	}
+}
+
+// clusterMetrics returns the metrics of the response as is, and with the request inputs removed from the body
+// and redirect location. The latter makes the metrics independent of the input for responses that reflect it.
+func clusterMetrics(resp *Response) []responseMetrics {
+	redirect := resp.GetRedirectLocation(false)
+	metrics := []responseMetrics{newResponseMetrics(resp.StatusCode, resp.Data, redirect)}
+	if resp.Request == nil || len(resp.Request.Input) == 0 {
+		return metrics
+	}
+	data := string(resp.Data)
+	for keyword, input := range resp.Request.Input {
+		if keyword == "FFUFHASH" || len(input) == 0 {
+			continue
+		}
+		data = strings.ReplaceAll(data, string(input), "")
+		redirect = strings.ReplaceAll(redirect, string(input), keyword)
+	}
+	return append(metrics, newResponseMetrics(resp.StatusCode, []byte(data), redirect))
+}
+
+// NewResponseCluster creates a cluster from a single response
+func NewResponseCluster(resp *Response) ResponseCluster {
+	m := clusterMetrics(resp)
+	n := m[len(m)-1]
+	return ResponseCluster{
+		Status:   n.status,
+		Size:     ValueRange{n.size, n.size},
+		Words:    ValueRange{n.words, n.words},
+		Lines:    ValueRange{n.lines, n.lines},
+		Redirect: n.redirect,
+		Hash:     n.hash,
+	}
+}
+
+// Add extends the cluster ranges with the response
+func (c *ResponseCluster) Add(resp *Response) {
+	m := clusterMetrics(resp)
+	n := m[len(m)-1]
+	c.Size = extendRange(c.Size, n.size)
+	c.Words = extendRange(c.Words, n.words)
+	c.Lines = extendRange(c.Lines, n.lines)
+}
+
+// Similar returns true if the response is similar enough to be added to the cluster
+func (c *ResponseCluster) Similar(resp *Response) bool {
+	for _, m := range clusterMetrics(resp) {
+		if m.status == c.Status && m.redirect == c.Redirect && c.Hash.Similarity(m.hash) >= CLUSTER_SIMILARITY {
+			return true
+		}
+	}
+	return false
+}
+
+// Match returns true if the response belongs to the cluster
+func (c *ResponseCluster) Match(resp *Response) bool {
+	for _, m := range clusterMetrics(resp) {
+		if m.status != c.Status || m.redirect != c.Redirect {
+			continue
+		}
+		if !inTolerance(c.Size, m.size) || !inTolerance(c.Words, m.words) || !inTolerance(c.Lines, m.lines) {
+			continue
+		}
+		if c.Size.Max > 0 && c.Hash.Similarity(m.hash) < CLUSTER_SIMILARITY {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+func extendRange(r ValueRange, value int64) ValueRange {
+	if value < r.Min {
+		r.Min = value
+	}
+	if value > r.Max {
+		r.Max = value
+	}
+	return r
+}
+
+// inTolerance checks if the value is within the range, widened by the range spread and 10% of the maximum
+func inTolerance(r ValueRange, value int64) bool {
+	tolerance := (r.Max - r.Min) + r.Max/10
+	return r.Min-tolerance <= value && value <= r.Max+tolerance
+}
+
+func rangeString(r ValueRange) string {
+	if r.Min == r.Max {
+		return strconv.FormatInt(r.Min, 10)
+	}
+	return fmt.Sprintf("%d-%d", r.Min, r.Max)
+}
+
+// String returns the cluster in a format that can be parsed with ParseResponseCluster
+func (c ResponseCluster) String() string {
+	return fmt.Sprintf("status=%d;size=%s;words=%s;lines=%s;redirect=%s;hash=%s",
+		c.Status, rangeString(c.Size), rangeString(c.Words), rangeString(c.Lines), url.QueryEscape(c.Redirect), c.Hash)
+}
+
+// Description returns a human readable description of the cluster
+func (c ResponseCluster) Description() string {
+	desc := fmt.Sprintf("status %d, size %s, words %s, lines %s", c.Status, rangeString(c.Size), rangeString(c.Words), rangeString(c.Lines))
+	if c.Redirect != "" {
+		desc += ", redirect to " + c.Redirect
+	}
+	return desc
+}
+
+// ParseResponseCluster parses a cluster from its string representation
+func ParseResponseCluster(value string) (ResponseCluster, error) {
+	var c ResponseCluster
+	var err error
+	seen := make(map[string]bool)
+	for _, field := range strings.Split(value, ";") {
+		kv := strings.SplitN(field, "=", 2)
+		if len(kv) != 2 {
+			return c, fmt.Errorf("invalid response cluster field: %s", field)
+		}
+		switch kv[0] {
+		case "status":
+			c.Status, err = strconv.ParseInt(kv[1], 10, 64)
+		case "size":
+			c.Size, err = ValueRangeFromString(kv[1])
+		case "words":
+			c.Words, err = ValueRangeFromString(kv[1])
+		case "lines":
+			c.Lines, err = ValueRangeFromString(kv[1])
+		case "redirect":
+			c.Redirect, err = url.QueryUnescape(kv[1])
+		case "hash":
+			c.Hash, err = ParseSimhash(kv[1])
+		default:
+			err = fmt.Errorf("unknown field")
+		}
+		if err != nil {
+			return c, fmt.Errorf("invalid response cluster field: %s", field)
+		}
+		seen[kv[0]] = true
+	}
+	for _, key := range []string{"status", "size", "words", "lines", "hash"} {
+		if !seen[key] {
+			return c, fmt.Errorf("response cluster is missing field: %s", key)
+		}
+	}
+	return c, nil
+}
@@ -0,0 +1,77 @@
+package ffuf
+
+import (
+	"fmt"
+	"testing"
+)
+
+func clusterTestResponse(status int64, input string, body string, location string) Response {
+	req := Request{Url: "http://example.com/" + input, Input: map[string][]byte{"FUZZ": []byte(input), "FFUFHASH": []byte("abc")}}
+	headers := map[string][]string{}
+	if location != "" {
+		headers["Location"] = []string{location}
+	}
+	return Response{StatusCode: status, Data: []byte(body), Headers: headers, Request: &req}
+}
+
+func notFoundPage(input string, token string) string {
+	return fmt.Sprintf("<html><body><h1>Sorry, /%s was not found</h1><p>The page you requested does not exist. "+
+		"Go back home or search again.</p><p>Request id %s</p></body></html>", input, token)
+}
+
+func TestResponseClusterMatch(t *testing.T) {
+	r1 := clusterTestResponse(200, "kdjfhgkwjehrgkjh", notFoundPage("kdjfhgkwjehrgkjh", "abcde fghij"), "")
+	r2 := clusterTestResponse(200, "qpwoeiru", notFoundPage("qpwoeiru", "klmno"), "")
+	c := NewResponseCluster(&r1)
+	if !c.Similar(&r2) {
+		t.Fatalf("Expected responses reflecting the input to be clustered together")
+	}
+	c.Add(&r2)
+
+	for i, test := range []struct {
+		resp  Response
+		match bool
+	}{
+		{clusterTestResponse(200, "a", notFoundPage("a", "pqrst"), ""), true},
+		{clusterTestResponse(200, "very/long/path/to/some/resource.php", notFoundPage("very/long/path/to/some/resource.php", "uvwxy z"), ""), true},
+		{clusterTestResponse(404, "admin", notFoundPage("admin", "pqrst"), ""), false},
+		{clusterTestResponse(200, "admin", "<html><body><h1>Welcome admin</h1><p>Dashboard with statistics and users</p></body></html>", ""), false},
+	} {
+		if match := c.Match(&test.resp); match != test.match {
+			t.Errorf("Cluster test %d: Was expecting match %t but got %t", i, test.match, match)
+		}
+	}
+}
+
+func TestResponseClusterRedirect(t *testing.T) {
+	r1 := clusterTestResponse(301, "kdjfhgkwjehrgkjh", "", "/kdjfhgkwjehrgkjh/")
+	c := NewResponseCluster(&r1)
+	if c.Redirect != "/FUZZ/" {
+		t.Errorf("Expected the input to be replaced in redirect location, got %s", c.Redirect)
+	}
+	r2 := clusterTestResponse(301, "backup", "", "/backup/")
+	if !c.Match(&r2) {
+		t.Errorf("Expected redirect reflecting the input to match")
+	}
+	r3 := clusterTestResponse(301, "backup", "", "/login")
+	if c.Match(&r3) {
+		t.Errorf("Expected redirect to a different location not to match")
+	}
+}
+
+func TestResponseClusterString(t *testing.T) {
+	r1 := clusterTestResponse(302, "abcdefgh", notFoundPage("abcdefgh", "x"), "/login?next=/abcdefgh,a;b")
+	r2 := clusterTestResponse(302, "ijklmnopqrst", notFoundPage("ijklmnopqrst", "x y z"), "/login?next=/ijklmnopqrst,a;b")
+	c := NewResponseCluster(&r1)
+	c.Add(&r2)
+	parsed, err := ParseResponseCluster(c.String())
+	if err != nil {
+		t.Fatalf("Failed to parse response cluster: %v", err)
+	}
+	if parsed != c {
+		t.Errorf("Expected response cluster %v after roundtrip, got %v", c, parsed)
+	}
+	if _, err := ParseResponseCluster("status=200;size=10"); err == nil {
+		t.Errorf("Expected an error for a response cluster with missing fields")
+	}
+}
@@ -8,30 +8,44 @@ type Config struct {
 	AutoCalibration           bool                  `json:"autocalibration"`
 	AutoCalibrationKeyword    string                `json:"autocalibration_keyword"`
 	AutoCalibrationPerHost    bool                  `json:"autocalibration_perhost"`
+	AutoCalibrationRecheck    int                   `json:"autocalibration_recheck"`
 	AutoCalibrationStrategies []string              `json:"autocalibration_strategies"`
 	AutoCalibrationStrings    []string              `json:"autocalibration_strings"`
 	Cancel                    context.CancelFunc    `json:"-"`
+	ChunkSize                 int                   `json:"chunk_size"`
 	Colors                    bool                  `json:"colors"`
 	CommandKeywords           []string              `json:"-"`
 	CommandLine               string                `json:"cmdline"`
 	ConfigFile                string                `json:"configfile"`
+	CookieJar                 string                `json:"cookie_jar"`
+	CookieJarFile             string                `json:"cookie_jar_file"`
+	Coordinator               string                `json:"coordinator"`
+	CoordinatorSecret         string                `json:"-"`
 	Context                   context.Context       `json:"-"`
+	CSRFKeyword               string                `json:"csrf_keyword"`
+	CSRFRule                  string                `json:"csrf_rule"`
+	CSRFUrl                   string                `json:"csrf_url"`
 	Data                      string                `json:"postdata"`
 	Debuglog                  string                `json:"debuglog"`
 	Delay                     optRange              `json:"delay"`
 	DirSearchCompat           bool                  `json:"dirsearch_compatibility"`
 	Encoders                  []string              `json:"encoders"`
+	ErrorFile                 string                `json:"errorfile"`
 	Extensions                []string              `json:"extensions"`
 	FilterMode                string                `json:"fmode"`
 	FollowRedirects           bool                  `json:"follow_redirects"`
+	HarAll                    bool                  `json:"har_all"`
 	Headers                   map[string]string     `json:"headers"`
 	IgnoreBody                bool                  `json:"ignorebody"`
 	IgnoreWordlistComments    bool                  `json:"ignore_wordlist_comments"`
+	InputCmdMode              string                `json:"cmd_inputmode"`
+	InputCmdNul               bool                  `json:"cmd_inputnul"`
 	InputMode                 string                `json:"inputmode"`
 	InputNum                  int                   `json:"cmd_inputnum"`
 	InputProviders            []InputProviderConfig `json:"inputproviders"`
 	InputShell                string                `json:"inputshell"`
 	Json                      bool                  `json:"json"`
+	MaskCharsets              []string              `json:"mask_charsets"`
 	MatcherManager            MatcherManager        `json:"matchers"`
 	MatcherMode               string                `json:"mmode"`
 	MaxTime                   int                   `json:"maxtime"`
@@ -41,30 +55,49 @@ type Config struct {
 	OutputDirectory           string                `json:"outputdirectory"`
 	OutputFile                string                `json:"outputfile"`
 	OutputFormat              string                `json:"outputformat"`
+	OutputProviders           []string              `json:"outputproviders"`
 	OutputSkipEmptyFile       bool                  `json:"OutputSkipEmptyFile"`
 	ProgressFrequency         int                   `json:"-"`
 	ProxyURL                  string                `json:"proxyurl"`
 	Quiet                     bool                  `json:"quiet"`
 	Rate                      int64                 `json:"rate"`
+	RateAdaptive              bool                  `json:"rate_adaptive"`
 	Raw                       bool                  `json:"raw"`
+	RawSocket                 bool                  `json:"raw_socket"`
 	Recursion                 bool                  `json:"recursion"`
+	ReplayErrors              string                `json:"replay_errors"`
 	RecursionDepth            int                   `json:"recursion_depth"`
 	RecursionStrategy         string                `json:"recursion_strategy"`
 	ReplayProxyURL            string                `json:"replayproxyurl"`
+	ResumeFile                string                `json:"resumefile"`
+	Retries                   int                   `json:"retries"`
+	RetryBackoff              float64               `json:"retry_backoff"`
+	RetryOnStatus             []ValueRange          `json:"retry_on_status"`
 	RequestFile               string                `json:"requestfile"`
 	RequestProto              string                `json:"requestproto"`
+	RequestTemplate           []byte                `json:"-"`
 	ScraperFile               string                `json:"scraperfile"`
 	Scrapers                  string                `json:"scrapers"`
+	SessionExtract            []string              `json:"session_extract"`
+	SessionMatchRegexp        string                `json:"session_match_regexp"`
+	SessionMatchStatus        []ValueRange          `json:"session_match_status"`
+	SessionRequest            *Request              `json:"-"`
+	SessionRequestFile        string                `json:"session_request"`
 	SNI                       string                `json:"sni"`
+	SqliteBodies              bool                  `json:"sqlite_bodies"`
 	StopOn403                 bool                  `json:"stop_403"`
 	StopOnAll                 bool                  `json:"stop_all"`
 	StopOnErrors              bool                  `json:"stop_errors"`
+	StreamWordlists           bool                  `json:"stream_wordlists"`
 	Threads                   int                   `json:"threads"`
+	TargetFile                string                `json:"target_file"`
+	Targets                   []string              `json:"targets"`
 	Timeout                   int                   `json:"timeout"`
 	Url                       string                `json:"url"`
 	Verbose                   bool                  `json:"verbose"`
 	Wordlists                 []string              `json:"wordlists"`
 	Http2                     bool                  `json:"http2"`
+	Http3                     bool                  `json:"http3"`
 	ClientCert                string                `json:"client-cert"`
 	ClientKey                 string                `json:"client-key"`
 }
@@ -74,6 +107,7 @@ type InputProviderConfig struct {
 	Keyword  string `json:"keyword"`
 	Value    string `json:"value"`
 	Encoders string `json:"encoders"`
+	Rules    string `json:"rules"`
 	Template string `json:"template"` // the templating string used for sniper mode (usually "§")
 }
 
@@ -85,47 +119,78 @@ func NewConfig(ctx context.Context, cancel context.CancelFunc) Config {
 	conf.CommandKeywords = make([]string, 0)
 	conf.Context = ctx
 	conf.Cancel = cancel
+	conf.ChunkSize = 1000
+	conf.Coordinator = ""
+	conf.CoordinatorSecret = ""
+	conf.CookieJar = ""
+	conf.CookieJarFile = ""
+	conf.CSRFKeyword = "CSRF"
+	conf.CSRFRule = ""
+	conf.CSRFUrl = ""
 	conf.Data = ""
 	conf.Debuglog = ""
 	conf.Delay = optRange{0, 0, false, false}
 	conf.DirSearchCompat = false
 	conf.Encoders = make([]string, 0)
+	conf.ErrorFile = ""
 	conf.Extensions = make([]string, 0)
 	conf.FilterMode = "or"
 	conf.FollowRedirects = false
+	conf.HarAll = false
 	conf.Headers = make(map[string]string)
 	conf.IgnoreWordlistComments = false
+	conf.InputCmdMode = "exec"
+	conf.InputCmdNul = false
 	conf.InputMode = "clusterbomb"
 	conf.InputNum = 0
 	conf.InputShell = ""
 	conf.InputProviders = make([]InputProviderConfig, 0)
 	conf.Json = false
+	conf.MaskCharsets = make([]string, 0)
 	conf.MatcherMode = "or"
 	conf.MaxTime = 0
 	conf.MaxTimeJob = 0
 	conf.Method = "GET"
 	conf.Noninteractive = false
+	conf.OutputProviders = make([]string, 0)
 	conf.ProgressFrequency = 125
 	conf.ProxyURL = ""
 	conf.Quiet = false
 	conf.Rate = 0
+	conf.RateAdaptive = false
 	conf.Raw = false
+	conf.RawSocket = false
 	conf.Recursion = false
 	conf.RecursionDepth = 0
 	conf.RecursionStrategy = "default"
+	conf.ReplayErrors = ""
 	conf.RequestFile = ""
+	conf.ResumeFile = ""
+	conf.Retries = 1
+	conf.RetryBackoff = 0
+	conf.RetryOnStatus = make([]ValueRange, 0)
 	conf.RequestProto = "https"
 	conf.SNI = ""
+	conf.SqliteBodies = false
 	conf.ScraperFile = ""
 	conf.Scrapers = "all"
+	conf.SessionExtract = []string{}
+	conf.SessionMatchRegexp = ""
+	conf.SessionMatchStatus = make([]ValueRange, 0)
+	conf.SessionRequest = nil
+	conf.SessionRequestFile = ""
 	conf.StopOn403 = false
 	conf.StopOnAll = false
 	conf.StopOnErrors = false
+	conf.StreamWordlists = false
+	conf.TargetFile = ""
+	conf.Targets = []string{}
 	conf.Timeout = 10
 	conf.Url = ""
 	conf.Verbose = false
 	conf.Wordlists = []string{}
 	conf.Http2 = false
+	conf.Http3 = false
 	return conf
 }
 
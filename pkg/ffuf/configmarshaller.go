@@ -8,7 +8,12 @@ import (
 func (c *Config) ToOptions() ConfigOptions {
 	o := ConfigOptions{}
 	// HTTP options
+	o.HTTP.CookieJar = c.CookieJar
+	o.HTTP.CookieJarFile = c.CookieJarFile
 	o.HTTP.Cookies = []string{}
+	o.HTTP.CSRFKeyword = c.CSRFKeyword
+	o.HTTP.CSRFRule = c.CSRFRule
+	o.HTTP.CSRFURL = c.CSRFUrl
 	o.HTTP.Data = c.Data
 	o.HTTP.FollowRedirects = c.FollowRedirects
 	o.HTTP.Headers = make([]string, 0)
@@ -19,22 +24,47 @@ func (c *Config) ToOptions() ConfigOptions {
 	o.HTTP.Method = c.Method
 	o.HTTP.ProxyURL = c.ProxyURL
 	o.HTTP.Raw = c.Raw
+	o.HTTP.RawSocket = c.RawSocket
 	o.HTTP.Recursion = c.Recursion
 	o.HTTP.RecursionDepth = c.RecursionDepth
 	o.HTTP.RecursionStrategy = c.RecursionStrategy
 	o.HTTP.ReplayProxyURL = c.ReplayProxyURL
+	o.HTTP.Retries = c.Retries
+	o.HTTP.RetryBackoff = c.RetryBackoff
+	retryon := make([]string, 0)
+	for _, vr := range c.RetryOnStatus {
+		retryon = append(retryon, rangeString(vr))
+	}
+	o.HTTP.RetryOn = strings.Join(retryon, ",")
+	o.HTTP.SessionExtract = c.SessionExtract
+	o.HTTP.SessionMatchRegexp = c.SessionMatchRegexp
+	sessionmc := make([]string, 0)
+	for _, vr := range c.SessionMatchStatus {
+		sessionmc = append(sessionmc, rangeString(vr))
+	}
+	o.HTTP.SessionMatchStatus = strings.Join(sessionmc, ",")
+	o.HTTP.SessionRequest = c.SessionRequestFile
 	o.HTTP.SNI = c.SNI
 	o.HTTP.Timeout = c.Timeout
 	o.HTTP.URL = c.Url
+	if c.TargetFile != "" {
+		// The URL is the current target of the list
+		o.HTTP.URL = ""
+	}
+	o.HTTP.URLList = c.TargetFile
 	o.HTTP.Http2 = c.Http2
+	o.HTTP.Http3 = c.Http3
 
 	o.General.AutoCalibration = c.AutoCalibration
 	o.General.AutoCalibrationKeyword = c.AutoCalibrationKeyword
 	o.General.AutoCalibrationPerHost = c.AutoCalibrationPerHost
+	o.General.AutoCalibrationRecheck = c.AutoCalibrationRecheck
 	o.General.AutoCalibrationStrategies = c.AutoCalibrationStrategies
 	o.General.AutoCalibrationStrings = c.AutoCalibrationStrings
+	o.General.ChunkSize = c.ChunkSize
 	o.General.Colors = c.Colors
 	o.General.ConfigFile = ""
+	o.General.Coordinator = c.Coordinator
 	if c.Delay.HasDelay {
 		if c.Delay.IsRange {
 			o.General.Delay = fmt.Sprintf("%.2f-%.2f", c.Delay.Min, c.Delay.Max)
@@ -50,6 +80,8 @@ func (c *Config) ToOptions() ConfigOptions {
 	o.General.Noninteractive = c.Noninteractive
 	o.General.Quiet = c.Quiet
 	o.General.Rate = int(c.Rate)
+	o.General.RateAdaptive = c.RateAdaptive
+	o.General.Resume = c.ResumeFile
 	o.General.ScraperFile = c.ScraperFile
 	o.General.Scrapers = c.Scrapers
 	o.General.StopOn403 = c.StopOn403
@@ -59,8 +91,16 @@ func (c *Config) ToOptions() ConfigOptions {
 	o.General.Verbose = c.Verbose
 
 	o.Input.DirSearchCompat = c.DirSearchCompat
+	o.Input.Encoders = []string{}
+	for _, v := range c.InputProviders {
+		if v.Encoders != "" {
+			o.Input.Encoders = append(o.Input.Encoders, fmt.Sprintf("%s:%s", v.Keyword, v.Encoders))
+		}
+	}
 	o.Input.Extensions = strings.Join(c.Extensions, ",")
 	o.Input.IgnoreWordlistComments = c.IgnoreWordlistComments
+	o.Input.InputCmdMode = c.InputCmdMode
+	o.Input.InputCmdNul = c.InputCmdNul
 	o.Input.InputMode = c.InputMode
 	o.Input.InputNum = c.InputNum
 	o.Input.InputShell = c.InputShell
@@ -70,19 +110,43 @@ func (c *Config) ToOptions() ConfigOptions {
 			o.Input.Inputcommands = append(o.Input.Inputcommands, fmt.Sprintf("%s:%s", v.Value, v.Keyword))
 		}
 	}
+	o.Input.MaskCharsets = c.MaskCharsets
+	o.Input.Masks = []string{}
+	o.Input.Ranges = []string{}
+	for _, v := range c.InputProviders {
+		if v.Name == "mask" {
+			o.Input.Masks = append(o.Input.Masks, fmt.Sprintf("%s:%s", v.Value, v.Keyword))
+		}
+		if v.Name == "range" {
+			o.Input.Ranges = append(o.Input.Ranges, fmt.Sprintf("%s:%s", v.Value, v.Keyword))
+		}
+	}
+	o.Input.ReplayErrors = c.ReplayErrors
+	o.Input.Rules = []string{}
+	for _, v := range c.InputProviders {
+		if v.Rules != "" {
+			o.Input.Rules = append(o.Input.Rules, fmt.Sprintf("%s:%s", v.Keyword, v.Rules))
+		}
+	}
 	o.Input.Request = c.RequestFile
 	o.Input.RequestProto = c.RequestProto
 	o.Input.Wordlists = c.Wordlists
+	o.Input.WordlistStream = c.StreamWordlists
 
 	o.Output.DebugLog = c.Debuglog
+	o.Output.ErrorFile = c.ErrorFile
+	o.Output.HarAll = c.HarAll
 	o.Output.OutputDirectory = c.OutputDirectory
 	o.Output.OutputFile = c.OutputFile
 	o.Output.OutputFormat = c.OutputFormat
+	o.Output.OutputProviders = c.OutputProviders
 	o.Output.OutputSkipEmptyFile = c.OutputSkipEmptyFile
+	o.Output.SqliteBodies = c.SqliteBodies
 
 	o.Filter.Mode = c.FilterMode
 	o.Filter.Lines = ""
 	o.Filter.Regexp = ""
+	o.Filter.Similarity = ""
 	o.Filter.Size = ""
 	o.Filter.Status = ""
 	o.Filter.Time = ""
@@ -93,13 +157,15 @@ func (c *Config) ToOptions() ConfigOptions {
 			o.Filter.Lines = filter.Repr()
 		case "regexp":
 			o.Filter.Regexp = filter.Repr()
+		case "similarity":
+			o.Filter.Similarity = filter.Repr()
 		case "size":
 			o.Filter.Size = filter.Repr()
 		case "status":
 			o.Filter.Status = filter.Repr()
 		case "time":
 			o.Filter.Time = filter.Repr()
-		case "words":
+		case "word":
 			o.Filter.Words = filter.Repr()
 		}
 	}
@@ -122,7 +188,7 @@ func (c *Config) ToOptions() ConfigOptions {
 			o.Matcher.Status = filter.Repr()
 		case "time":
 			o.Matcher.Time = filter.Repr()
-		case "words":
+		case "word":
 			o.Matcher.Words = filter.Repr()
 		}
 	}
@@ -0,0 +1,43 @@
+package ffuf
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReadCookieFile(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "cookies.txt")
+	content := "# Netscape HTTP Cookie File\n\n" +
+		"example.com\tFALSE\t/\tFALSE\t0\tsid\tabc123\n" +
+		".example.com\tTRUE\t/app\tTRUE\t1893456000\tpref\tdark\n" +
+		"#HttpOnly_api.example.com\tTRUE\t/\tFALSE\t0\tauth\ttoken\r\n"
+	if err := os.WriteFile(filename, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write cookie file: %v", err)
+	}
+	cookies, err := ReadCookieFile(filename)
+	if err != nil {
+		t.Fatalf("Failed to read cookie file: %v", err)
+	}
+	if len(cookies) != 3 {
+		t.Fatalf("Expected 3 cookies, got %d", len(cookies))
+	}
+	if c := cookies[0]; c.Name != "sid" || c.Value != "abc123" || c.Domain != "example.com" || !c.Expires.IsZero() {
+		t.Errorf("Unexpected host-only session cookie: %v", c)
+	}
+	if c := cookies[1]; c.Domain != ".example.com" || c.Path != "/app" || !c.Secure || c.Expires.Unix() != 1893456000 {
+		t.Errorf("Unexpected secure domain cookie: %v", c)
+	}
+	if c := cookies[2]; c.Name != "auth" || c.Value != "token" || !c.HttpOnly || c.Domain != ".api.example.com" {
+		t.Errorf("Unexpected HttpOnly cookie: %v", c)
+	}
+
+	_ = os.WriteFile(filename, []byte("example.com\tFALSE\t/\tFALSE\tnever\tsid\tabc\n"), 0644)
+	if _, err := ReadCookieFile(filename); err == nil {
+		t.Errorf("Expected an error for an invalid expiry")
+	}
+	_ = os.WriteFile(filename, []byte("example.com sid abc\n"), 0644)
+	if _, err := ReadCookieFile(filename); err == nil {
+		t.Errorf("Expected an error for a line without tab separated fields")
+	}
+}
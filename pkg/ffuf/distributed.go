@@ -0,0 +1,747 @@
+package ffuf
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// DISTRIBUTED_REPORT_INTERVAL is how often the workers send the progress and the results of a task to the coordinator
+const DISTRIBUTED_REPORT_INTERVAL = time.Second
+
+// DISTRIBUTED_LEASE is how long a task stays assigned to a worker without a report, before it is handed to another one
+const DISTRIBUTED_LEASE = 30 * time.Second
+
+// DISTRIBUTED_POLL_INTERVAL is how often an idle worker asks the coordinator for a new task
+const DISTRIBUTED_POLL_INTERVAL = 500 * time.Millisecond
+
+// COORDINATOR_SECRET_ENV is the environment variable the coordinator and the workers read the shared secret from
+const COORDINATOR_SECRET_ENV = "FFUF_COORDINATOR_SECRET"
+
+// DistributedTask is a chunk of positions of a queued job, handed out by the coordinator to a worker. Calibration
+// holds the filters autocalibrated by the coordinator, for the workers to use instead of calibrating on their own.
+type DistributedTask struct {
+	ID           int              `json:"id"`
+	Options      ConfigOptions    `json:"options"`
+	Jobhash      string           `json:"jobhash"`
+	Url          string           `json:"url"`
+	Request      Request          `json:"request"`
+	Depth        int              `json:"depth"`
+	Start        int              `json:"start"`
+	End          int              `json:"end"`
+	RecordErrors bool             `json:"record_errors"`
+	Calibration  CalibrationState `json:"calibration"`
+}
+
+// DistributedResult is a result found by a worker, with the response data needed by the output of the coordinator
+type DistributedResult struct {
+	Result
+	Headers    map[string][]string `json:"headers"`
+	Raw        string              `json:"raw"`
+	RequestRaw string              `json:"request_raw"`
+}
+
+// DistributedMessage is an info, warning or error message printed by the job of a worker
+type DistributedMessage struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// DistributedReport is sent by a worker while running a task, and once the task is done. Stopped is set when the
+// job of the worker hit a stopping condition, eg. -sf
+type DistributedReport struct {
+	Worker       string               `json:"worker"`
+	TaskID       int                  `json:"task_id"`
+	Done         bool                 `json:"done"`
+	Stopped      bool                 `json:"stopped"`
+	Requests     int                  `json:"requests"`
+	Errors       int                  `json:"errors"`
+	Retries      int                  `json:"retries"`
+	Failed       int                  `json:"failed"`
+	Results      []DistributedResult  `json:"results"`
+	Messages     []DistributedMessage `json:"messages"`
+	ErrorRecords []ErrorRecord        `json:"error_records"`
+	QueueJobs    []ResumeQueueJob     `json:"queuejobs"`
+}
+
+// DistributedReply is the answer of the coordinator to a report. Stop tells the worker to abandon the task.
+type DistributedReply struct {
+	Stop bool `json:"stop"`
+}
+
+// taskLease is a task handed out to a worker. The counters and the failed requests reported for the task are only
+// merged to the job once the task is done, as the positions of an expired lease are run again by another worker.
+type taskLease struct {
+	task         DistributedTask
+	worker       string
+	deadline     time.Time
+	requests     int
+	errors       int
+	retries      int
+	failed       int
+	errorRecords []ErrorRecord
+}
+
+// coordinator hands out the tasks of the running job to the workers, and merges their reports to the job
+type coordinator struct {
+	job       *Job
+	listener  net.Listener
+	server    *http.Server
+	options   ConfigOptions
+	secret    string
+	mutex     sync.Mutex
+	pending   []DistributedTask
+	queued    []QueueJob
+	leases    map[int]*taskLease
+	nextID    int
+	firstTask int
+	done      bool
+	stopped   string
+	reported  map[int]bool
+	messages  map[string]bool
+	workers   map[string]bool
+}
+
+// startCoordinator starts listening for the workers on the address of -coordinator
+func (j *Job) startCoordinator() error {
+	ln, err := net.Listen("tcp", j.Config.Coordinator)
+	if err != nil {
+		return err
+	}
+	secret := j.Config.CoordinatorSecret
+	if secret == "" {
+		random := make([]byte, 16)
+		if _, err := rand.Read(random); err != nil {
+			ln.Close()
+			return err
+		}
+		secret = hex.EncodeToString(random)
+	}
+	c := &coordinator{
+		job:      j,
+		listener: ln,
+		secret:   secret,
+		leases:   make(map[int]*taskLease),
+		reported: make(map[int]bool),
+		messages: make(map[string]bool),
+		workers:  make(map[string]bool),
+	}
+	// The workers send everything back to the coordinator instead of writing files or asking for input, and the
+	// coordinator keeps track of the running time
+	c.options = j.Config.ToOptions()
+	c.options.General.Coordinator = ""
+	c.options.General.CoordinatorSecret = ""
+	c.options.General.MaxTime = 0
+	c.options.General.MaxTimeJob = 0
+	// The autocalibration is run by the coordinator, and the filters are shipped with the tasks
+	c.options.General.AutoCalibrationRecheck = 0
+	c.options.General.Noninteractive = true
+	c.options.General.Quiet = true
+	c.options.General.Resume = ""
+	c.options.Output.OutputFile = ""
+	c.options.Output.OutputDirectory = ""
+	c.options.Output.OutputProviders = []string{}
+	c.options.Output.ErrorFile = ""
+	c.options.Output.HarAll = false
+	c.options.Output.SqliteBodies = false
+	mux := http.NewServeMux()
+	mux.HandleFunc("/task", c.authorized(c.handleTask))
+	mux.HandleFunc("/report", c.authorized(c.handleReport))
+	c.server = &http.Server{Handler: mux}
+	go func() {
+		_ = c.server.Serve(ln)
+	}()
+	j.coordinator = c
+	if j.Config.CoordinatorSecret == "" {
+		j.Output.Info(fmt.Sprintf("Waiting for workers, start them with: %s=%s ffuf worker -coordinator http://%s", COORDINATOR_SECRET_ENV, secret, ln.Addr()))
+	} else {
+		j.Output.Info(fmt.Sprintf("Waiting for workers, start them with: ffuf worker -coordinator http://%s, using the same secret", ln.Addr()))
+	}
+	return nil
+}
+
+// close tells the workers that the job is done and stops the coordinator
+func (c *coordinator) close() {
+	c.mutex.Lock()
+	c.done = true
+	c.mutex.Unlock()
+	// Give the idle workers a chance to hear that there is nothing left to do
+	time.Sleep(2 * DISTRIBUTED_POLL_INTERVAL)
+	_ = c.server.Close()
+}
+
+// Addr returns the address the coordinator is listening on
+func (c *coordinator) Addr() string {
+	return c.listener.Addr().String()
+}
+
+// authorized rejects the requests that do not carry the shared secret. The tasks contain the full options of the
+// job, including the headers and the session requests, and the reports are merged to its results.
+func (c *coordinator) authorized(handler http.HandlerFunc) http.HandlerFunc {
+	expected := []byte("Bearer " + c.secret)
+	return func(w http.ResponseWriter, r *http.Request) {
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), expected) != 1 {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		handler(w, r)
+	}
+}
+
+func (c *coordinator) handleTask(w http.ResponseWriter, r *http.Request) {
+	worker := r.URL.Query().Get("worker")
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if !c.workers[worker] {
+		c.workers[worker] = true
+		c.job.Output.Info(fmt.Sprintf("Worker %s connected", worker))
+	}
+	if c.done {
+		w.WriteHeader(http.StatusGone)
+		return
+	}
+	if len(c.pending) == 0 || c.job.isPaused() {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	task := c.pending[0]
+	c.pending = c.pending[1:]
+	c.leases[task.ID] = &taskLease{task: task, worker: worker, deadline: time.Now().Add(DISTRIBUTED_LEASE)}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(task)
+}
+
+func (c *coordinator) handleReport(w http.ResponseWriter, r *http.Request) {
+	var rep DistributedReport
+	if err := json.NewDecoder(r.Body).Decode(&rep); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	j := c.job
+	lease, ok := c.leases[rep.TaskID]
+	current := ok && lease.worker == rep.Worker
+	if current {
+		lease.deadline = time.Now().Add(DISTRIBUTED_LEASE)
+		lease.requests += rep.Requests
+		lease.errors += rep.Errors
+		lease.retries += rep.Retries
+		lease.failed += rep.Failed
+		lease.errorRecords = append(lease.errorRecords, rep.ErrorRecords...)
+		if rep.Done {
+			delete(c.leases, rep.TaskID)
+			j.ErrorMutex.Lock()
+			j.Counter += lease.requests
+			j.ErrorCounter += lease.errors
+			j.RetryCounter += lease.retries
+			j.FailedCounter += lease.failed
+			j.ErrorMutex.Unlock()
+			if rep.TaskID >= c.firstTask {
+				j.errorLogMutex.Lock()
+				for _, rec := range lease.errorRecords {
+					j.writeErrorRecord(rec)
+				}
+				j.errorLogMutex.Unlock()
+			}
+		}
+		if rep.Stopped && c.stopped == "" {
+			// The stopping conditions apply to the whole job, which is stopped by the main loop
+			c.stopped = fmt.Sprintf("Worker %s hit a stopping condition, exiting.", rep.Worker)
+		}
+	}
+	for _, m := range rep.Messages {
+		key := m.Type + m.Text
+		if c.messages[key] {
+			continue
+		}
+		c.messages[key] = true
+		text := fmt.Sprintf("[%s] %s", rep.Worker, m.Text)
+		switch m.Type {
+		case "error":
+			j.Output.Error(text)
+		case "warning":
+			j.Output.Warning(text)
+		default:
+			j.Output.Info(text)
+		}
+	}
+	// Results of a task of a previous queued job, or of a position that was reported by another worker already
+	// after the lease expired, are dropped
+	if rep.TaskID >= c.firstTask {
+		for _, res := range rep.Results {
+			if c.reported[res.Position] {
+				continue
+			}
+			c.reported[res.Position] = true
+			j.Output.Result(res.response())
+		}
+	}
+	// The job queue belongs to the main loop, which picks the new jobs up when the current one is done
+	for _, qj := range rep.QueueJobs {
+		c.queued = append(c.queued, QueueJob{Url: qj.Url, depth: qj.Depth, req: qj.Request})
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(DistributedReply{Stop: c.stopped != "" || (!current && !rep.Done)})
+}
+
+// expireLeases hands the tasks of the workers that have not reported in time to the other workers
+func (c *coordinator) expireLeases() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for id, lease := range c.leases {
+		if time.Now().After(lease.deadline) {
+			delete(c.leases, id)
+			c.pending = append([]DistributedTask{lease.task}, c.pending...)
+			c.job.Output.Warning(fmt.Sprintf("Worker %s did not report on positions %d-%d, handing them to another worker", lease.worker, lease.task.Start, lease.task.End))
+		}
+	}
+}
+
+// stopReason returns the error of a worker that hit a stopping condition, or an empty string
+func (c *coordinator) stopReason() string {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.stopped
+}
+
+// finished tells if all of the tasks of the current queued job are done
+func (c *coordinator) finished() bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return len(c.pending) == 0 && len(c.leases) == 0
+}
+
+// startDistributedExecution splits the current queued job to tasks for the workers and waits for them to finish
+func (j *Job) startDistributedExecution() {
+	c := j.coordinator
+	j.printQueueJob()
+	queuejob := j.currentQueueJob()
+	total := j.Input.Total()
+	j.calibrateQueueJob(queuejob)
+	calibration := j.calibrationState()
+	c.mutex.Lock()
+	c.firstTask = c.nextID + 1
+	c.reported = make(map[int]bool)
+	for start := 1; start <= total; start += j.Config.ChunkSize {
+		end := start + j.Config.ChunkSize - 1
+		if end > total {
+			end = total
+		}
+		c.nextID++
+		c.pending = append(c.pending, DistributedTask{
+			ID:           c.nextID,
+			Options:      c.options,
+			Jobhash:      j.Jobhash,
+			Url:          queuejob.Url,
+			Request:      queuejob.req,
+			Depth:        queuejob.depth,
+			Start:        start,
+			End:          end,
+			RecordErrors: j.Config.ErrorFile != "",
+			Calibration:  calibration,
+		})
+	}
+	c.mutex.Unlock()
+
+	for !c.finished() && !j.queueSkipped() {
+		if reason := c.stopReason(); reason != "" {
+			j.Error = reason
+			j.Stop()
+		}
+		j.CheckStop()
+		if !j.isRunning() || !j.isRunningJob() {
+			j.Output.Warning(j.Error)
+			break
+		}
+		c.expireLeases()
+		j.updateProgress()
+		time.Sleep(time.Duration(j.Config.ProgressFrequency) * time.Millisecond)
+	}
+
+	// Drop the tasks that are left if the job was stopped or skipped, the workers get told to stop on their next report
+	c.mutex.Lock()
+	c.pending = make([]DistributedTask, 0)
+	c.leases = make(map[int]*taskLease)
+	queued := c.queued
+	c.queued = make([]QueueJob, 0)
+	c.mutex.Unlock()
+	for _, qj := range queued {
+		if !j.queued(qj.Url) {
+			j.addQueueJob(qj)
+		}
+	}
+	j.updateProgress()
+}
+
+// calibrateQueueJob runs the autocalibration for a queued job on the coordinator, using the first input of the job
+// the same way as the first request of a job would
+func (j *Job) calibrateQueueJob(queuejob QueueJob) {
+	if !j.Config.AutoCalibration {
+		return
+	}
+	j.Input.Reset()
+	if !j.Input.Next() {
+		return
+	}
+	input := j.Input.Value()
+	j.Input.Reset()
+	req, err := j.Runner.Prepare(input, &queuejob.req)
+	if err != nil {
+		j.Output.Error(fmt.Sprintf("Encountered an error while preparing autocalibration request: %s\n", err))
+		return
+	}
+	_ = j.CalibrateIfNeeded(HostURLFromRequest(req), input)
+}
+
+// rangeInput restricts an InputProvider to the positions from start to end
+type rangeInput struct {
+	InputProvider
+	start int
+	end   int
+}
+
+// Next advances the underlying InputProvider until the end of the range
+func (r *rangeInput) Next() bool {
+	if r.InputProvider.Position() >= r.end {
+		return false
+	}
+	return r.InputProvider.Next()
+}
+
+// Reset moves the underlying InputProvider to the beginning of the range
+func (r *rangeInput) Reset() {
+	r.InputProvider.Reset()
+	r.InputProvider.SetPosition(r.start)
+}
+
+func (r *rangeInput) Total() int {
+	return r.end - r.start + 1
+}
+
+// RunChunk runs the positions of a task handed out by the coordinator, and returns the recursion jobs it found
+func (j *Job) RunChunk(task DistributedTask) []QueueJob {
+	if j.chunkInput == nil {
+		// First task of the job
+		j.chunkInput = j.Input
+		j.startTime = time.Now()
+		if j.Session != nil {
+			if err := j.Session.Login(j.Runner); err != nil {
+				j.Output.Error(fmt.Sprintf("Could not log in with the session request: %s", err))
+				return nil
+			}
+		}
+	}
+	j.stateMutex.Lock()
+	j.Running = true
+	j.RunningJob = true
+	j.stateMutex.Unlock()
+	j.Jobhash = task.Jobhash
+	j.Config.Url = task.Url
+	j.currentDepth = task.Depth
+	j.currentTemplate = requestTemplateHash(task.Request)
+	j.queuejobs = []QueueJob{{Url: task.Url, depth: task.Depth, req: task.Request}}
+	j.queuepos = 1
+	j.Input = j.chunkInput
+	j.activateKeywords(task.Request)
+	j.Input = &rangeInput{InputProvider: j.chunkInput, start: task.Start, end: task.End}
+	// The hosts calibrated by the coordinator are not calibrated again
+	j.restoreCalibration(task.Calibration)
+	j.errorLogMutex.Lock()
+	if task.RecordErrors && j.errorRecords == nil {
+		j.errorRecords = make([]ErrorRecord, 0)
+	}
+	j.errorLogMutex.Unlock()
+	j.Reset(false)
+	j.startExecution()
+	return j.queuejobs[1:]
+}
+
+// workerOutput collects the results and the messages of a job run by a worker, to be sent to the coordinator
+type workerOutput struct {
+	mutex    sync.Mutex
+	results  []DistributedResult
+	messages []DistributedMessage
+}
+
+func newWorkerOutput() *workerOutput {
+	return &workerOutput{results: make([]DistributedResult, 0), messages: make([]DistributedMessage, 0)}
+}
+
+// drain returns the collected results and messages, and empties the buffers
+func (o *workerOutput) drain() ([]DistributedResult, []DistributedMessage) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	results, messages := o.results, o.messages
+	o.results = make([]DistributedResult, 0)
+	o.messages = make([]DistributedMessage, 0)
+	return results, messages
+}
+
+func (o *workerOutput) message(msgtype, text string) {
+	if text == "" {
+		return
+	}
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	o.messages = append(o.messages, DistributedMessage{Type: msgtype, Text: text})
+}
+
+func (o *workerOutput) Banner()                                {}
+func (o *workerOutput) Finalize() error                        { return nil }
+func (o *workerOutput) Progress(status Progress)               {}
+func (o *workerOutput) Info(infostring string)                 { o.message("info", infostring) }
+func (o *workerOutput) Error(errstring string)                 { o.message("error", errstring) }
+func (o *workerOutput) Raw(output string)                      {}
+func (o *workerOutput) Warning(warnstring string)              { o.message("warning", warnstring) }
+func (o *workerOutput) PrintResult(res Result)                 {}
+func (o *workerOutput) SaveFile(filename, format string) error { return nil }
+func (o *workerOutput) GetResults() []Result                   { return []Result{} }
+func (o *workerOutput) SetResults(results []Result)            {}
+func (o *workerOutput) GetCurrentResults() []Result            { return []Result{} }
+func (o *workerOutput) SetCurrentResults(results []Result)     {}
+func (o *workerOutput) Reset()                                 {}
+func (o *workerOutput) Cycle()                                 {}
+
+func (o *workerOutput) Result(resp Response) {
+	res := DistributedResult{
+		Result:     NewResult(&resp),
+		Headers:    resp.Headers,
+		Raw:        resp.Raw,
+		RequestRaw: resp.Request.Raw,
+	}
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	o.results = append(o.results, res)
+}
+
+// response rebuilds the Response of a result reported by a worker, for the OutputProvider of the coordinator
+func (r *DistributedResult) response() Response {
+	req := &Request{
+		Url:      r.Url,
+		Host:     r.Host,
+		Input:    r.Input,
+		Position: r.Position,
+		Raw:      r.RequestRaw,
+	}
+	scraperData := r.ScraperData
+	if scraperData == nil {
+		scraperData = make(map[string][]string)
+	}
+	return Response{
+		StatusCode:    r.StatusCode,
+		Headers:       r.Headers,
+		ContentLength: r.ContentLength,
+		ContentWords:  r.ContentWords,
+		ContentLines:  r.ContentLines,
+		ContentType:   r.ContentType,
+		Request:       req,
+		Raw:           r.Raw,
+		ScraperData:   scraperData,
+		Time:          r.Duration,
+		Retries:       r.Retries,
+	}
+}
+
+// Worker runs the tasks handed out by a coordinator, until the coordinator tells that the job is done
+type Worker struct {
+	Coordinator string
+	Name        string
+	// Secret is the shared secret of the coordinator
+	Secret string
+	// NewJob sets up a job from the options of a task, the same way as for a job started from the command line
+	NewJob func(options *ConfigOptions) (*Job, error)
+	client *http.Client
+	jobs   map[string]*workerJob
+}
+
+// workerJob is a job of a worker, kept for the following tasks with the same options
+type workerJob struct {
+	job     *Job
+	output  *workerOutput
+	errors  int
+	retries int
+	failed  int
+}
+
+func NewWorker(coordinator string, name string, newjob func(options *ConfigOptions) (*Job, error)) *Worker {
+	return &Worker{
+		Coordinator: coordinator,
+		Name:        name,
+		NewJob:      newjob,
+		client:      &http.Client{Timeout: DISTRIBUTED_LEASE},
+		jobs:        make(map[string]*workerJob),
+	}
+}
+
+// Run asks the coordinator for tasks and runs them, until the coordinator is done or has not been reachable for
+// the duration of a task lease
+func (w *Worker) Run() error {
+	defer func() {
+		for _, wj := range w.jobs {
+			wj.job.closeInput()
+		}
+	}()
+	var lastSeen time.Time
+	for {
+		task, status, err := w.nextTask()
+		if err != nil {
+			if !lastSeen.IsZero() && time.Since(lastSeen) > DISTRIBUTED_LEASE {
+				return fmt.Errorf("lost the connection to the coordinator: %s", err)
+			}
+			time.Sleep(DISTRIBUTED_POLL_INTERVAL)
+			continue
+		}
+		lastSeen = time.Now()
+		switch status {
+		case http.StatusOK:
+			if err := w.runTask(task); err != nil {
+				return err
+			}
+			lastSeen = time.Now()
+		case http.StatusNoContent:
+			time.Sleep(DISTRIBUTED_POLL_INTERVAL)
+		case http.StatusGone:
+			return nil
+		case http.StatusUnauthorized:
+			return fmt.Errorf("the coordinator did not accept the secret, set it with -secret or %s", COORDINATOR_SECRET_ENV)
+		default:
+			return fmt.Errorf("unexpected response from the coordinator: %d", status)
+		}
+	}
+}
+
+func (w *Worker) nextTask() (DistributedTask, int, error) {
+	var task DistributedTask
+	resp, err := w.post("/task?worker="+url.QueryEscape(w.Name), nil)
+	if err != nil {
+		return task, 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusOK {
+		if err := json.NewDecoder(resp.Body).Decode(&task); err != nil {
+			return task, 0, err
+		}
+	}
+	return task, resp.StatusCode, nil
+}
+
+func (w *Worker) job(options *ConfigOptions) (*workerJob, error) {
+	key, err := json.Marshal(options)
+	if err != nil {
+		return nil, err
+	}
+	if wj, ok := w.jobs[string(key)]; ok {
+		return wj, nil
+	}
+	job, err := w.NewJob(options)
+	if err != nil {
+		return nil, err
+	}
+	wj := &workerJob{job: job, output: newWorkerOutput()}
+	job.Output = wj.output
+	w.jobs[string(key)] = wj
+	return wj, nil
+}
+
+// runTask runs a task and reports its progress to the coordinator while it is running
+func (w *Worker) runTask(task DistributedTask) error {
+	wj, err := w.job(&task.Options)
+	if err != nil {
+		return fmt.Errorf("could not set up the job of the coordinator: %s", err)
+	}
+	done := make(chan []QueueJob)
+	go func() {
+		done <- wj.job.RunChunk(task)
+	}()
+	requests := 0
+	ticker := time.NewTicker(DISTRIBUTED_REPORT_INTERVAL)
+	defer ticker.Stop()
+	for {
+		select {
+		case queued := <-done:
+			rep := w.report(wj, task, &requests)
+			rep.Done = true
+			for _, qj := range queued {
+				rep.QueueJobs = append(rep.QueueJobs, ResumeQueueJob{Url: qj.Url, Depth: qj.depth, Request: qj.req})
+			}
+			rep.Stopped = !wj.job.isRunning()
+			// The final report must get through, otherwise the task would be run again by another worker
+			deadline := time.Now().Add(DISTRIBUTED_LEASE)
+			for {
+				_, err := w.send(rep)
+				if err == nil || time.Now().After(deadline) {
+					break
+				}
+				time.Sleep(DISTRIBUTED_POLL_INTERVAL)
+			}
+			if rep.Stopped {
+				return fmt.Errorf("the job was stopped: %s", wj.job.Error)
+			}
+			return nil
+		case <-ticker.C:
+			reply, err := w.send(w.report(wj, task, &requests))
+			if err == nil && reply.Stop {
+				wj.job.Next()
+			}
+		}
+	}
+}
+
+// report collects the new results, messages, errors and counters of the task since the previous report
+func (w *Worker) report(wj *workerJob, task DistributedTask, requests *int) DistributedReport {
+	j := wj.job
+	rep := DistributedReport{Worker: w.Name, TaskID: task.ID}
+	j.ErrorMutex.Lock()
+	rep.Requests, *requests = j.Counter-*requests, j.Counter
+	rep.Errors, wj.errors = j.ErrorCounter-wj.errors, j.ErrorCounter
+	rep.Retries, wj.retries = j.RetryCounter-wj.retries, j.RetryCounter
+	rep.Failed, wj.failed = j.FailedCounter-wj.failed, j.FailedCounter
+	j.ErrorMutex.Unlock()
+	rep.Results, rep.Messages = wj.output.drain()
+	j.errorLogMutex.Lock()
+	if j.errorRecords != nil {
+		rep.ErrorRecords = j.errorRecords
+		j.errorRecords = make([]ErrorRecord, 0)
+	}
+	j.errorLogMutex.Unlock()
+	return rep
+}
+
+func (w *Worker) send(rep DistributedReport) (DistributedReply, error) {
+	var reply DistributedReply
+	body, err := json.Marshal(rep)
+	if err != nil {
+		return reply, err
+	}
+	resp, err := w.post("/report", bytes.NewReader(body))
+	if err != nil {
+		return reply, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return reply, fmt.Errorf("unexpected response from the coordinator: %d", resp.StatusCode)
+	}
+	err = json.NewDecoder(resp.Body).Decode(&reply)
+	return reply, err
+}
+
+// post sends a request with the shared secret to the coordinator
+func (w *Worker) post(path string, body io.Reader) (*http.Response, error) {
+	req, err := http.NewRequest("POST", w.Coordinator+path, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+w.Secret)
+	return w.client.Do(req)
+}
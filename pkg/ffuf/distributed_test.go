@@ -0,0 +1,282 @@
+package ffuf
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRangeInput(t *testing.T) {
+	input := &rangeInput{InputProvider: &countingInput{total: 100}, start: 21, end: 30}
+	input.Reset()
+	positions := make([]int, 0)
+	for input.Next() {
+		positions = append(positions, input.Position())
+	}
+	if len(positions) != 10 || positions[0] != 21 || positions[9] != 30 {
+		t.Errorf("Expected positions 21-30, got %v", positions)
+	}
+	if input.Total() != 10 {
+		t.Errorf("Expected total to be the size of the range, got %d", input.Total())
+	}
+}
+
+// resultOutput records the results
+type resultOutput struct {
+	NullOutput
+	mutex   sync.Mutex
+	results []Response
+}
+
+func (o *resultOutput) Result(resp Response) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	o.results = append(o.results, resp)
+}
+
+// statusRunner responds with 200 to the inputs ending in 7, and with 404 to the others
+type statusRunner struct {
+	testRunner
+}
+
+func (r *statusRunner) Execute(req *Request) (Response, error) {
+	status := int64(404)
+	if strings.HasSuffix(string(req.Input["FUZZ"]), "7") {
+		status = 200
+	}
+	return Response{StatusCode: status, Request: req, Headers: map[string][]string{}, ScraperData: map[string][]string{}}, nil
+}
+
+// freeAddr returns a local address for the coordinator to listen on
+func freeAddr(t *testing.T) string {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to find a free port: %v", err)
+	}
+	defer ln.Close()
+	return ln.Addr().String()
+}
+
+// startWorkers starts workers for the coordinator, with jobs of 250 inputs using the runners from newRunner
+func startWorkers(t *testing.T, addr string, count int, newRunner func() RunnerProvider) *sync.WaitGroup {
+	var wg sync.WaitGroup
+	for i := 0; i < count; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			w := NewWorker("http://"+addr, fmt.Sprintf("worker%d", i), func(options *ConfigOptions) (*Job, error) {
+				wconf := NewConfig(context.Background(), func() {})
+				wconf.Url = options.HTTP.URL
+				wconf.Threads = options.General.Threads
+				wconf.AutoCalibration = options.General.AutoCalibration
+				wconf.MatcherManager = newTestMatcherManager()
+				wjob := NewJob(&wconf)
+				wjob.Input = &countingInput{total: 250}
+				wjob.Runner = newRunner()
+				return wjob, nil
+			})
+			w.Secret = "s3cret"
+			if err := w.Run(); err != nil {
+				t.Errorf("Worker %d failed: %v", i, err)
+			}
+		}(i)
+	}
+	return &wg
+}
+
+func TestDistributedJob(t *testing.T) {
+	HISTORYDIR = t.TempDir()
+	addr := freeAddr(t)
+
+	conf := NewConfig(context.Background(), func() {})
+	conf.Url = "http://example.com/FUZZ"
+	conf.Coordinator = addr
+	conf.CoordinatorSecret = "s3cret"
+	conf.ChunkSize = 17
+	conf.Threads = 5
+	conf.Quiet = true
+	conf.MatcherManager = newTestMatcherManager()
+	job := NewJob(&conf)
+	job.Input = &countingInput{total: 250}
+	out := &resultOutput{}
+	job.Output = out
+
+	wg := startWorkers(t, addr, 3, func() RunnerProvider { return &statusRunner{} })
+	job.Start()
+	wg.Wait()
+
+	positions := make([]int, 0)
+	for _, resp := range out.results {
+		positions = append(positions, resp.Request.Position)
+		if fmt.Sprint(resp.Request.Position) != string(resp.Request.Input["FUZZ"]) {
+			t.Errorf("Expected the input of position %d, got %s", resp.Request.Position, resp.Request.Input["FUZZ"])
+		}
+	}
+	sort.Ints(positions)
+	if len(positions) != 25 || positions[0] != 7 || positions[24] != 247 {
+		t.Errorf("Expected each of the 25 matching positions to be reported once, got %v", positions)
+	}
+	for i := 1; i < len(positions); i++ {
+		if positions[i] == positions[i-1] {
+			t.Errorf("Position %d was reported more than once", positions[i])
+		}
+	}
+	if job.Counter != 250 {
+		t.Errorf("Expected the request counters of the workers to add up to 250, got %d", job.Counter)
+	}
+}
+
+// countingRunner counts the requests sent by a worker
+type countingRunner struct {
+	testRunner
+	requests *atomic.Int64
+}
+
+func (r *countingRunner) Execute(req *Request) (Response, error) {
+	r.requests.Add(1)
+	return r.testRunner.Execute(req)
+}
+
+func TestDistributedCalibration(t *testing.T) {
+	HISTORYDIR = t.TempDir()
+	AUTOCALIBDIR = t.TempDir()
+	addr := freeAddr(t)
+	response := func(input string) string {
+		if strings.HasSuffix(input, "7") {
+			return "found " + input
+		}
+		return "the page you are looking for was not found"
+	}
+
+	conf := NewConfig(context.Background(), func() {})
+	conf.Url = "http://example.com/FUZZ"
+	conf.Coordinator = addr
+	conf.CoordinatorSecret = "s3cret"
+	conf.ChunkSize = 50
+	conf.Threads = 5
+	conf.Quiet = true
+	conf.AutoCalibration = true
+	conf.MatcherManager = newTestMatcherManager()
+	job := NewJob(&conf)
+	job.Input = &countingInput{total: 250}
+	job.Runner = &testRunner{response: response}
+	out := &resultOutput{}
+	job.Output = out
+
+	var requests atomic.Int64
+	wg := startWorkers(t, addr, 2, func() RunnerProvider {
+		return &countingRunner{testRunner: testRunner{response: response}, requests: &requests}
+	})
+	job.Start()
+	wg.Wait()
+
+	if !conf.MatcherManager.Calibrated() || len(conf.MatcherManager.GetFilters()["calibration"].Repr()) == 0 {
+		t.Errorf("Expected the coordinator to calibrate the job")
+	}
+	if requests.Load() != 250 {
+		t.Errorf("Expected the workers to use the filters of the coordinator instead of calibrating, got %d requests", requests.Load())
+	}
+	if len(out.results) != 25 {
+		t.Errorf("Expected the calibration of the coordinator to filter all but the 25 matching positions, got %d results", len(out.results))
+	}
+}
+
+func TestCoordinatorLeaseCounting(t *testing.T) {
+	conf := NewConfig(context.Background(), func() {})
+	conf.MatcherManager = newTestMatcherManager()
+	job := NewJob(&conf)
+	job.Output = &resultOutput{}
+	c := &coordinator{
+		job:       job,
+		pending:   []DistributedTask{{ID: 1, Start: 1, End: 10}},
+		firstTask: 1,
+		leases:    make(map[int]*taskLease),
+		reported:  make(map[int]bool),
+		messages:  make(map[string]bool),
+		workers:   make(map[string]bool),
+	}
+	lease := func(worker string) {
+		rec := httptest.NewRecorder()
+		c.handleTask(rec, httptest.NewRequest("POST", "/task?worker="+worker, nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected worker %s to get the task, got status %d", worker, rec.Code)
+		}
+	}
+	report := func(rep DistributedReport) DistributedReply {
+		body, _ := json.Marshal(rep)
+		rec := httptest.NewRecorder()
+		c.handleReport(rec, httptest.NewRequest("POST", "/report", bytes.NewReader(body)))
+		var reply DistributedReply
+		_ = json.NewDecoder(rec.Body).Decode(&reply)
+		return reply
+	}
+
+	lease("a")
+	report(DistributedReport{Worker: "a", TaskID: 1, Requests: 4, Errors: 1})
+	if job.Counter != 0 || job.ErrorCounter != 0 {
+		t.Errorf("Expected the requests of a running task not to be counted yet, got %d requests", job.Counter)
+	}
+	c.leases[1].deadline = time.Now().Add(-time.Second)
+	c.expireLeases()
+	lease("b")
+	report(DistributedReport{Worker: "b", TaskID: 1, Requests: 10, Errors: 2, Done: true})
+	if reply := report(DistributedReport{Worker: "a", TaskID: 1, Requests: 6}); !reply.Stop {
+		t.Errorf("Expected the worker of the expired lease to be told to stop")
+	}
+	if job.Counter != 10 || job.ErrorCounter != 2 {
+		t.Errorf("Expected only the requests of the completed task to be counted, got %d requests and %d errors", job.Counter, job.ErrorCounter)
+	}
+
+	c.pending = []DistributedTask{{ID: 2, Start: 11, End: 20}}
+	lease("b")
+	if reply := report(DistributedReport{Worker: "b", TaskID: 2, Stopped: true}); !reply.Stop || c.stopReason() == "" {
+		t.Errorf("Expected a stopping condition of a worker to be handed to the main loop")
+	}
+}
+
+func TestCoordinatorSecret(t *testing.T) {
+	conf := NewConfig(context.Background(), func() {})
+	conf.Coordinator = "127.0.0.1:0"
+	conf.CoordinatorSecret = "s3cret"
+	conf.MatcherManager = newTestMatcherManager()
+	job := NewJob(&conf)
+	job.Output = &resultOutput{}
+	if err := job.startCoordinator(); err != nil {
+		t.Fatalf("Failed to start the coordinator: %v", err)
+	}
+	defer job.coordinator.close()
+
+	for _, secret := range []string{"", "wrong"} {
+		w := NewWorker("http://"+job.coordinator.Addr(), "worker", nil)
+		w.Secret = secret
+		for _, path := range []string{"/task", "/report"} {
+			resp, err := w.post(path, strings.NewReader("{}"))
+			if err != nil {
+				t.Fatalf("Failed to reach the coordinator: %v", err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != http.StatusUnauthorized {
+				t.Errorf("Expected %s to be rejected with secret %q, got status %d", path, secret, resp.StatusCode)
+			}
+		}
+		if err := w.Run(); err == nil || !strings.Contains(err.Error(), "did not accept the secret") {
+			t.Errorf("Expected the worker to give up on a wrong secret, got %v", err)
+		}
+	}
+
+	w := NewWorker("http://"+job.coordinator.Addr(), "worker", nil)
+	w.Secret = "s3cret"
+	if _, status, err := w.nextTask(); err != nil || status != http.StatusNoContent {
+		t.Errorf("Expected the worker with the secret to be accepted, got status %d and error %v", status, err)
+	}
+}
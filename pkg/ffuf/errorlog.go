@@ -0,0 +1,234 @@
+package ffuf
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+)
+
+// ErrorRecord describes a request that failed, written to the errors file (-oe) and read back with -replay-errors
+type ErrorRecord struct {
+	Input    map[string]string `json:"input"`
+	Position int               `json:"position"`
+	FfufHash string            `json:"ffufhash"`
+	Url      string            `json:"url"`
+	Job      string            `json:"job"`
+	Depth    int               `json:"depth"`
+	Template string            `json:"template"`
+	Error    string            `json:"error"`
+	Time     time.Time         `json:"time"`
+}
+
+// replayKey identifies a queue job: sniper jobs share the url, but each has its own request template
+type replayKey struct {
+	Url      string
+	Template string
+}
+
+// requestTemplateHash returns a short hash identifying the request template of a queue job
+func requestTemplateHash(req Request) string {
+	tmpl, _ := json.Marshal(struct {
+		Method  string
+		Url     string
+		Headers map[string]string
+		Data    []byte
+	}{req.Method, req.Url, req.Headers, req.Data})
+	return fmt.Sprintf("%x", sha256.Sum256(tmpl))[:16]
+}
+
+// ReadErrorRecords reads the failed requests from a JSONL errors file
+func ReadErrorRecords(filename string) ([]ErrorRecord, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	records := make([]ErrorRecord, 0)
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var rec ErrorRecord
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			return nil, fmt.Errorf("invalid error record on line %d: %s", line, err)
+		}
+		records = append(records, rec)
+	}
+	return records, scanner.Err()
+}
+
+// openErrorLog opens the errors file for writing. The file is appended to when continuing a job with -resume.
+func (j *Job) openErrorLog() error {
+	flags := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	if j.resumeFrom > 0 {
+		flags = os.O_CREATE | os.O_WRONLY | os.O_APPEND
+	}
+	f, err := os.OpenFile(j.Config.ErrorFile, flags, 0644)
+	if err != nil {
+		return err
+	}
+	j.errorLog = f
+	return nil
+}
+
+// closeErrorLog closes the errors file
+func (j *Job) closeErrorLog() {
+	j.errorLogMutex.Lock()
+	defer j.errorLogMutex.Unlock()
+	if j.errorLog != nil {
+		_ = j.errorLog.Close()
+		j.errorLog = nil
+	}
+}
+
+// recordError writes a failed request to the errors file, if one is in use. Distributed workers collect the failed
+// requests to send them to the coordinator instead.
+func (j *Job) recordError(input map[string][]byte, position int, url string, reqerr error) {
+	j.errorLogMutex.Lock()
+	defer j.errorLogMutex.Unlock()
+	if j.errorLog == nil && j.errorRecords == nil {
+		return
+	}
+	rec := ErrorRecord{
+		Input:    make(map[string]string),
+		Position: position,
+		FfufHash: string(input["FFUFHASH"]),
+		Url:      url,
+		Job:      j.Config.Url,
+		Depth:    j.currentDepth,
+		Template: j.currentTemplate,
+		Error:    reqerr.Error(),
+		Time:     time.Now(),
+	}
+	for k, v := range input {
+		if k != "FFUFHASH" {
+			rec.Input[k] = string(v)
+		}
+	}
+	if j.errorRecords != nil {
+		j.errorRecords = append(j.errorRecords, rec)
+		return
+	}
+	j.writeErrorRecord(rec)
+}
+
+// writeErrorRecord writes a failed request to the errors file. The caller must hold errorLogMutex.
+func (j *Job) writeErrorRecord(rec ErrorRecord) {
+	if j.errorLog == nil {
+		return
+	}
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return
+	}
+	if _, err := j.errorLog.Write(append(line, '\n')); err != nil {
+		j.Output.Error(fmt.Sprintf("Could not write to the errors file: %s", err))
+	}
+}
+
+// setupErrorReplay reads the failed requests to replay, and queues the jobs they belong to
+func (j *Job) setupErrorReplay() error {
+	records, err := ReadErrorRecords(j.Config.ReplayErrors)
+	if err != nil {
+		return err
+	}
+	j.replayBase = j.Input
+	j.replayPositions = make(map[replayKey][]int)
+	seen := make(map[replayKey]map[int]bool)
+	count := 0
+	for _, rec := range records {
+		key := replayKey{Url: rec.Job, Template: rec.Template}
+		if seen[key] == nil {
+			seen[key] = make(map[int]bool)
+			if !j.queuedTemplate(key) {
+				// the request failed in a recursion job, which keeps its depth so it does not recurse past the limit
+				j.addQueueJob(QueueJob{Url: rec.Job, depth: rec.Depth, req: RecursionRequest(j.Config, rec.Job)})
+			}
+		}
+		if !seen[key][rec.Position] {
+			seen[key][rec.Position] = true
+			j.replayPositions[key] = append(j.replayPositions[key], rec.Position)
+			count++
+		}
+	}
+	for key := range j.replayPositions {
+		sort.Ints(j.replayPositions[key])
+	}
+	j.Output.Info(fmt.Sprintf("Replaying %d failed requests from %s", count, j.Config.ReplayErrors))
+	return nil
+}
+
+// queued checks if a job for the url is already in the job queue
+func (j *Job) queued(url string) bool {
+	j.queueMutex.Lock()
+	defer j.queueMutex.Unlock()
+	for _, qj := range j.queuejobs {
+		if qj.Url == url {
+			return true
+		}
+	}
+	return false
+}
+
+// queuedTemplate checks if a job for the url and request template is already in the job queue
+func (j *Job) queuedTemplate(key replayKey) bool {
+	j.queueMutex.Lock()
+	defer j.queueMutex.Unlock()
+	for _, qj := range j.queuejobs {
+		if qj.Url == key.Url && requestTemplateHash(qj.req) == key.Template {
+			return true
+		}
+	}
+	return false
+}
+
+// positionInput restricts an InputProvider to a sorted list of positions, while keeping the original positions
+type positionInput struct {
+	InputProvider
+	positions []int
+	index     int
+}
+
+func newPositionInput(input InputProvider, positions []int) *positionInput {
+	return &positionInput{InputProvider: input, positions: positions}
+}
+
+// Next advances the underlying InputProvider to the next position on the list
+func (p *positionInput) Next() bool {
+	if p.index >= len(p.positions) {
+		return false
+	}
+	pos := p.positions[p.index]
+	p.index++
+	for p.InputProvider.Position() < pos-1 {
+		if !p.InputProvider.Next() {
+			return false
+		}
+		// Value advances the iterators of the underlying InputProvider
+		p.InputProvider.Value()
+	}
+	return p.InputProvider.Next()
+}
+
+// SetPosition continues from the first position on the list that is not lower than pos
+func (p *positionInput) SetPosition(pos int) {
+	p.InputProvider.Reset()
+	p.index = sort.SearchInts(p.positions, pos)
+}
+
+func (p *positionInput) Reset() {
+	p.InputProvider.Reset()
+	p.index = 0
+}
+
+func (p *positionInput) Total() int {
+	return len(p.positions)
+}
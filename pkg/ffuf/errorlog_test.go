@@ -0,0 +1,134 @@
+package ffuf
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+// countingInput is an InputProvider returning the position as the FUZZ value
+type countingInput struct {
+	position int
+	total    int
+}
+
+func (i *countingInput) ActivateKeywords([]string)             {}
+func (i *countingInput) AddProvider(InputProviderConfig) error { return nil }
+func (i *countingInput) Keywords() []string                    { return []string{"FUZZ"} }
+func (i *countingInput) Position() int                         { return i.position }
+func (i *countingInput) SetPosition(pos int)                   { i.position = pos - 1 }
+func (i *countingInput) Reset()                                { i.position = 0 }
+func (i *countingInput) Total() int                            { return i.total }
+func (i *countingInput) Value() map[string][]byte {
+	return map[string][]byte{"FUZZ": []byte(strconv.Itoa(i.position))}
+}
+func (i *countingInput) Next() bool {
+	if i.position >= i.total {
+		return false
+	}
+	i.position++
+	return true
+}
+
+func TestPositionInput(t *testing.T) {
+	input := newPositionInput(&countingInput{total: 100}, []int{1, 5, 6, 50, 100, 200})
+	values := make([]string, 0)
+	for input.Next() {
+		if string(input.Value()["FUZZ"]) != strconv.Itoa(input.Position()) {
+			t.Errorf("Expected the value to match the original position %d, got %s", input.Position(), input.Value()["FUZZ"])
+		}
+		values = append(values, string(input.Value()["FUZZ"]))
+	}
+	if fmt.Sprint(values) != "[1 5 6 50 100]" {
+		t.Errorf("Expected only the listed positions within the input, got %v", values)
+	}
+	if input.Total() != 6 {
+		t.Errorf("Expected total to be the number of listed positions, got %d", input.Total())
+	}
+	input.SetPosition(7)
+	if !input.Next() || input.Position() != 50 {
+		t.Errorf("Expected to continue from the next listed position 50, got %d", input.Position())
+	}
+}
+
+func TestErrorRecords(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "ffuf-test")
+	if err != nil {
+		t.Fatalf("Failed to create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	conf := NewConfig(context.Background(), func() {})
+	conf.Url = "http://example.com/FUZZ"
+	conf.ErrorFile = filepath.Join(tmpDir, "errors.jsonl")
+	conf.MatcherManager = newTestMatcherManager()
+	job := NewJob(&conf)
+	job.Output = NewNullOutput()
+	job.Input = &countingInput{total: 10}
+	job.WriteHistory = false
+	// Two sniper jobs share the url, but not the request template
+	baseUrl := conf.Url
+	deepUrl := "http://example.com/a/b/FUZZ"
+	job.queuejobs = []QueueJob{
+		{Url: baseUrl, req: Request{Method: "POST", Url: baseUrl, Data: []byte("user=FUZZ&pass=x")}},
+		{Url: baseUrl, req: Request{Method: "POST", Url: baseUrl, Data: []byte("user=x&pass=FUZZ")}},
+		{Url: deepUrl, depth: 2, req: RecursionRequest(&conf, deepUrl)},
+	}
+	if err := job.openErrorLog(); err != nil {
+		t.Fatalf("Failed to open errors file: %v", err)
+	}
+	job.prepareQueueJob()
+	for _, pos := range []int{7, 3, 7} {
+		input := map[string][]byte{"FUZZ": []byte("admin" + strconv.Itoa(pos)), "FFUFHASH": []byte("abc" + strconv.Itoa(pos))}
+		job.recordError(input, pos, "http://example.com/admin"+strconv.Itoa(pos), fmt.Errorf("connection refused"))
+	}
+	job.prepareQueueJob()
+	job.recordError(map[string][]byte{"FUZZ": []byte("admin5")}, 5, "http://example.com/", fmt.Errorf("connection refused"))
+	job.prepareQueueJob()
+	job.recordError(map[string][]byte{"FUZZ": []byte("c")}, 2, "http://example.com/a/b/c", fmt.Errorf("connection refused"))
+	job.closeErrorLog()
+
+	records, err := ReadErrorRecords(conf.ErrorFile)
+	if err != nil {
+		t.Fatalf("Failed to read errors file: %v", err)
+	}
+	if len(records) != 5 || records[1].Position != 3 || records[1].Input["FUZZ"] != "admin3" || records[1].FfufHash != "abc3" ||
+		records[1].Error != "connection refused" || records[1].Job != baseUrl ||
+		records[4].Job != deepUrl || records[4].Depth != 2 {
+		t.Errorf("Unexpected error records: %v", records)
+	}
+	if _, ok := records[0].Input["FFUFHASH"]; ok {
+		t.Errorf("Expected FFUFHASH not to be recorded as an input")
+	}
+
+	if records[0].Template == records[3].Template {
+		t.Errorf("Expected the sniper jobs to be told apart by the request template")
+	}
+
+	conf.ReplayErrors = conf.ErrorFile
+	job.Input = &countingInput{total: 10}
+	// The recursion job is not queued at the start of the replaying job
+	job.queuejobs = job.queuejobs[:2]
+	if err := job.setupErrorReplay(); err != nil {
+		t.Fatalf("Failed to set up error replay: %v", err)
+	}
+	if len(job.queuejobs) != 3 {
+		t.Fatalf("Expected only the recursion job to be queued, got %d jobs", len(job.queuejobs))
+	}
+	if job.queuejobs[2].Url != deepUrl || job.queuejobs[2].depth != 2 {
+		t.Errorf("Expected the recursion job to be queued with its depth, got %s at depth %d", job.queuejobs[2].Url, job.queuejobs[2].depth)
+	}
+	first := replayKey{Url: baseUrl, Template: records[0].Template}
+	second := replayKey{Url: baseUrl, Template: records[3].Template}
+	if fmt.Sprint(job.replayPositions[first]) != "[3 7]" || fmt.Sprint(job.replayPositions[second]) != "[5]" {
+		t.Errorf("Expected sorted unique positions to replay for each job, got %v", job.replayPositions)
+	}
+
+	_ = os.WriteFile(conf.ErrorFile, []byte("{\"position\":1}\nnot json\n"), 0644)
+	if _, err := ReadErrorRecords(conf.ErrorFile); err == nil {
+		t.Errorf("Expected an error for an invalid errors file")
+	}
+}
@@ -18,15 +18,10 @@ type ConfigOptionsHistory struct {
 }
 
 func WriteHistoryEntry(conf *Config) (string, error) {
-	options := ConfigOptionsHistory{
-		ConfigOptions: conf.ToOptions(),
-		Time:          time.Now(),
-	}
-	jsonoptions, err := json.Marshal(options)
+	jsonoptions, hashstr, err := historyEntry(conf)
 	if err != nil {
 		return "", err
 	}
-	hashstr := calculateHistoryHash(jsonoptions)
 	err = createConfigDir(filepath.Join(HISTORYDIR, hashstr))
 	if err != nil {
 		return "", err
@@ -35,6 +30,24 @@ func WriteHistoryEntry(conf *Config) (string, error) {
 	return hashstr, err
 }
 
+// HistoryHash returns the hash of a job for FFUFHASH values, without writing the job to the history
+func HistoryHash(conf *Config) (string, error) {
+	_, hashstr, err := historyEntry(conf)
+	return hashstr, err
+}
+
+func historyEntry(conf *Config) ([]byte, string, error) {
+	options := ConfigOptionsHistory{
+		ConfigOptions: conf.ToOptions(),
+		Time:          time.Now(),
+	}
+	jsonoptions, err := json.Marshal(options)
+	if err != nil {
+		return nil, "", err
+	}
+	return jsonoptions, calculateHistoryHash(jsonoptions), nil
+}
+
 func calculateHistoryHash(options []byte) string {
 	return fmt.Sprintf("%x", sha256.Sum256(options))
 }
@@ -61,6 +61,11 @@ type InternalInputProvider interface {
 	Disable()
 }
 
+// ResponseRecorder is implemented by the output providers that record all of the responses, and not only the results
+type ResponseRecorder interface {
+	Record(resp Response)
+}
+
 // OutputProvider is responsible of providing output from the RunnerProvider
 type OutputProvider interface {
 	Banner()
@@ -73,6 +78,8 @@ type OutputProvider interface {
 	Result(resp Response)
 	PrintResult(res Result)
 	SaveFile(filename, format string) error
+	GetResults() []Result
+	SetResults(results []Result)
 	GetCurrentResults() []Result
 	SetCurrentResults(results []Result)
 	Reset()
@@ -105,5 +112,6 @@ type Result struct {
 	ScraperData      map[string][]string `json:"scraper"`
 	ResultFile       string              `json:"resultfile"`
 	Host             string              `json:"host"`
+	Retries          int                 `json:"retries"`
 	HTMLColor        string              `json:"-"`
 }
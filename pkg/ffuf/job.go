@@ -2,10 +2,12 @@ package ffuf
 
 import (
 	"fmt"
+	"io"
 	"log"
 	"math/rand"
 	"os"
 	"os/signal"
+	"strings"
 	"sync"
 	"syscall"
 	"time"
@@ -20,27 +22,51 @@ type Job struct {
 	Runner               RunnerProvider
 	ReplayRunner         RunnerProvider
 	Scraper              Scraper
+	Session              *Session
 	Output               OutputProvider
 	Jobhash              string
 	Counter              int
 	ErrorCounter         int
 	SpuriousErrorCounter int
+	RetryCounter         int
+	FailedCounter        int
 	Total                int
 	Running              bool
 	RunningJob           bool
 	Paused               bool
+	stateMutex           sync.Mutex
 	Count403             int
 	Count429             int
 	Error                string
 	Rate                 *RateThrottle
+	HandleSignals        bool
+	WriteHistory         bool
+	Logger               *log.Logger
 	startTime            time.Time
 	startTimeJob         time.Time
 	queuejobs            []QueueJob
+	queueMutex           sync.Mutex
 	queuepos             int
 	skipQueue            bool
 	currentDepth         int
+	currentTemplate      string
 	calibMutex           sync.Mutex
+	driftMutex           sync.Mutex
 	pauseWg              sync.WaitGroup
+	calibratedHosts      []string
+	resumeMutex          sync.Mutex
+	resumeOptions        ConfigOptions
+	resumeFrom           int
+	inflight             map[int]bool
+	lastDispatched       int
+	lastCheckpoint       time.Time
+	errorLog             *os.File
+	errorLogMutex        sync.Mutex
+	replayBase           InputProvider
+	replayPositions      map[replayKey][]int
+	errorRecords         []ErrorRecord
+	coordinator          *coordinator
+	chunkInput           InputProvider
 }
 
 type QueueJob struct {
@@ -58,6 +84,8 @@ func NewJob(conf *Config) *Job {
 	j.Counter = 0
 	j.ErrorCounter = 0
 	j.SpuriousErrorCounter = 0
+	j.RetryCounter = 0
+	j.FailedCounter = 0
 	j.Running = false
 	j.RunningJob = false
 	j.Paused = false
@@ -66,9 +94,59 @@ func NewJob(conf *Config) *Job {
 	j.currentDepth = 0
 	j.Rate = NewRateThrottle(conf)
 	j.skipQueue = false
+	j.inflight = make(map[int]bool)
+	j.HandleSignals = true
+	j.WriteHistory = true
+	j.Logger = log.Default()
+	if conf.SessionRequest != nil {
+		j.Session = NewSession(conf)
+	}
 	return &j
 }
 
+// isRunning tells if the job has not been stopped
+func (j *Job) isRunning() bool {
+	j.stateMutex.Lock()
+	defer j.stateMutex.Unlock()
+	return j.Running
+}
+
+// isRunningJob tells if the current queued job has not been stopped
+func (j *Job) isRunningJob() bool {
+	j.stateMutex.Lock()
+	defer j.stateMutex.Unlock()
+	return j.RunningJob
+}
+
+// queueSkipped tells if the current queued job was skipped
+func (j *Job) queueSkipped() bool {
+	j.stateMutex.Lock()
+	defer j.stateMutex.Unlock()
+	return j.skipQueue
+}
+
+// isPaused tells if the job is paused
+func (j *Job) isPaused() bool {
+	j.stateMutex.Lock()
+	defer j.stateMutex.Unlock()
+	return j.Paused
+}
+
+// incCounter increments the request counter, and returns its new value
+func (j *Job) incCounter() int {
+	j.ErrorMutex.Lock()
+	defer j.ErrorMutex.Unlock()
+	j.Counter++
+	return j.Counter
+}
+
+// requestCount returns the number of requests sent in the current job
+func (j *Job) requestCount() int {
+	j.ErrorMutex.Lock()
+	defer j.ErrorMutex.Unlock()
+	return j.Counter
+}
+
 // incError increments the error counter
 func (j *Job) incError() {
 	j.ErrorMutex.Lock()
@@ -91,6 +169,20 @@ func (j *Job) inc429() {
 	j.Count429++
 }
 
+// incRetry increments the retried request counter
+func (j *Job) incRetry() {
+	j.ErrorMutex.Lock()
+	defer j.ErrorMutex.Unlock()
+	j.RetryCounter++
+}
+
+// incFailed increments the counter of requests that failed after all the retries
+func (j *Job) incFailed() {
+	j.ErrorMutex.Lock()
+	defer j.ErrorMutex.Unlock()
+	j.FailedCounter++
+}
+
 // resetSpuriousErrors resets the spurious error counter
 func (j *Job) resetSpuriousErrors() {
 	j.ErrorMutex.Lock()
@@ -100,65 +192,185 @@ func (j *Job) resetSpuriousErrors() {
 
 // DeleteQueueItem deletes a recursion job from the queue by its index in the slice
 func (j *Job) DeleteQueueItem(index int) {
+	j.queueMutex.Lock()
+	defer j.queueMutex.Unlock()
 	index = j.queuepos + index - 1
 	j.queuejobs = append(j.queuejobs[:index], j.queuejobs[index+1:]...)
 }
 
 // QueuedJobs returns the slice of queued recursive jobs
 func (j *Job) QueuedJobs() []QueueJob {
-	return j.queuejobs[j.queuepos-1:]
+	j.queueMutex.Lock()
+	defer j.queueMutex.Unlock()
+	return append([]QueueJob{}, j.queuejobs[j.queuepos-1:]...)
 }
 
-// Start the execution of the Job
+// addQueueJob adds a job to the end of the job queue
+func (j *Job) addQueueJob(qj QueueJob) {
+	j.queueMutex.Lock()
+	defer j.queueMutex.Unlock()
+	j.queuejobs = append(j.queuejobs, qj)
+}
+
+// currentQueueJob returns the queued job that is running
+func (j *Job) currentQueueJob() QueueJob {
+	j.queueMutex.Lock()
+	defer j.queueMutex.Unlock()
+	return j.queuejobs[j.queuepos-1]
+}
+
+// Start the execution of the Job, printing out the error if it could not be started
 func (j *Job) Start() {
+	if err := j.Run(); err != nil {
+		j.Output.Error(err.Error())
+	}
+}
+
+// Run executes the Job, and returns an error if it could not be started
+func (j *Job) Run() error {
 	if j.startTime.IsZero() {
 		j.startTime = time.Now()
 	}
+	defer j.closeInput()
 
-	basereq := BaseRequest(j.Config)
+	targets := j.Config.Targets
+	if len(targets) == 0 {
+		targets = []string{j.Config.Url}
+	}
+	for _, target := range targets {
+		// Add a job for each of the targets to the job queue
+		basereq := RecursionRequest(j.Config, target)
+		if j.Config.InputMode == "sniper" {
+			// process multiple payload locations and create a queue job for each location
+			reqs := SniperRequests(&basereq, j.Config.InputProviders[0].Template)
+			for _, r := range reqs {
+				j.queuejobs = append(j.queuejobs, QueueJob{Url: target, depth: 0, req: r})
+			}
+			j.Total = j.Input.Total() * len(reqs)
+		} else {
+			j.queuejobs = append(j.queuejobs, QueueJob{Url: target, depth: 0, req: basereq})
+			j.Total = j.Input.Total()
+		}
+	}
 
-	if j.Config.InputMode == "sniper" {
-		// process multiple payload locations and create a queue job for each location
-		reqs := SniperRequests(&basereq, j.Config.InputProviders[0].Template)
-		for _, r := range reqs {
-			j.queuejobs = append(j.queuejobs, QueueJob{Url: j.Config.Url, depth: 0, req: r})
+	if j.Config.ReplayErrors != "" {
+		if err := j.setupErrorReplay(); err != nil {
+			return fmt.Errorf("Could not read the failed requests to replay: %s", err)
 		}
-		j.Total = j.Input.Total() * len(reqs)
-	} else {
-		// Add the default job to job queue
-		j.queuejobs = append(j.queuejobs, QueueJob{Url: j.Config.Url, depth: 0, req: BaseRequest(j.Config)})
-		j.Total = j.Input.Total()
+	}
+
+	if j.Config.ResumeFile != "" {
+		// Store the options before the job starts to mutate the configuration
+		j.resumeOptions = j.Config.ToOptions()
+		if FileExists(j.Config.ResumeFile) {
+			state, err := ReadResumeState(j.Config.ResumeFile)
+			if err != nil {
+				j.Output.Error(fmt.Sprintf("Could not read resume state: %s", err))
+			} else {
+				j.restoreResumeState(state)
+			}
+		}
+	}
+
+	if j.Config.ErrorFile != "" {
+		if err := j.openErrorLog(); err != nil {
+			return fmt.Errorf("Could not open the errors file: %s", err)
+		}
+		defer j.closeErrorLog()
+	}
+
+	if j.Config.Coordinator != "" {
+		if err := j.startCoordinator(); err != nil {
+			return fmt.Errorf("Could not start the coordinator: %s", err)
+		}
+		defer j.coordinator.close()
+	}
+	// The coordinator only sends requests of its own for the autocalibration
+	if j.Session != nil && (j.coordinator == nil || j.Config.AutoCalibration) {
+		if err := j.Session.Login(j.Runner); err != nil {
+			return fmt.Errorf("Could not log in with the session request: %s", err)
+		}
+		j.Output.Info(fmt.Sprintf("Logged in, using session values: %s", strings.Join(j.Session.Values(), ", ")))
 	}
 
 	rand.Seed(time.Now().UnixNano())
 	defer j.Stop()
 
+	j.stateMutex.Lock()
 	j.Running = true
 	j.RunningJob = true
+	j.stateMutex.Unlock()
 	//Show banner if not running in silent mode
 	if !j.Config.Quiet {
 		j.Output.Banner()
 	}
 	// Monitor for SIGTERM and do cleanup properly (writing the output files etc)
-	j.interruptMonitor()
-	for j.jobsInQueue() {
+	if j.HandleSignals {
+		j.interruptMonitor()
+	}
+	for j.jobsInQueue() && j.isRunning() {
 		j.prepareQueueJob()
 		j.Reset(true)
+		j.stateMutex.Lock()
 		j.RunningJob = true
-		j.startExecution()
+		j.stateMutex.Unlock()
+		if j.resumeFrom > 0 {
+			j.Output.Info(fmt.Sprintf("Resuming job from position %d", j.resumeFrom))
+			j.Input.SetPosition(j.resumeFrom)
+			j.Counter = j.resumeFrom - 1
+			j.lastDispatched = j.resumeFrom - 1
+			j.resumeFrom = 0
+		}
+		if j.coordinator != nil {
+			j.startDistributedExecution()
+		} else {
+			j.startExecution()
+		}
+	}
+
+	if j.Config.ResumeFile != "" {
+		if j.isRunning() {
+			// All of the jobs are done, the resume state is not needed anymore
+			_ = os.Remove(j.Config.ResumeFile)
+		} else {
+			j.saveResumeState()
+		}
 	}
 
 	err := j.Output.Finalize()
 	if err != nil {
 		j.Output.Error(err.Error())
 	}
+	return nil
+}
+
+// closeInput closes the inputproviders that hold on to files, like the temporary files of the streamed wordlists
+func (j *Job) closeInput() {
+	// The input of a queued job or a task can be a view over the actual inputproviders
+	input := j.Input
+	if j.replayBase != nil {
+		input = j.replayBase
+	}
+	if j.chunkInput != nil {
+		input = j.chunkInput
+	}
+	if c, ok := input.(io.Closer); ok {
+		if err := c.Close(); err != nil {
+			j.Logger.Printf("Could not close the input: %s", err)
+		}
+	}
 }
 
 // Reset resets the counters and wordlist position for a job
 func (j *Job) Reset(cycle bool) {
 	j.Input.Reset()
+	j.ErrorMutex.Lock()
 	j.Counter = 0
+	j.ErrorMutex.Unlock()
+	j.lastDispatched = 0
+	j.stateMutex.Lock()
 	j.skipQueue = false
+	j.stateMutex.Unlock()
 	j.startTimeJob = time.Now()
 	if cycle {
 		j.Output.Cycle()
@@ -168,29 +380,51 @@ func (j *Job) Reset(cycle bool) {
 }
 
 func (j *Job) jobsInQueue() bool {
+	j.queueMutex.Lock()
+	defer j.queueMutex.Unlock()
 	return j.queuepos < len(j.queuejobs)
 }
 
 func (j *Job) prepareQueueJob() {
-	j.Config.Url = j.queuejobs[j.queuepos].Url
-	j.currentDepth = j.queuejobs[j.queuepos].depth
+	j.queueMutex.Lock()
+	next := j.queuejobs[j.queuepos]
+	j.queueMutex.Unlock()
+	j.Config.Url = next.Url
+	j.currentDepth = next.depth
+	j.currentTemplate = requestTemplateHash(next.req)
+	j.activateKeywords(next.req)
+	if j.replayBase != nil {
+		// Only run the positions of the failed requests of this job
+		j.Input = newPositionInput(j.replayBase, j.replayPositions[replayKey{Url: j.Config.Url, Template: j.currentTemplate}])
+	}
+	j.queueMutex.Lock()
+	j.queuepos += 1
+	j.queueMutex.Unlock()
+	if j.WriteHistory {
+		j.Jobhash, _ = WriteHistoryEntry(j.Config)
+	} else {
+		j.Jobhash, _ = HistoryHash(j.Config)
+	}
+}
 
+// activateKeywords enables the inputproviders of the keywords present in the request of a queued job
+func (j *Job) activateKeywords(req Request) {
 	//Find all keywords present in new queued job
 	kws := j.Input.Keywords()
 	found_kws := make([]string, 0)
 	for _, k := range kws {
-		if RequestContainsKeyword(j.queuejobs[j.queuepos].req, k) {
+		if RequestContainsKeyword(req, k) {
 			found_kws = append(found_kws, k)
 		}
 	}
 	//And activate / disable inputproviders as needed
 	j.Input.ActivateKeywords(found_kws)
-	j.queuepos += 1
-	j.Jobhash, _ = WriteHistoryEntry(j.Config)
 }
 
 // SkipQueue allows to skip the current job and advance to the next queued recursion job
 func (j *Job) SkipQueue() {
+	j.stateMutex.Lock()
+	defer j.stateMutex.Unlock()
 	j.skipQueue = true
 }
 
@@ -214,6 +448,8 @@ func (j *Job) sleepIfNeeded() {
 
 // Pause pauses the job process
 func (j *Job) Pause() {
+	j.stateMutex.Lock()
+	defer j.stateMutex.Unlock()
 	if !j.Paused {
 		j.Paused = true
 		j.pauseWg.Add(1)
@@ -223,6 +459,8 @@ func (j *Job) Pause() {
 
 // Resume resumes the job process
 func (j *Job) Resume() {
+	j.stateMutex.Lock()
+	defer j.stateMutex.Unlock()
 	if j.Paused {
 		j.Paused = false
 		j.Output.Info("------ RESUMING -----")
@@ -235,23 +473,19 @@ func (j *Job) startExecution() {
 	wg.Add(1)
 	go j.runBackgroundTasks(&wg)
 
-	// Print the base URL when starting a new recursion or sniper queue job
-	if j.queuepos > 1 {
-		if j.Config.InputMode == "sniper" {
-			j.Output.Info(fmt.Sprintf("Starting queued sniper job (%d of %d) on target: %s", j.queuepos, len(j.queuejobs), j.Config.Url))
-		} else {
-			j.Output.Info(fmt.Sprintf("Starting queued job on target: %s", j.Config.Url))
-		}
+	// The coordinator has printed the base URL already for the tasks of distributed workers
+	if j.chunkInput == nil {
+		j.printQueueJob()
 	}
 
 	//Limiter blocks after reaching the buffer, ensuring limited concurrency
 	threadlimiter := make(chan bool, j.Config.Threads)
 
-	for j.Input.Next() && !j.skipQueue {
+	for j.Input.Next() && !j.queueSkipped() {
 		// Check if we should stop the process
 		j.CheckStop()
 
-		if !j.Running {
+		if !j.isRunning() {
 			defer j.Output.Warning(j.Error)
 			break
 		}
@@ -266,18 +500,31 @@ func (j *Job) startExecution() {
 		nextInput["FFUFHASH"] = j.ffufHash(nextPosition)
 
 		wg.Add(1)
-		j.Counter++
+		counter := j.incCounter()
+		if j.Config.ResumeFile != "" {
+			j.markInflight(nextPosition)
+		}
+		if j.Config.AutoCalibrationRecheck > 0 && counter%j.Config.AutoCalibrationRecheck == 0 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				j.checkBaselineDrift(nextInput)
+			}()
+		}
 
 		go func() {
 			defer func() { <-threadlimiter }()
 			defer wg.Done()
+			if j.Config.ResumeFile != "" {
+				defer j.unmarkInflight(nextPosition)
+			}
 			threadStart := time.Now()
-			j.runTask(nextInput, nextPosition, false)
+			j.runTask(nextInput, nextPosition)
 			j.sleepIfNeeded()
 			threadEnd := time.Now()
 			j.Rate.Tick(threadStart, threadEnd)
 		}()
-		if !j.RunningJob {
+		if !j.isRunningJob() {
 			defer j.Output.Warning(j.Error)
 			return
 		}
@@ -286,6 +533,17 @@ func (j *Job) startExecution() {
 	j.updateProgress()
 }
 
+// printQueueJob prints the base URL when starting a new recursion, sniper or target queue job
+func (j *Job) printQueueJob() {
+	if j.queuepos > 1 || len(j.Config.Targets) > 1 {
+		if j.Config.InputMode == "sniper" {
+			j.Output.Info(fmt.Sprintf("Starting queued sniper job (%d of %d) on target: %s", j.queuepos, len(j.queuejobs), j.Config.Url))
+		} else {
+			j.Output.Info(fmt.Sprintf("Starting queued job on target: %s", j.Config.Url))
+		}
+	}
+}
+
 func (j *Job) interruptMonitor() {
 	sigChan := make(chan os.Signal, 2)
 	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
@@ -293,9 +551,12 @@ func (j *Job) interruptMonitor() {
 		for range sigChan {
 			j.Error = "Caught keyboard interrupt (Ctrl-C)\n"
 			// resume if paused
+			j.stateMutex.Lock()
 			if j.Paused {
+				j.Paused = false
 				j.pauseWg.Done()
 			}
+			j.stateMutex.Unlock()
 			// Stop the job
 			j.Stop()
 		}
@@ -304,17 +565,19 @@ func (j *Job) interruptMonitor() {
 
 func (j *Job) runBackgroundTasks(wg *sync.WaitGroup) {
 	defer wg.Done()
-	totalProgress := j.Input.Total()
-	for j.Counter <= totalProgress && !j.skipQueue {
+	for j.requestCount() <= j.Input.Total() && !j.queueSkipped() {
 		j.pauseWg.Wait()
-		if !j.Running {
+		if !j.isRunning() {
 			break
 		}
 		j.updateProgress()
-		if j.Counter == totalProgress {
+		if j.Config.ResumeFile != "" && time.Since(j.lastCheckpoint) > RESUME_INTERVAL {
+			j.saveResumeState()
+		}
+		if j.requestCount() == j.Input.Total() {
 			return
 		}
-		if !j.RunningJob {
+		if !j.isRunningJob() {
 			return
 		}
 		time.Sleep(time.Millisecond * time.Duration(j.Config.ProgressFrequency))
@@ -322,14 +585,24 @@ func (j *Job) runBackgroundTasks(wg *sync.WaitGroup) {
 }
 
 func (j *Job) updateProgress() {
+	j.queueMutex.Lock()
+	queuepos, queuetotal := j.queuepos, len(j.queuejobs)
+	j.queueMutex.Unlock()
 	prog := Progress{
 		StartedAt:  j.startTimeJob,
-		ReqCount:   j.Counter,
 		ReqTotal:   j.Input.Total(),
 		ReqSec:     j.Rate.CurrentRate(),
-		QueuePos:   j.queuepos,
-		QueueTotal: len(j.queuejobs),
-		ErrorCount: j.ErrorCounter,
+		QueuePos:   queuepos,
+		QueueTotal: queuetotal,
+	}
+	j.ErrorMutex.Lock()
+	prog.ReqCount = j.Counter
+	prog.ErrorCount = j.ErrorCounter
+	prog.RetryCount = j.RetryCounter
+	prog.FailedCount = j.FailedCounter
+	j.ErrorMutex.Unlock()
+	if j.Config.RateAdaptive {
+		prog.HostRates = j.Rate.HostRates()
 	}
 	j.Output.Progress(prog)
 }
@@ -396,25 +669,42 @@ func (j *Job) ffufHash(pos int) []byte {
 	return []byte(hashstring)
 }
 
-func (j *Job) runTask(input map[string][]byte, position int, retried bool) {
-	basereq := j.queuejobs[j.queuepos-1].req
+func (j *Job) runTask(input map[string][]byte, position int) {
+	basereq := j.currentQueueJob().req
+	generation := 0
+	if j.Session != nil {
+		basereq, generation = j.Session.Apply(&basereq)
+	}
 	req, err := j.Runner.Prepare(input, &basereq)
 	req.Position = position
 	if err != nil {
 		j.Output.Error(fmt.Sprintf("Encountered an error while preparing request: %s\n", err))
 		j.incError()
-		log.Printf("%s", err)
+		j.recordError(input, position, "", err)
+		j.Logger.Printf("%s", err)
 		return
 	}
 
-	resp, err := j.Runner.Execute(&req)
-	if err != nil {
-		if retried {
-			j.incError()
-			log.Printf("%s", err)
-		} else {
-			j.runTask(input, position, true)
+	resp, err := j.executeWithRetries(&req)
+	if err == nil && j.Session != nil && j.Session.Expired(&resp) {
+		// The session has expired, log in again and resend the request with the new session
+		renewed, serr := j.Session.Refresh(j.Runner, generation)
+		if serr != nil {
+			j.Output.Error(fmt.Sprintf("Could not log in again with the session request: %s", serr))
+		} else if renewed {
+			queuereq := j.currentQueueJob().req
+			basereq, _ = j.Session.Apply(&queuereq)
+			req, err = j.Runner.Prepare(input, &basereq)
+			req.Position = position
+			if err == nil {
+				resp, err = j.executeWithRetries(&req)
+			}
 		}
+	}
+	if err != nil {
+		j.incError()
+		j.recordError(input, position, req.Url, err)
+		j.Logger.Printf("%s", err)
 		if os.IsTimeout(err) {
 			for name := range j.Config.MatcherManager.GetMatchers() {
 				if name == "time" {
@@ -475,7 +765,7 @@ func (j *Job) runTask(input map[string][]byte, position int, retried bool) {
 			if err != nil {
 				j.Output.Error(fmt.Sprintf("Encountered an error while preparing replayproxy request: %s\n", err))
 				j.incError()
-				log.Printf("%s", err)
+				j.Logger.Printf("%s", err)
 			} else {
 				_, _ = j.ReplayRunner.Execute(&replayreq)
 			}
@@ -491,6 +781,8 @@ func (j *Job) runTask(input map[string][]byte, position int, retried bool) {
 		if len(resp.ScraperData) > 0 {
 			// print the result anyway, as scraper found something
 			j.Output.Result(resp)
+		} else if rec, ok := j.Output.(ResponseRecorder); ok {
+			rec.Record(resp)
 		}
 	}
 
@@ -511,10 +803,9 @@ func (j *Job) handleScraperResult(resp *Response, sres ScraperResult) {
 ///////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 // handleGreedyRecursionJob adds a recursion job to the queue if the maximum depth has not been reached
 func (j *Job) handleGreedyRecursionJob(resp Response) {
-	if ((j.Config.RecursionDepth == 0 || j.currentDepth < j.Config.RecursionDepth) && !fileExtensions.MatchString(resp.Request.Url)) && (resp.StatusCode != 400 || resp.StatusCode != 404) {
+	if ((j.Config.RecursionDepth == 0 || j.currentDepth < j.Config.RecursionDepth) && !fileExtensions.MatchString(resp.Request.Url)) && (resp.StatusCode != 400 && resp.StatusCode != 404) {
 		recUrl := resp.Request.Url + "/" + "FUZZ"
-		newJob := QueueJob{Url: recUrl, depth: j.currentDepth + 1, req: RecursionRequest(j.Config, recUrl)}
-		j.queuejobs = append(j.queuejobs, newJob)
+		j.addQueueJob(QueueJob{Url: recUrl, depth: j.currentDepth + 1, req: RecursionRequest(j.Config, recUrl)})
 		j.Output.Info(fmt.Sprintf("Adding a new job to the queue: %s", recUrl))
 	} else {
 		j.Output.Warning(fmt.Sprintf("Maximum recursion depth reached. Ignoring: %s", resp.Request.Url))
@@ -533,24 +824,27 @@ func (j *Job) handleDefaultRecursionJob(resp Response) {
 
 // CheckStop stops the job if stopping conditions are met
 func (j *Job) CheckStop() {
-	if j.Counter > 50 {
+	j.ErrorMutex.Lock()
+	counter, count403, count429, spurious := j.Counter, j.Count403, j.Count429, j.SpuriousErrorCounter
+	j.ErrorMutex.Unlock()
+	if counter > 50 {
 		// We have enough samples
 		if j.Config.StopOn403 || j.Config.StopOnAll {
-			if float64(j.Count403)/float64(j.Counter) > 0.95 {
+			if float64(count403)/float64(counter) > 0.95 {
 				// Over 95% of requests are 403
 				j.Error = "Getting an unusual amount of 403 responses, exiting."
 				j.Stop()
 			}
 		}
 		if j.Config.StopOnErrors || j.Config.StopOnAll {
-			if j.SpuriousErrorCounter > j.Config.Threads*2 {
+			if spurious > j.Config.Threads*2 {
 				// Most of the requests are erroring
 				j.Error = "Receiving spurious errors, exiting."
 				j.Stop()
 			}
 
 		}
-		if j.Config.StopOnAll && (float64(j.Count429)/float64(j.Counter) > 0.2) {
+		if j.Config.StopOnAll && (float64(count429)/float64(counter) > 0.2) {
 			// Over 20% of responses are 429
 			j.Error = "Getting an unusual amount of 429 responses, exiting."
 			j.Stop()
@@ -581,11 +875,15 @@ func (j *Job) CheckStop() {
 
 // Stop the execution of the Job
 func (j *Job) Stop() {
+	j.stateMutex.Lock()
 	j.Running = false
+	j.stateMutex.Unlock()
 	j.Config.Cancel()
 }
 
 // Stop current, resume to next
 func (j *Job) Next() {
+	j.stateMutex.Lock()
+	defer j.stateMutex.Unlock()
 	j.RunningJob = false
 }
@@ -2,13 +2,16 @@ package ffuf
 
 import (
 	"bufio"
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/textproto"
 	"net/url"
 	"os"
 	"path/filepath"
+	"regexp"
 	"runtime"
 	"strconv"
 	"strings"
@@ -26,7 +29,12 @@ type ConfigOptions struct {
 }
 
 type HTTPOptions struct {
+	CookieJar           string   `json:"cookie_jar"`
+	CookieJarFile       string   `json:"cookie_jar_file"`
 	Cookies             []string `json:"-"` // this is appended in headers
+	CSRFKeyword         string   `json:"csrf_keyword"`
+	CSRFRule            string   `json:"csrf_rule"`
+	CSRFURL             string   `json:"csrf_url"`
 	Data                string   `json:"data"`
 	FollowRedirects     bool     `json:"follow_redirects"`
 	Headers             []string `json:"headers"`
@@ -34,14 +42,24 @@ type HTTPOptions struct {
 	Method              string   `json:"method"`
 	ProxyURL            string   `json:"proxy_url"`
 	Raw                 bool     `json:"raw"`
+	RawSocket           bool     `json:"raw_socket"`
 	Recursion           bool     `json:"recursion"`
 	RecursionDepth      int      `json:"recursion_depth"`
 	RecursionStrategy   string   `json:"recursion_strategy"`
 	ReplayProxyURL      string   `json:"replay_proxy_url"`
+	Retries             int      `json:"retries"`
+	RetryBackoff        float64  `json:"retry_backoff"`
+	RetryOn             string   `json:"retry_on"`
+	SessionExtract      []string `json:"session_extract"`
+	SessionMatchRegexp  string   `json:"session_match_regexp"`
+	SessionMatchStatus  string   `json:"session_match_status"`
+	SessionRequest      string   `json:"session_request"`
 	SNI                 string   `json:"sni"`
 	Timeout             int      `json:"timeout"`
 	URL                 string   `json:"url"`
+	URLList             string   `json:"url_list"`
 	Http2               bool     `json:"http2"`
+	Http3               bool     `json:"http3"`
 	ClientCert          string   `json:"client-cert"`
 	ClientKey           string   `json:"client-key"`
 }
@@ -50,10 +68,14 @@ type GeneralOptions struct {
 	AutoCalibration           bool     `json:"autocalibration"`
 	AutoCalibrationKeyword    string   `json:"autocalibration_keyword"`
 	AutoCalibrationPerHost    bool     `json:"autocalibration_per_host"`
+	AutoCalibrationRecheck    int      `json:"autocalibration_recheck"`
 	AutoCalibrationStrategies []string `json:"autocalibration_strategies"`
 	AutoCalibrationStrings    []string `json:"autocalibration_strings"`
+	ChunkSize                 int      `json:"chunk_size"`
 	Colors                    bool     `json:"colors"`
 	ConfigFile                string   `toml:"-" json:"config_file"`
+	Coordinator               string   `json:"coordinator"`
+	CoordinatorSecret         string   `json:"coordinator_secret"`
 	Delay                     string   `json:"delay"`
 	Json                      bool     `json:"json"`
 	MaxTime                   int      `json:"maxtime"`
@@ -61,6 +83,8 @@ type GeneralOptions struct {
 	Noninteractive            bool     `json:"noninteractive"`
 	Quiet                     bool     `json:"quiet"`
 	Rate                      int      `json:"rate"`
+	RateAdaptive              bool     `json:"rate_adaptive"`
+	Resume                    string   `json:"resume"`
 	ScraperFile               string   `json:"scraperfile"`
 	Scrapers                  string   `json:"scrapers"`
 	Searchhash                string   `json:"-"`
@@ -77,31 +101,44 @@ type InputOptions struct {
 	Encoders               []string `json:"encoders"`
 	Extensions             string   `json:"extensions"`
 	IgnoreWordlistComments bool     `json:"ignore_wordlist_comments"`
+	InputCmdMode           string   `json:"input_cmd_mode"`
+	InputCmdNul            bool     `json:"input_cmd_nul"`
 	InputMode              string   `json:"input_mode"`
 	InputNum               int      `json:"input_num"`
 	InputShell             string   `json:"input_shell"`
 	Inputcommands          []string `json:"input_commands"`
+	MaskCharsets           []string `json:"mask_charsets"`
+	Masks                  []string `json:"masks"`
+	Ranges                 []string `json:"ranges"`
+	ReplayErrors           string   `json:"replay_errors"`
 	Request                string   `json:"request_file"`
 	RequestProto           string   `json:"request_proto"`
+	Rules                  []string `json:"rules"`
 	Wordlists              []string `json:"wordlists"`
+	WordlistStream         bool     `json:"wordlist_stream"`
 }
 
 type OutputOptions struct {
-	DebugLog            string `json:"debug_log"`
-	OutputDirectory     string `json:"output_directory"`
-	OutputFile          string `json:"output_file"`
-	OutputFormat        string `json:"output_format"`
-	OutputSkipEmptyFile bool   `json:"output_skip_empty"`
+	DebugLog            string   `json:"debug_log"`
+	ErrorFile           string   `json:"error_file"`
+	HarAll              bool     `json:"har_all"`
+	OutputDirectory     string   `json:"output_directory"`
+	OutputFile          string   `json:"output_file"`
+	OutputFormat        string   `json:"output_format"`
+	OutputProviders     []string `json:"output_providers"`
+	OutputSkipEmptyFile bool     `json:"output_skip_empty"`
+	SqliteBodies        bool     `json:"sqlite_bodies"`
 }
 
 type FilterOptions struct {
-	Mode   string `json:"mode"`
-	Lines  string `json:"lines"`
-	Regexp string `json:"regexp"`
-	Size   string `json:"size"`
-	Status string `json:"status"`
-	Time   string `json:"time"`
-	Words  string `json:"words"`
+	Mode       string `json:"mode"`
+	Lines      string `json:"lines"`
+	Regexp     string `json:"regexp"`
+	Similarity string `json:"similarity"`
+	Size       string `json:"size"`
+	Status     string `json:"status"`
+	Time       string `json:"time"`
+	Words      string `json:"words"`
 }
 
 type MatcherOptions struct {
@@ -120,6 +157,7 @@ func NewConfigOptions() *ConfigOptions {
 	c.Filter.Mode = "or"
 	c.Filter.Lines = ""
 	c.Filter.Regexp = ""
+	c.Filter.Similarity = ""
 	c.Filter.Size = ""
 	c.Filter.Status = ""
 	c.Filter.Time = ""
@@ -127,7 +165,10 @@ func NewConfigOptions() *ConfigOptions {
 	c.General.AutoCalibration = false
 	c.General.AutoCalibrationKeyword = "FUZZ"
 	c.General.AutoCalibrationStrategies = []string{"basic"}
+	c.General.ChunkSize = 1000
 	c.General.Colors = false
+	c.General.Coordinator = ""
+	c.General.CoordinatorSecret = ""
 	c.General.Delay = ""
 	c.General.Json = false
 	c.General.MaxTime = 0
@@ -135,6 +176,8 @@ func NewConfigOptions() *ConfigOptions {
 	c.General.Noninteractive = false
 	c.General.Quiet = false
 	c.General.Rate = 0
+	c.General.RateAdaptive = false
+	c.General.Resume = ""
 	c.General.Searchhash = ""
 	c.General.ScraperFile = ""
 	c.General.Scrapers = "all"
@@ -144,28 +187,51 @@ func NewConfigOptions() *ConfigOptions {
 	c.General.StopOnErrors = false
 	c.General.Threads = 40
 	c.General.Verbose = false
+	c.HTTP.CookieJar = ""
+	c.HTTP.CookieJarFile = ""
+	c.HTTP.CSRFKeyword = "CSRF"
+	c.HTTP.CSRFRule = ""
+	c.HTTP.CSRFURL = ""
 	c.HTTP.Data = ""
 	c.HTTP.FollowRedirects = false
 	c.HTTP.IgnoreBody = false
 	c.HTTP.Method = ""
 	c.HTTP.ProxyURL = ""
 	c.HTTP.Raw = false
+	c.HTTP.RawSocket = false
 	c.HTTP.Recursion = false
 	c.HTTP.RecursionDepth = 0
 	c.HTTP.RecursionStrategy = "default"
 	c.HTTP.ReplayProxyURL = ""
+	c.HTTP.Retries = 1
+	c.HTTP.RetryBackoff = 0
+	c.HTTP.RetryOn = ""
+	c.HTTP.SessionExtract = []string{}
+	c.HTTP.SessionMatchRegexp = ""
+	c.HTTP.SessionMatchStatus = ""
+	c.HTTP.SessionRequest = ""
 	c.HTTP.Timeout = 10
 	c.HTTP.SNI = ""
 	c.HTTP.URL = ""
+	c.HTTP.URLList = ""
 	c.HTTP.Http2 = false
+	c.HTTP.Http3 = false
 	c.Input.DirSearchCompat = false
 	c.Input.Encoders = []string{}
 	c.Input.Extensions = ""
 	c.Input.IgnoreWordlistComments = false
+	c.Input.InputCmdMode = "exec"
+	c.Input.InputCmdNul = false
 	c.Input.InputMode = "clusterbomb"
 	c.Input.InputNum = 100
+	c.Input.MaskCharsets = []string{}
+	c.Input.Masks = []string{}
+	c.Input.Ranges = []string{}
+	c.Input.ReplayErrors = ""
 	c.Input.Request = ""
 	c.Input.RequestProto = "https"
+	c.Input.Rules = []string{}
+	c.Input.WordlistStream = false
 	c.Matcher.Mode = "or"
 	c.Matcher.Lines = ""
 	c.Matcher.Regexp = ""
@@ -174,10 +240,14 @@ func NewConfigOptions() *ConfigOptions {
 	c.Matcher.Time = ""
 	c.Matcher.Words = ""
 	c.Output.DebugLog = ""
+	c.Output.ErrorFile = ""
+	c.Output.HarAll = false
 	c.Output.OutputDirectory = ""
 	c.Output.OutputFile = ""
 	c.Output.OutputFormat = "json"
+	c.Output.OutputProviders = []string{}
 	c.Output.OutputSkipEmptyFile = false
+	c.Output.SqliteBodies = false
 	return c
 }
 
@@ -190,8 +260,8 @@ func ConfigFromOptions(parseOpts *ConfigOptions, ctx context.Context, cancel con
 
 	var err error
 	var err2 error
-	if len(parseOpts.HTTP.URL) == 0 && parseOpts.Input.Request == "" {
-		errs.Add(fmt.Errorf("-u flag or -request flag is required"))
+	if len(parseOpts.HTTP.URL) == 0 && parseOpts.Input.Request == "" && parseOpts.HTTP.URLList == "" {
+		errs.Add(fmt.Errorf("-u flag, -u-list flag or -request flag is required"))
 	}
 
 	// prepare extensions
@@ -217,6 +287,9 @@ func ConfigFromOptions(parseOpts *ConfigOptions, ctx context.Context, cancel con
 	if !validmode {
 		errs.Add(fmt.Errorf("Input mode (-mode) %s not recognized", conf.InputMode))
 	}
+	if parseOpts.Input.InputCmdMode != "exec" && parseOpts.Input.InputCmdMode != "stream" && parseOpts.Input.InputCmdMode != "request" {
+		errs.Add(fmt.Errorf("Input command mode (-input-cmd-mode) %s not recognized. Available modes: exec, stream, request", parseOpts.Input.InputCmdMode))
+	}
 
 	template := ""
 	// sniper mode needs some additional checking
@@ -230,6 +303,10 @@ func ConfigFromOptions(parseOpts *ConfigOptions, ctx context.Context, cancel con
 		if len(parseOpts.Input.Inputcommands) > 1 {
 			errs.Add(fmt.Errorf("sniper mode only supports one input command"))
 		}
+
+		if len(parseOpts.Input.Ranges)+len(parseOpts.Input.Masks) > 1 {
+			errs.Add(fmt.Errorf("sniper mode only supports one range or mask"))
+		}
 	}
 	tmpEncoders := make(map[string]string)
 	for _, e := range parseOpts.Input.Encoders {
@@ -342,8 +419,59 @@ func ConfigFromOptions(parseOpts *ConfigOptions, ctx context.Context, cancel con
 		}
 	}
 
+	// Ranges and masks are generated from the position, so they are given the same way as the input commands
+	generators := make([]InputProviderConfig, 0)
+	for _, v := range parseOpts.Input.Ranges {
+		generators = append(generators, InputProviderConfig{Name: "range", Value: v})
+	}
+	for _, v := range parseOpts.Input.Masks {
+		generators = append(generators, InputProviderConfig{Name: "mask", Value: v})
+	}
+	for _, newp := range generators {
+		// Masks can contain colons, so the keyword is the part after the last one
+		if idx := strings.LastIndex(newp.Value, ":"); idx != -1 && !strings.Contains(newp.Value[idx+1:], "?") {
+			if conf.InputMode == "sniper" {
+				errs.Add(fmt.Errorf("sniper mode does not support %s keywords", newp.Name))
+				continue
+			}
+			newp.Keyword = newp.Value[idx+1:]
+			newp.Value = newp.Value[:idx]
+		} else {
+			newp.Keyword = "FUZZ"
+			newp.Template = template
+		}
+		enc, ok := tmpEncoders[newp.Keyword]
+		if ok {
+			newp.Encoders = enc
+		}
+		conf.InputProviders = append(conf.InputProviders, newp)
+	}
+	conf.MaskCharsets = parseOpts.Input.MaskCharsets
+
+	// Attach the mutation rules to the inputs of their keywords
+	for _, v := range parseOpts.Input.Rules {
+		keyword, filename, found := strings.Cut(v, ":")
+		if !found || keyword == "" || filename == "" {
+			errs.Add(fmt.Errorf("Invalid rules (-rules) %s, expected KEYWORD:FILE", v))
+			continue
+		}
+		if fullpath, err := filepath.Abs(filename); err == nil {
+			filename = fullpath
+		}
+		found = false
+		for i := range conf.InputProviders {
+			if conf.InputProviders[i].Keyword == keyword {
+				conf.InputProviders[i].Rules = filename
+				found = true
+			}
+		}
+		if !found {
+			errs.Add(fmt.Errorf("Rules (-rules) defined for keyword %s, but it has no input", keyword))
+		}
+	}
+
 	if len(conf.InputProviders) == 0 {
-		errs.Add(fmt.Errorf("Either -w or --input-cmd flag is required"))
+		errs.Add(fmt.Errorf("Either -w, --input-cmd, -range or -mask flag is required"))
 	}
 
 	// Prepare the request using body
@@ -351,7 +479,7 @@ func ConfigFromOptions(parseOpts *ConfigOptions, ctx context.Context, cancel con
 		err := parseRawRequest(parseOpts, &conf)
 		if err != nil {
 			errmsg := fmt.Sprintf("Could not parse raw request: %s", err)
-			errs.Add(fmt.Errorf(errmsg))
+			errs.Add(errors.New(errmsg))
 		}
 	}
 
@@ -360,6 +488,22 @@ func ConfigFromOptions(parseOpts *ConfigOptions, ctx context.Context, cancel con
 		conf.Url = parseOpts.HTTP.URL
 	}
 
+	// Prepare the list of targets
+	if parseOpts.HTTP.URLList != "" {
+		if parseOpts.HTTP.URL != "" || parseOpts.Input.Request != "" {
+			errs.Add(fmt.Errorf("Target list (-u-list) can not be used with -u or -request"))
+		}
+		conf.TargetFile = parseOpts.HTTP.URLList
+		conf.Targets, err = readTargets(parseOpts.HTTP.URLList, conf.InputProviders)
+		if err != nil {
+			errs.Add(fmt.Errorf("Could not read the target list (-u-list): %s", err))
+		} else if len(conf.Targets) == 0 {
+			errs.Add(fmt.Errorf("Target list (-u-list) does not contain any URLs"))
+		} else {
+			conf.Url = conf.Targets[0]
+		}
+	}
+
 	// Prepare SNI
 	if parseOpts.HTTP.SNI != "" {
 		conf.SNI = parseOpts.HTTP.SNI
@@ -445,10 +589,62 @@ func ConfigFromOptions(parseOpts *ConfigOptions, ctx context.Context, cancel con
 		}
 	}
 
+	// Verify the retry policy
+	if parseOpts.HTTP.Retries < 0 {
+		errs.Add(fmt.Errorf("Number of retries (-retries) can not be negative"))
+	} else {
+		conf.Retries = parseOpts.HTTP.Retries
+	}
+	if parseOpts.HTTP.RetryBackoff < 0 {
+		errs.Add(fmt.Errorf("Retry backoff (-retry-backoff) can not be negative"))
+	} else {
+		conf.RetryBackoff = parseOpts.HTTP.RetryBackoff
+	}
+	if parseOpts.HTTP.RetryOn != "" {
+		conf.RetryOnStatus, err = parseStatusRanges(parseOpts.HTTP.RetryOn)
+		if err != nil {
+			errs.Add(fmt.Errorf("Bad status code for retries (-retry-on): %s", err))
+		}
+	}
+
+	// Session handling
+	if parseOpts.HTTP.SessionRequest != "" {
+		req, err := ReadRawRequest(parseOpts.HTTP.SessionRequest, parseOpts.Input.RequestProto)
+		if err != nil {
+			errs.Add(fmt.Errorf("Could not read the login request (-session-request): %s", err))
+		} else {
+			conf.SessionRequestFile = parseOpts.HTTP.SessionRequest
+			conf.SessionRequest = &req
+		}
+		for _, v := range parseOpts.HTTP.SessionExtract {
+			if _, err := ParseSessionExtractor(v); err != nil {
+				errs.Add(fmt.Errorf("Bad session value (-session-extract): %s", err))
+			}
+		}
+		conf.SessionExtract = parseOpts.HTTP.SessionExtract
+		if parseOpts.HTTP.SessionMatchStatus != "" {
+			conf.SessionMatchStatus, err = parseStatusRanges(parseOpts.HTTP.SessionMatchStatus)
+			if err != nil {
+				errs.Add(fmt.Errorf("Bad logged out status code (-session-mc): %s", err))
+			}
+		}
+		if parseOpts.HTTP.SessionMatchRegexp != "" {
+			if _, err := regexp.Compile(parseOpts.HTTP.SessionMatchRegexp); err != nil {
+				errs.Add(fmt.Errorf("Bad logged out regexp (-session-mr): %s", err))
+			}
+			conf.SessionMatchRegexp = parseOpts.HTTP.SessionMatchRegexp
+		}
+		if parseOpts.HTTP.RawSocket {
+			errs.Add(fmt.Errorf("Session handling (-session-request) can not be used with -raw-socket"))
+		}
+	} else if len(parseOpts.HTTP.SessionExtract) > 0 || parseOpts.HTTP.SessionMatchStatus != "" || parseOpts.HTTP.SessionMatchRegexp != "" {
+		errs.Add(fmt.Errorf("Session options -session-extract, -session-mc and -session-mr require a login request (-session-request)"))
+	}
+
 	//Check the output file format option
 	if parseOpts.Output.OutputFile != "" {
 		//No need to check / error out if output file isn't defined
-		outputFormats := []string{"all", "json", "ejson", "html", "md", "csv", "ecsv"}
+		outputFormats := []string{"all", "json", "ejson", "html", "md", "csv", "ecsv", "jsonl", "sarif", "junit", "burp", "har"}
 		found := false
 		for _, f := range outputFormats {
 			if f == parseOpts.Output.OutputFormat {
@@ -460,6 +656,20 @@ func ConfigFromOptions(parseOpts *ConfigOptions, ctx context.Context, cancel con
 			errs.Add(fmt.Errorf("Unknown output file format (-of): %s", parseOpts.Output.OutputFormat))
 		}
 	}
+	if parseOpts.Output.HarAll && (parseOpts.Output.OutputFile == "" || (parseOpts.Output.OutputFormat != "har" && parseOpts.Output.OutputFormat != "all")) {
+		errs.Add(fmt.Errorf("Recording all of the requests (-har-all) requires a HAR output file: -o with -of har or -of all"))
+	}
+	if parseOpts.Output.SqliteBodies {
+		found := false
+		for _, provider := range parseOpts.Output.OutputProviders {
+			if provider == "sqlite" || strings.HasPrefix(provider, "sqlite:") {
+				found = true
+			}
+		}
+		if !found {
+			errs.Add(fmt.Errorf("Storing the bodies (-sqlite-bodies) requires the sqlite output provider: -output-provider sqlite:FILE"))
+		}
+	}
 
 	// Auto-calibration strings
 	if len(parseOpts.General.AutoCalibrationStrings) > 0 {
@@ -483,6 +693,7 @@ func ConfigFromOptions(parseOpts *ConfigOptions, ctx context.Context, cancel con
 	} else {
 		conf.Rate = int64(parseOpts.General.Rate)
 	}
+	conf.RateAdaptive = parseOpts.General.RateAdaptive
 
 	if conf.Method == "" {
 		if parseOpts.HTTP.Method == "" {
@@ -508,15 +719,41 @@ func ConfigFromOptions(parseOpts *ConfigOptions, ctx context.Context, cancel con
 	// Common stuff
 	conf.IgnoreWordlistComments = parseOpts.Input.IgnoreWordlistComments
 	conf.DirSearchCompat = parseOpts.Input.DirSearchCompat
+	conf.StreamWordlists = parseOpts.Input.WordlistStream
 	conf.Colors = parseOpts.General.Colors
 	conf.InputNum = parseOpts.Input.InputNum
 
 	conf.InputShell = parseOpts.Input.InputShell
+	conf.InputCmdMode = parseOpts.Input.InputCmdMode
+	conf.InputCmdNul = parseOpts.Input.InputCmdNul
 	conf.OutputFile = parseOpts.Output.OutputFile
 	conf.OutputDirectory = parseOpts.Output.OutputDirectory
+	conf.OutputProviders = parseOpts.Output.OutputProviders
 	conf.OutputSkipEmptyFile = parseOpts.Output.OutputSkipEmptyFile
+	conf.HarAll = parseOpts.Output.HarAll
+	conf.SqliteBodies = parseOpts.Output.SqliteBodies
 	conf.IgnoreBody = parseOpts.HTTP.IgnoreBody
 	conf.Quiet = parseOpts.General.Quiet
+	conf.ResumeFile = parseOpts.General.Resume
+	conf.ErrorFile = parseOpts.Output.ErrorFile
+	conf.ReplayErrors = parseOpts.Input.ReplayErrors
+	if conf.ReplayErrors != "" && conf.ResumeFile != "" {
+		errs.Add(fmt.Errorf("Replaying failed requests (-replay-errors) can not be used with -resume"))
+	}
+	conf.Coordinator = parseOpts.General.Coordinator
+	conf.ChunkSize = parseOpts.General.ChunkSize
+	conf.CoordinatorSecret = parseOpts.General.CoordinatorSecret
+	if conf.CoordinatorSecret == "" {
+		conf.CoordinatorSecret = os.Getenv(COORDINATOR_SECRET_ENV)
+	}
+	if conf.Coordinator != "" {
+		if conf.ResumeFile != "" || conf.ReplayErrors != "" {
+			errs.Add(fmt.Errorf("Distributed scanning (-coordinator) can not be used with -resume or -replay-errors"))
+		}
+		if conf.ChunkSize < 1 {
+			errs.Add(fmt.Errorf("Chunk size (-chunk-size) must be at least 1"))
+		}
+	}
 	conf.ScraperFile = parseOpts.General.ScraperFile
 	conf.Scrapers = parseOpts.General.Scrapers
 	conf.StopOn403 = parseOpts.General.StopOn403
@@ -529,7 +766,13 @@ func ConfigFromOptions(parseOpts *ConfigOptions, ctx context.Context, cancel con
 	conf.RecursionStrategy = parseOpts.HTTP.RecursionStrategy
 	conf.AutoCalibration = parseOpts.General.AutoCalibration
 	conf.AutoCalibrationPerHost = parseOpts.General.AutoCalibrationPerHost
+	conf.AutoCalibrationRecheck = parseOpts.General.AutoCalibrationRecheck
 	conf.AutoCalibrationStrategies = parseOpts.General.AutoCalibrationStrategies
+	if parseOpts.Filter.Similarity != "" && !similarityHasBaseline(parseOpts.Filter.Similarity) {
+		if !conf.AutoCalibration && len(parseOpts.General.AutoCalibrationStrings) == 0 {
+			errs.Add(fmt.Errorf("Similarity filter (-fsim) needs a baseline file or -ac"))
+		}
+	}
 	conf.Threads = parseOpts.General.Threads
 	conf.Timeout = parseOpts.HTTP.Timeout
 	conf.MaxTime = parseOpts.General.MaxTime
@@ -538,6 +781,74 @@ func ConfigFromOptions(parseOpts *ConfigOptions, ctx context.Context, cancel con
 	conf.Verbose = parseOpts.General.Verbose
 	conf.Json = parseOpts.General.Json
 	conf.Http2 = parseOpts.HTTP.Http2
+	conf.Http3 = parseOpts.HTTP.Http3
+	if conf.Http3 {
+		if conf.Http2 {
+			errs.Add(fmt.Errorf("Cannot have -http2 and -http3"))
+		}
+		if len(conf.ProxyURL) > 0 {
+			errs.Add(fmt.Errorf("HTTP/3 (-http3) can not be used with a proxy (-x)"))
+		}
+	}
+	conf.RawSocket = parseOpts.HTTP.RawSocket
+	if conf.RawSocket {
+		if parseOpts.Input.Request == "" {
+			errs.Add(fmt.Errorf("Raw socket mode (-raw-socket) requires a request file (-request)"))
+		} else {
+			conf.RequestTemplate, err = os.ReadFile(parseOpts.Input.Request)
+			if err != nil {
+				errs.Add(fmt.Errorf("Could not read request file: %s", err))
+			}
+		}
+		if len(conf.ProxyURL) > 0 || conf.Http2 || conf.Http3 {
+			errs.Add(fmt.Errorf("Raw socket mode (-raw-socket) can not be used with -x, -http2 or -http3"))
+		}
+		if conf.Recursion {
+			errs.Add(fmt.Errorf("Raw socket mode (-raw-socket) can not be used with -recursion"))
+		}
+	}
+
+	// Cookie jar
+	conf.CookieJar = parseOpts.HTTP.CookieJar
+	if parseOpts.HTTP.CookieJarFile != "" {
+		if _, err := ReadCookieFile(parseOpts.HTTP.CookieJarFile); err != nil {
+			errs.Add(fmt.Errorf("Could not read the cookie file (-cookie-jar-file): %s", err))
+		}
+		conf.CookieJarFile = parseOpts.HTTP.CookieJarFile
+		if conf.CookieJar == "" {
+			// A cookie file implies a shared cookie jar
+			conf.CookieJar = "shared"
+		}
+	}
+	if conf.CookieJar != "" && conf.CookieJar != "shared" && conf.CookieJar != "thread" {
+		errs.Add(fmt.Errorf("Unrecognized value for parameter cookie-jar: %s, valid values are: shared, thread", conf.CookieJar))
+	}
+	if conf.CookieJar != "" && conf.RawSocket {
+		errs.Add(fmt.Errorf("Cookie jar (-cookie-jar) can not be used with -raw-socket"))
+	}
+
+	// Anti-CSRF token refresh
+	if parseOpts.HTTP.CSRFURL != "" {
+		conf.CSRFUrl = parseOpts.HTTP.CSRFURL
+		conf.CSRFRule = parseOpts.HTTP.CSRFRule
+		conf.CSRFKeyword = parseOpts.HTTP.CSRFKeyword
+		ruletype, rule, found := strings.Cut(conf.CSRFRule, ":")
+		if !found || rule == "" || (ruletype != "regexp" && ruletype != "query") {
+			errs.Add(fmt.Errorf("CSRF token rule (-csrf-rule) must be in format regexp:<regexp> or query:<selector>"))
+		} else if ruletype == "regexp" {
+			if _, err := regexp.Compile(rule); err != nil {
+				errs.Add(fmt.Errorf("Bad CSRF token regexp (-csrf-rule): %s", err))
+			}
+		}
+		if conf.CSRFKeyword == "" || !keywordPresent(conf.CSRFKeyword, &conf) {
+			errs.Add(fmt.Errorf("CSRF keyword %s (-csrf-keyword) not found in the url, headers or post data", conf.CSRFKeyword))
+		}
+		if conf.RawSocket || conf.IgnoreBody {
+			errs.Add(fmt.Errorf("CSRF token refresh (-csrf-url) can not be used with -raw-socket or -ignore-body"))
+		}
+	} else if parseOpts.HTTP.CSRFRule != "" {
+		errs.Add(fmt.Errorf("CSRF token rule (-csrf-rule) requires the url to fetch the token from (-csrf-url)"))
+	}
 
 	// Check that fmode and mmode have sane values
 	valid_opmodes := []string{"and", "or"}
@@ -553,11 +864,11 @@ func ConfigFromOptions(parseOpts *ConfigOptions, ctx context.Context, cancel con
 	}
 	if !fmode_found {
 		errmsg := fmt.Sprintf("Unrecognized value for parameter fmode: %s, valid values are: and, or", parseOpts.Filter.Mode)
-		errs.Add(fmt.Errorf(errmsg))
+		errs.Add(errors.New(errmsg))
 	}
 	if !mmode_found {
 		errmsg := fmt.Sprintf("Unrecognized value for parameter mmode: %s, valid values are: and, or", parseOpts.Matcher.Mode)
-		errs.Add(fmt.Errorf(errmsg))
+		errs.Add(errors.New(errmsg))
 	}
 	conf.FilterMode = parseOpts.Filter.Mode
 	conf.MatcherMode = parseOpts.Matcher.Mode
@@ -566,6 +877,14 @@ func ConfigFromOptions(parseOpts *ConfigOptions, ctx context.Context, cancel con
 		// AutoCalibrationPerHost implies AutoCalibration
 		conf.AutoCalibration = true
 	}
+	if conf.AutoCalibrationRecheck > 0 {
+		// AutoCalibrationRecheck implies AutoCalibration
+		conf.AutoCalibration = true
+	}
+	if conf.AutoCalibration && len(conf.Targets) > 1 {
+		// Calibrate each of the targets separately
+		conf.AutoCalibrationPerHost = true
+	}
 
 	// Handle copy as curl situation where POST method is implied by --data flag. If method is set to anything but GET, NOOP
 	if len(conf.Data) > 0 &&
@@ -583,14 +902,14 @@ func ConfigFromOptions(parseOpts *ConfigOptions, ctx context.Context, cancel con
 		if provider.Template != "" {
 			if !templatePresent(provider.Template, &conf) {
 				errmsg := fmt.Sprintf("Template %s defined, but not found in pairs in headers, method, URL or POST data.", provider.Template)
-				errs.Add(fmt.Errorf(errmsg))
+				errs.Add(errors.New(errmsg))
 			} else {
 				newInputProviders = append(newInputProviders, provider)
 			}
 		} else {
 			if !keywordPresent(provider.Keyword, &conf) {
 				errmsg := fmt.Sprintf("Keyword %s defined, but not found in headers, method, URL or POST data.", provider.Keyword)
-				_, _ = fmt.Fprintf(os.Stderr, "%s\n", fmt.Errorf(errmsg))
+				_, _ = fmt.Fprintf(os.Stderr, "%s\n", errors.New(errmsg))
 			} else {
 				newInputProviders = append(newInputProviders, provider)
 			}
@@ -609,7 +928,13 @@ func ConfigFromOptions(parseOpts *ConfigOptions, ctx context.Context, cancel con
 	if parseOpts.HTTP.Recursion {
 		if !strings.HasSuffix(conf.Url, "FUZZ") {
 			errmsg := "When using -recursion the URL (-u) must end with FUZZ keyword."
-			errs.Add(fmt.Errorf(errmsg))
+			errs.Add(errors.New(errmsg))
+		}
+		for _, target := range conf.Targets {
+			if !strings.HasSuffix(target, "FUZZ") {
+				errs.Add(fmt.Errorf("When using -recursion the targets (-u-list) must end with FUZZ keyword: %s", target))
+				break
+			}
 		}
 	}
 
@@ -623,9 +948,68 @@ func ConfigFromOptions(parseOpts *ConfigOptions, ctx context.Context, cancel con
 func parseRawRequest(parseOpts *ConfigOptions, conf *Config) error {
 	conf.RequestFile = parseOpts.Input.Request
 	conf.RequestProto = parseOpts.Input.RequestProto
-	file, err := os.Open(parseOpts.Input.Request)
+	req, err := ReadRawRequest(parseOpts.Input.Request, parseOpts.Input.RequestProto)
+	if err != nil {
+		return err
+	}
+	conf.Method = req.Method
+	for k, v := range req.Headers {
+		conf.Headers[k] = v
+	}
+	conf.Url = req.Url
+	conf.Data = string(req.Data)
+	return nil
+}
+
+// readTargets reads the target URLs from a file, one per line. FUZZ keyword is appended to the path of the
+// URLs that do not contain any of the input keywords.
+func readTargets(filename string, providers []InputProviderConfig) ([]string, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	targets := make([]string, 0)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		target := strings.TrimSpace(scanner.Text())
+		if target == "" || strings.HasPrefix(target, "#") {
+			continue
+		}
+		hasKeyword := strings.Contains(target, "FUZZ")
+		for _, p := range providers {
+			if (p.Keyword != "" && strings.Contains(target, p.Keyword)) || (p.Template != "" && strings.Contains(target, p.Template)) {
+				hasKeyword = true
+			}
+		}
+		if !hasKeyword {
+			target = strings.TrimSuffix(target, "/") + "/FUZZ"
+		}
+		targets = append(targets, target)
+	}
+	return targets, scanner.Err()
+}
+
+// parseStatusRanges parses a comma separated list of status codes and ranges
+func parseStatusRanges(value string) ([]ValueRange, error) {
+	ranges := make([]ValueRange, 0)
+	for _, sv := range strings.Split(value, ",") {
+		vr, err := ValueRangeFromString(strings.TrimSpace(sv))
+		if err != nil {
+			return ranges, fmt.Errorf("%s", sv)
+		}
+		ranges = append(ranges, vr)
+	}
+	return ranges, nil
+}
+
+// ReadRawRequest parses a raw HTTP request from a file. proto is used as the URL scheme if the request line only
+// contains the path.
+func ReadRawRequest(filename string, proto string) (Request, error) {
+	req := Request{Headers: make(map[string]string)}
+	file, err := os.Open(filename)
 	if err != nil {
-		return fmt.Errorf("could not open request file: %s", err)
+		return req, fmt.Errorf("could not open request file: %s", err)
 	}
 	defer file.Close()
 
@@ -633,14 +1017,14 @@ func parseRawRequest(parseOpts *ConfigOptions, conf *Config) error {
 
 	s, err := r.ReadString('\n')
 	if err != nil {
-		return fmt.Errorf("could not read request: %s", err)
+		return req, fmt.Errorf("could not read request: %s", err)
 	}
 	parts := strings.Split(s, " ")
 	if len(parts) < 3 {
-		return fmt.Errorf("malformed request supplied")
+		return req, fmt.Errorf("malformed request supplied")
 	}
 	// Set the request Method
-	conf.Method = parts[0]
+	req.Method = parts[0]
 
 	for {
 		line, err := r.ReadString('\n')
@@ -659,7 +1043,7 @@ func parseRawRequest(parseOpts *ConfigOptions, conf *Config) error {
 			continue
 		}
 
-		conf.Headers[strings.TrimSpace(p[0])] = strings.TrimSpace(p[1])
+		req.Headers[strings.TrimSpace(p[0])] = strings.TrimSpace(p[1])
 	}
 
 	// Handle case with the full http url in path. In that case,
@@ -667,30 +1051,36 @@ func parseRawRequest(parseOpts *ConfigOptions, conf *Config) error {
 	if strings.HasPrefix(parts[1], "http") {
 		parsed, err := url.Parse(parts[1])
 		if err != nil {
-			return fmt.Errorf("could not parse request URL: %s", err)
+			return req, fmt.Errorf("could not parse request URL: %s", err)
 		}
-		conf.Url = parts[1]
-		conf.Headers["Host"] = parsed.Host
+		req.Url = parts[1]
+		req.Headers["Host"] = parsed.Host
 	} else {
 		// Build the request URL from the request
-		conf.Url = parseOpts.Input.RequestProto + "://" + conf.Headers["Host"] + parts[1]
+		req.Url = proto + "://" + req.Headers["Host"] + parts[1]
 	}
 
 	// Set the request body
 	b, err := io.ReadAll(r)
 	if err != nil {
-		return fmt.Errorf("could not read request body: %s", err)
+		return req, fmt.Errorf("could not read request body: %s", err)
 	}
-	conf.Data = string(b)
 
 	// Remove newline (typically added by the editor) at the end of the file
 	//nolint:gosimple // we specifically want to remove just a single newline, not all of them
-	if strings.HasSuffix(conf.Data, "\r\n") {
-		conf.Data = conf.Data[:len(conf.Data)-2]
-	} else if strings.HasSuffix(conf.Data, "\n") {
-		conf.Data = conf.Data[:len(conf.Data)-1]
+	if bytes.HasSuffix(b, []byte("\r\n")) {
+		b = b[:len(b)-2]
+	} else if bytes.HasSuffix(b, []byte("\n")) {
+		b = b[:len(b)-1]
 	}
-	return nil
+	req.Data = b
+	return req, nil
+}
+
+// similarityHasBaseline tells if a -fsim value brings its own baseline, either as a file or as stored hashes
+func similarityHasBaseline(value string) bool {
+	threshold := strings.Split(value, ",")[0]
+	return strings.Contains(value, ",") || strings.Contains(threshold, ":")
 }
 
 func keywordPresent(keyword string, conf *Config) bool {
@@ -701,6 +1091,11 @@ func keywordPresent(keyword string, conf *Config) bool {
 	if strings.Contains(conf.Url, keyword) {
 		return true
 	}
+	for _, target := range conf.Targets {
+		if strings.Contains(target, keyword) {
+			return true
+		}
+	}
 	if strings.Contains(conf.Data, keyword) {
 		return true
 	}
@@ -1,6 +1,8 @@
 package ffuf
 
 import (
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 )
@@ -178,3 +180,130 @@ func TestReplayProxyParsing(t *testing.T) {
 		t.Errorf("Expected proxy string with unsupported protocol to fail")
 	}
 }
+
+func TestRetryParsing(t *testing.T) {
+	configOptions := NewConfigOptions()
+	configOptions.HTTP.RetryOn = "429, 502-504"
+	conf, _ := ConfigFromOptions(configOptions, nil, nil)
+	if len(conf.RetryOnStatus) != 2 || conf.RetryOnStatus[1] != (ValueRange{502, 504}) {
+		t.Errorf("Expected retry status codes to be parsed, got %v", conf.RetryOnStatus)
+	}
+
+	configOptions.HTTP.RetryOn = "5xx"
+	_, err := ConfigFromOptions(configOptions, nil, nil)
+	if !strings.Contains(err.Error(), "Bad status code for retries (-retry-on): 5xx") {
+		t.Errorf("Expected invalid retry status code to fail")
+	}
+
+	configOptions.HTTP.RetryOn = ""
+	configOptions.HTTP.Retries = -1
+	_, err = ConfigFromOptions(configOptions, nil, nil)
+	if !strings.Contains(err.Error(), "Number of retries (-retries) can not be negative") {
+		t.Errorf("Expected negative number of retries to fail")
+	}
+}
+
+func TestSimilarityParsing(t *testing.T) {
+	configOptions := NewConfigOptions()
+	configOptions.HTTP.URL = "https://example.com/FUZZ"
+	configOptions.Filter.Similarity = "95"
+	_, err := ConfigFromOptions(configOptions, nil, nil)
+	if err == nil || !strings.Contains(err.Error(), "Similarity filter (-fsim) needs a baseline file or -ac") {
+		t.Errorf("Expected -fsim without a baseline to fail, got %v", err)
+	}
+
+	for _, value := range []string{"95:/tmp/baseline.html", "95,0123456789abcdef0123456789abcdef"} {
+		configOptions.Filter.Similarity = value
+		_, err = ConfigFromOptions(configOptions, nil, nil)
+		if err != nil && strings.Contains(err.Error(), "-fsim") {
+			t.Errorf("Expected -fsim %s to bring its own baseline, got %v", value, err)
+		}
+	}
+
+	configOptions.Filter.Similarity = "95"
+	configOptions.General.AutoCalibration = true
+	_, err = ConfigFromOptions(configOptions, nil, nil)
+	if err != nil && strings.Contains(err.Error(), "-fsim") {
+		t.Errorf("Expected -fsim with -ac to be accepted, got %v", err)
+	}
+}
+
+func TestTargetListParsing(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "targets.txt")
+	_ = os.WriteFile(filename, []byte("https://one.example.com/\n\n# staging\nhttp://two.example.com/app\nhttps://three.example.com/FUZZ.php\nhttps://HOST.example.com/\n"), 0644)
+	configOptions := NewConfigOptions()
+	configOptions.HTTP.URLList = filename
+	configOptions.Input.Wordlists = []string{"/dev/null", "/dev/null:HOST"}
+	configOptions.General.AutoCalibration = true
+	conf, err := ConfigFromOptions(configOptions, nil, nil)
+	if err != nil {
+		t.Fatalf("Failed to parse the target list: %v", err)
+	}
+	expected := []string{"https://one.example.com/FUZZ", "http://two.example.com/app/FUZZ", "https://three.example.com/FUZZ.php", "https://HOST.example.com/"}
+	if strings.Join(conf.Targets, " ") != strings.Join(expected, " ") || conf.Url != expected[0] {
+		t.Errorf("Expected targets %v, got %v", expected, conf.Targets)
+	}
+	if len(conf.InputProviders) != 2 {
+		t.Errorf("Expected the keywords of all targets to be kept, got %v", conf.InputProviders)
+	}
+	if !conf.AutoCalibrationPerHost {
+		t.Errorf("Expected autocalibration to be done for each target")
+	}
+
+	configOptions.HTTP.URL = "https://example.com/FUZZ"
+	_, err = ConfigFromOptions(configOptions, nil, nil)
+	if err == nil || !strings.Contains(err.Error(), "Target list (-u-list) can not be used with -u or -request") {
+		t.Errorf("Expected -u-list and -u together to fail, got %v", err)
+	}
+}
+
+func TestGeneratorParsing(t *testing.T) {
+	configOptions := NewConfigOptions()
+	configOptions.HTTP.URL = "https://example.com/FUZZ?id=ID&token=TOKEN"
+	configOptions.Input.Ranges = []string{"1-100", "0001-9999:ID"}
+	configOptions.Input.Masks = []string{"a:?d?1:TOKEN"}
+	configOptions.Input.MaskCharsets = []string{"?l-"}
+	conf, err := ConfigFromOptions(configOptions, nil, nil)
+	if err != nil {
+		t.Fatalf("Failed to parse the ranges and masks: %v", err)
+	}
+	expected := []InputProviderConfig{
+		{Name: "range", Keyword: "FUZZ", Value: "1-100"},
+		{Name: "range", Keyword: "ID", Value: "0001-9999"},
+		{Name: "mask", Keyword: "TOKEN", Value: "a:?d?1"},
+	}
+	if len(conf.InputProviders) != len(expected) {
+		t.Fatalf("Expected %d input providers, got %v", len(expected), conf.InputProviders)
+	}
+	for i, p := range expected {
+		if conf.InputProviders[i] != p {
+			t.Errorf("Expected input provider %v, got %v", p, conf.InputProviders[i])
+		}
+	}
+
+	conf.MatcherManager = newTestMatcherManager()
+	opts := conf.ToOptions()
+	if strings.Join(opts.Input.Ranges, " ") != "1-100:FUZZ 0001-9999:ID" || strings.Join(opts.Input.Masks, " ") != "a:?d?1:TOKEN" || opts.Input.MaskCharsets[0] != "?l-" {
+		t.Errorf("Expected the ranges and masks to be kept in the options, got %v, %v and %v", opts.Input.Ranges, opts.Input.Masks, opts.Input.MaskCharsets)
+	}
+}
+
+func TestRulesParsing(t *testing.T) {
+	configOptions := NewConfigOptions()
+	configOptions.HTTP.URL = "https://example.com/FUZZ?user=USER"
+	configOptions.Input.Wordlists = []string{"/dev/null", "/dev/null:USER"}
+	configOptions.Input.Rules = []string{"USER:/tmp/rules.txt"}
+	conf, err := ConfigFromOptions(configOptions, nil, nil)
+	if err != nil {
+		t.Fatalf("Failed to parse the rules: %v", err)
+	}
+	if conf.InputProviders[0].Rules != "" || conf.InputProviders[1].Rules != "/tmp/rules.txt" {
+		t.Errorf("Expected the rules to be attached to the USER keyword, got %v", conf.InputProviders)
+	}
+
+	configOptions.Input.Rules = []string{"OTHER:/tmp/rules.txt", "FUZZ"}
+	_, err = ConfigFromOptions(configOptions, nil, nil)
+	if err == nil || !strings.Contains(err.Error(), "defined for keyword OTHER") || !strings.Contains(err.Error(), "expected KEYWORD:FILE") {
+		t.Errorf("Expected invalid rules to fail, got %v", err)
+	}
+}
@@ -5,11 +5,14 @@ import (
 )
 
 type Progress struct {
-	StartedAt  time.Time
-	ReqCount   int
-	ReqTotal   int
-	ReqSec     int64
-	QueuePos   int
-	QueueTotal int
-	ErrorCount int
+	StartedAt   time.Time
+	ReqCount    int
+	ReqTotal    int
+	ReqSec      int64
+	QueuePos    int
+	QueueTotal  int
+	ErrorCount  int
+	RetryCount  int
+	FailedCount int
+	HostRates   []HostRate
 }
@@ -2,16 +2,41 @@ package ffuf
 
 import (
 	"container/ring"
+	"math"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 )
 
+const (
+	// ADAPTIVE_RATE_MIN is the lowest rate in requests per second the adaptive rate limiting backs off to
+	ADAPTIVE_RATE_MIN = 1.0
+	// ADAPTIVE_RATE_INTERVAL is the minimum time between two adjustments of the rate limit of a host
+	ADAPTIVE_RATE_INTERVAL = time.Second
+	// ADAPTIVE_RATE_SAMPLES is the number of responses used for calculating the request rate and response time baseline of a host
+	ADAPTIVE_RATE_SAMPLES = 20
+	// ADAPTIVE_RATE_LATENCY_WEIGHT is the weight of a new response time in the moving average
+	ADAPTIVE_RATE_LATENCY_WEIGHT = 0.1
+	// ADAPTIVE_RATE_LATENCY_SPIKE is the factor of the baseline response time considered a latency spike
+	ADAPTIVE_RATE_LATENCY_SPIKE = 3.0
+	// ADAPTIVE_RATE_LATENCY_MIN is the minimum increase in milliseconds over the baseline considered a latency spike
+	ADAPTIVE_RATE_LATENCY_MIN = 100.0
+	// ADAPTIVE_RATE_MAX_RETRY_AFTER is the longest time a Retry-After header is honored for
+	ADAPTIVE_RATE_MAX_RETRY_AFTER = 5 * time.Minute
+)
+
 type RateThrottle struct {
 	rateCounter    *ring.Ring
 	Config         *Config
 	RateMutex      sync.Mutex
 	RateLimiter    *time.Ticker
 	lastAdjustment time.Time
+	hosts          map[string]*hostRate
 }
 
 func NewRateThrottle(conf *Config) *RateThrottle {
@@ -20,7 +45,7 @@ func NewRateThrottle(conf *Config) *RateThrottle {
 		lastAdjustment: time.Now(),
 	}
 
-	if conf.Rate > 0 {
+	if conf.Rate > 0 && !conf.RateAdaptive {
 		r.rateCounter = ring.New(int(conf.Rate * 5))
 		ratemicros := 1000000 / conf.Rate
 		r.RateLimiter = time.NewTicker(time.Microsecond * time.Duration(ratemicros))
@@ -34,6 +59,8 @@ func NewRateThrottle(conf *Config) *RateThrottle {
 
 // CurrentRate calculates requests/second value from circular list of rate
 func (r *RateThrottle) CurrentRate() int64 {
+	r.RateMutex.Lock()
+	defer r.RateMutex.Unlock()
 	n := r.rateCounter.Len()
 	lowest := int64(0)
 	highest := int64(0)
@@ -70,7 +97,9 @@ func (r *RateThrottle) ChangeRate(rate int) {
 	}
 
 	r.RateLimiter.Stop()
-	if rate > 0 {
+	r.RateMutex.Lock()
+	defer r.RateMutex.Unlock()
+	if rate > 0 && !r.Config.RateAdaptive {
 		r.RateLimiter = time.NewTicker(time.Microsecond * time.Duration(ratemicros))
 		// reset the rate counter
 		r.rateCounter = ring.New(rate * 5)
@@ -90,3 +119,229 @@ func (r *RateThrottle) Tick(start, end time.Time) {
 	r.rateCounter = r.rateCounter.Next()
 	r.rateCounter.Value = end.UnixMicro()
 }
+
+// hostRate holds the adaptive rate limiting state of a single host
+type hostRate struct {
+	// current rate limit in requests per second, 0 when the host is not limited
+	limit float64
+	// rate limit before the latest decrease, used to size the additive increase
+	ceiling float64
+	// earliest time for the next request
+	next time.Time
+	// time of the latest change in the rate limit
+	lastChange time.Time
+	// timestamps of the latest responses, used to calculate the current rate
+	ticks *ring.Ring
+	// exponentially weighted moving average of the response time, and the lowest average seen
+	latency  float64
+	baseline float64
+	samples  int
+}
+
+// HostRate describes the current request rate and adaptive rate limit of a host
+type HostRate struct {
+	Host   string
+	ReqSec int64
+	Limit  int64
+}
+
+func newHostRate() *hostRate {
+	return &hostRate{ticks: ring.New(ADAPTIVE_RATE_SAMPLES), lastChange: time.Now()}
+}
+
+// currentRate calculates requests/second value from the timestamps of the latest responses
+func (h *hostRate) currentRate() float64 {
+	n := 0
+	var earliest, latest time.Time
+	h.ticks.Do(func(v interface{}) {
+		if t, ok := v.(time.Time); ok {
+			n++
+			if earliest.IsZero() || t.Before(earliest) {
+				earliest = t
+			}
+			if t.After(latest) {
+				latest = t
+			}
+		}
+	})
+	elapsed := latest.Sub(earliest)
+	if n < 2 || elapsed <= 0 {
+		return 0
+	}
+	return float64(n-1) / elapsed.Seconds()
+}
+
+// decrease halves the rate limit of the host
+func (h *hostRate) decrease(now time.Time) {
+	current := h.limit
+	if observed := h.currentRate(); current == 0 || (observed > 0 && observed < current) {
+		current = observed
+	}
+	if current <= 0 {
+		current = ADAPTIVE_RATE_MIN * 2
+	}
+	h.ceiling = current
+	h.limit = math.Max(ADAPTIVE_RATE_MIN, current/2)
+	h.lastChange = now
+}
+
+// increase raises the rate limit of the host by a tenth of the rate before the latest decrease
+func (h *hostRate) increase(now time.Time, max float64) {
+	h.limit += math.Max(1, h.ceiling/10)
+	if max > 0 && h.limit >= max {
+		h.limit = max
+	} else if max == 0 && h.limit > h.ceiling*2 {
+		// The host handles twice the rate that caused throttling, remove the limit altogether
+		h.limit = 0
+	}
+	h.lastChange = now
+}
+
+// host returns the adaptive rate limiting state of a host, creating it if needed. RateMutex must be held.
+func (r *RateThrottle) host(host string) *hostRate {
+	if r.hosts == nil {
+		r.hosts = make(map[string]*hostRate)
+	}
+	h, ok := r.hosts[host]
+	if !ok {
+		h = newHostRate()
+		if r.Config.Rate > 0 {
+			h.limit = float64(r.Config.Rate)
+		}
+		r.hosts[host] = h
+	}
+	return h
+}
+
+// WaitForHost blocks until a request to the host is allowed by the adaptive rate limit, or the job is cancelled
+func (r *RateThrottle) WaitForHost(host string) {
+	if !r.Config.RateAdaptive {
+		return
+	}
+	r.RateMutex.Lock()
+	h := r.host(host)
+	now := time.Now()
+	slot := now
+	if h.next.After(slot) {
+		slot = h.next
+	}
+	if h.limit > 0 {
+		h.next = slot.Add(time.Duration(float64(time.Second) / h.limit))
+	}
+	r.RateMutex.Unlock()
+	if wait := slot.Sub(now); wait > 0 {
+		// makes the wait cancellable by context, a Retry-After can block for minutes
+		var done <-chan struct{}
+		if r.Config.Context != nil {
+			done = r.Config.Context.Done()
+		}
+		select {
+		case <-done:
+		case <-time.After(wait):
+		}
+	}
+}
+
+// HostFeedback adjusts the adaptive rate limit of the host based on a response. The limit is halved on 429 and
+// 503 responses and when the response times spike, and increased additively when the host responds normally.
+// A Retry-After header blocks the requests to the host for the requested time.
+func (r *RateThrottle) HostFeedback(host string, resp *Response, err error) {
+	if !r.Config.RateAdaptive {
+		return
+	}
+	r.RateMutex.Lock()
+	defer r.RateMutex.Unlock()
+	h := r.host(host)
+	now := time.Now()
+	h.ticks = h.ticks.Next()
+	h.ticks.Value = now
+	max := float64(r.Config.Rate)
+	if max > 0 && (h.limit == 0 || h.limit > max) {
+		// the rate was changed from the interactive console
+		h.limit = max
+	}
+
+	throttled := false
+	if err != nil {
+		throttled = os.IsTimeout(err)
+	} else if resp.StatusCode == 429 || resp.StatusCode == 503 {
+		throttled = true
+		if wait := retryAfter(resp, now); wait > 0 {
+			if until := now.Add(wait); until.After(h.next) {
+				h.next = until
+			}
+		}
+	} else if resp.Time > 0 {
+		ms := float64(resp.Time.Milliseconds())
+		if h.samples == 0 {
+			h.latency = ms
+		} else {
+			h.latency = h.latency*(1-ADAPTIVE_RATE_LATENCY_WEIGHT) + ms*ADAPTIVE_RATE_LATENCY_WEIGHT
+		}
+		h.samples++
+		if h.samples >= ADAPTIVE_RATE_SAMPLES && (h.baseline == 0 || h.latency < h.baseline) {
+			h.baseline = h.latency
+		}
+		throttled = h.baseline > 0 && h.latency > h.baseline*ADAPTIVE_RATE_LATENCY_SPIKE && h.latency-h.baseline > ADAPTIVE_RATE_LATENCY_MIN
+	}
+
+	// Only adjust once per interval, as the responses to the requests already in flight reflect the old rate
+	if now.Sub(h.lastChange) < ADAPTIVE_RATE_INTERVAL {
+		return
+	}
+	if throttled {
+		h.decrease(now)
+		if h.latency > 0 {
+			// give the host a chance to recover before judging the response times again
+			h.latency = h.baseline
+		}
+	} else if h.limit > 0 {
+		h.increase(now, max)
+	}
+}
+
+// HostRates returns the current request rates and adaptive rate limits of the hosts
+func (r *RateThrottle) HostRates() []HostRate {
+	r.RateMutex.Lock()
+	defer r.RateMutex.Unlock()
+	rates := make([]HostRate, 0, len(r.hosts))
+	for host, h := range r.hosts {
+		rates = append(rates, HostRate{Host: host, ReqSec: int64(math.Round(h.currentRate())), Limit: int64(math.Round(h.limit))})
+	}
+	sort.Slice(rates, func(i, j int) bool { return rates[i].Host < rates[j].Host })
+	return rates
+}
+
+// retryAfter returns the wait time requested by the Retry-After header of the response
+func retryAfter(resp *Response, now time.Time) time.Duration {
+	values, ok := resp.Headers["Retry-After"]
+	if !ok || len(values) == 0 {
+		return 0
+	}
+	value := strings.TrimSpace(values[0])
+	var wait time.Duration
+	if secs, err := strconv.Atoi(value); err == nil {
+		wait = time.Duration(secs) * time.Second
+	} else if t, err := http.ParseTime(value); err == nil {
+		wait = t.Sub(now)
+	}
+	if wait > ADAPTIVE_RATE_MAX_RETRY_AFTER {
+		wait = ADAPTIVE_RATE_MAX_RETRY_AFTER
+	}
+	return wait
+}
+
+// RateLimitHost returns the host the adaptive rate limit of the request is tracked for
+func RateLimitHost(req *Request) string {
+	if host, ok := req.Headers["Host"]; ok && host != "" {
+		return host
+	}
+	if req.Host != "" {
+		return req.Host
+	}
+	u, err := url.Parse(req.Url)
+	if err != nil {
+		return ""
+	}
+	return u.Host
+}
@@ -0,0 +1,118 @@
+package ffuf
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Now()
+	for i, test := range []struct {
+		value string
+		wait  time.Duration
+	}{
+		{"", 0},
+		{"120", 120 * time.Second},
+		{"invalid", 0},
+		{now.Add(30 * time.Second).UTC().Format(http.TimeFormat), 30 * time.Second},
+		{"86400", ADAPTIVE_RATE_MAX_RETRY_AFTER},
+	} {
+		resp := Response{Headers: map[string][]string{}}
+		if test.value != "" {
+			resp.Headers["Retry-After"] = []string{test.value}
+		}
+		// the HTTP date has a resolution of one second
+		if wait := retryAfter(&resp, now); wait > test.wait || wait < test.wait-time.Second {
+			t.Errorf("Retry-After test %d: was expecting wait time of %s but got %s", i, test.wait, wait)
+		}
+	}
+}
+
+func TestAdaptiveRate(t *testing.T) {
+	conf := &Config{Rate: 100, RateAdaptive: true, Threads: 10}
+	r := NewRateThrottle(conf)
+	ok := Response{StatusCode: 200, Time: 10 * time.Millisecond}
+	throttled := Response{StatusCode: 429, Headers: map[string][]string{}}
+
+	r.HostFeedback("a.example.com", &ok, nil)
+	r.HostFeedback("b.example.com", &ok, nil)
+	r.hosts["a.example.com"].lastChange = time.Now().Add(-ADAPTIVE_RATE_INTERVAL)
+	r.HostFeedback("a.example.com", &throttled, nil)
+	if limit := r.hosts["a.example.com"].limit; limit != 50 {
+		t.Errorf("Expected the rate limit to be halved on 429 response, got %f", limit)
+	}
+	// The responses to requests sent with the old rate should not decrease the limit further
+	r.HostFeedback("a.example.com", &throttled, nil)
+	if limit := r.hosts["a.example.com"].limit; limit != 50 {
+		t.Errorf("Expected the rate limit to be adjusted only once per interval, got %f", limit)
+	}
+	if limit := r.hosts["b.example.com"].limit; limit != 100 {
+		t.Errorf("Expected the rate limit of other hosts not to change, got %f", limit)
+	}
+
+	r.hosts["a.example.com"].lastChange = time.Now().Add(-ADAPTIVE_RATE_INTERVAL)
+	r.HostFeedback("a.example.com", &ok, nil)
+	if limit := r.hosts["a.example.com"].limit; limit != 60 {
+		t.Errorf("Expected the rate limit to increase additively, got %f", limit)
+	}
+	for i := 0; i < 10; i++ {
+		r.hosts["a.example.com"].lastChange = time.Now().Add(-ADAPTIVE_RATE_INTERVAL)
+		r.HostFeedback("a.example.com", &ok, nil)
+	}
+	if limit := r.hosts["a.example.com"].limit; limit != 100 {
+		t.Errorf("Expected the rate limit not to exceed -rate, got %f", limit)
+	}
+
+	throttled.Headers["Retry-After"] = []string{"1"}
+	r.hosts["b.example.com"].lastChange = time.Now().Add(-ADAPTIVE_RATE_INTERVAL)
+	r.HostFeedback("b.example.com", &throttled, nil)
+	start := time.Now()
+	r.WaitForHost("b.example.com")
+	if waited := time.Since(start); waited < 900*time.Millisecond {
+		t.Errorf("Expected Retry-After to block requests to the host, waited %s", waited)
+	}
+	start = time.Now()
+	r.WaitForHost("a.example.com")
+	if waited := time.Since(start); waited > 500*time.Millisecond {
+		t.Errorf("Expected Retry-After not to block requests to other hosts, waited %s", waited)
+	}
+
+	rates := r.HostRates()
+	if len(rates) != 2 || rates[0].Host != "a.example.com" || rates[0].Limit != 100 || rates[1].Limit != 50 {
+		t.Errorf("Unexpected host rates %v", rates)
+	}
+}
+
+func TestAdaptiveRateLatency(t *testing.T) {
+	conf := &Config{RateAdaptive: true, Threads: 10}
+	r := NewRateThrottle(conf)
+	for i := 0; i < ADAPTIVE_RATE_SAMPLES; i++ {
+		r.HostFeedback("example.com", &Response{StatusCode: 200, Time: 50 * time.Millisecond}, nil)
+	}
+	if limit := r.hosts["example.com"].limit; limit != 0 {
+		t.Errorf("Expected the host not to be rate limited without -rate, got %f", limit)
+	}
+	r.hosts["example.com"].lastChange = time.Now().Add(-ADAPTIVE_RATE_INTERVAL)
+	for i := 0; i < 20; i++ {
+		r.HostFeedback("example.com", &Response{StatusCode: 200, Time: 2 * time.Second}, nil)
+	}
+	if limit := r.hosts["example.com"].limit; limit == 0 {
+		t.Errorf("Expected a response time spike to rate limit the host")
+	}
+}
+
+func TestAdaptiveRateCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	conf := &Config{RateAdaptive: true, Threads: 10, Context: ctx}
+	r := NewRateThrottle(conf)
+	throttled := Response{StatusCode: 429, Headers: map[string][]string{"Retry-After": {"60"}}}
+	r.HostFeedback("example.com", &throttled, nil)
+	time.AfterFunc(100*time.Millisecond, cancel)
+	start := time.Now()
+	r.WaitForHost("example.com")
+	if waited := time.Since(start); waited > 5*time.Second {
+		t.Errorf("Expected cancelling the job to stop waiting for Retry-After, waited %s", waited)
+	}
+}
@@ -21,6 +21,7 @@ type Response struct {
 	ResultFile    string
 	ScraperData   map[string][]string
 	Time          time.Duration
+	Retries       int
 }
 
 // GetRedirectLocation returns the redirect location for a 3xx redirect HTTP response
@@ -90,3 +91,27 @@ func NewResponse(httpresp *http.Response, req *Request) Response {
 	resp.ScraperData = make(map[string][]string)
 	return resp
 }
+
+// NewResult returns the Result reported to the output for a matched response
+func NewResult(resp *Response) Result {
+	inputs := make(map[string][]byte, len(resp.Request.Input))
+	for k, v := range resp.Request.Input {
+		inputs[k] = v
+	}
+	return Result{
+		Input:            inputs,
+		Position:         resp.Request.Position,
+		StatusCode:       resp.StatusCode,
+		ContentLength:    resp.ContentLength,
+		ContentWords:     resp.ContentWords,
+		ContentLines:     resp.ContentLines,
+		ContentType:      resp.ContentType,
+		RedirectLocation: resp.GetRedirectLocation(false),
+		ScraperData:      resp.ScraperData,
+		Url:              resp.Request.Url,
+		Duration:         resp.Time,
+		ResultFile:       resp.ResultFile,
+		Host:             resp.Request.Host,
+		Retries:          resp.Retries,
+	}
+}
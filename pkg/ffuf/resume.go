@@ -0,0 +1,150 @@
+package ffuf
+
+import (
+	"encoding/json"
+	"os"
+	"time"
+)
+
+// RESUME_INTERVAL is the time between two periodic resume state checkpoints
+const RESUME_INTERVAL = 10 * time.Second
+
+// ResumeState holds everything needed to continue an interrupted job
+type ResumeState struct {
+	Options        ConfigOptions    `json:"options"`
+	Time           time.Time        `json:"time"`
+	Position       int              `json:"position"`
+	QueuePos       int              `json:"queuepos"`
+	QueueJobs      []ResumeQueueJob `json:"queuejobs"`
+	Results        []Result         `json:"results"`
+	CurrentResults []Result         `json:"current_results"`
+	Errors         int              `json:"errors"`
+	Retries        int              `json:"retries"`
+	Failed         int              `json:"failed"`
+	CalibrationState
+}
+
+// ResumeQueueJob is the serializable form of a QueueJob
+type ResumeQueueJob struct {
+	Url     string  `json:"url"`
+	Depth   int     `json:"depth"`
+	Request Request `json:"request"`
+}
+
+// ReadResumeState reads a previously written resume state from a file
+func ReadResumeState(filename string) (*ResumeState, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	state := ResumeState{}
+	err = json.Unmarshal(data, &state)
+	return &state, err
+}
+
+// WriteResumeState writes the resume state to a file. The data is written to a temporary file first and
+// renamed over the old one, so a crash mid-write never leaves a truncated state file behind.
+func WriteResumeState(filename string, state *ResumeState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	tmpfile := filename + ".tmp"
+	err = os.WriteFile(tmpfile, data, 0640)
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmpfile, filename)
+}
+
+// markInflight records a dispatched input position that has not finished yet
+func (j *Job) markInflight(pos int) {
+	j.resumeMutex.Lock()
+	defer j.resumeMutex.Unlock()
+	j.inflight[pos] = true
+	j.lastDispatched = pos
+}
+
+// unmarkInflight removes a finished input position from the inflight set
+func (j *Job) unmarkInflight(pos int) {
+	j.resumeMutex.Lock()
+	defer j.resumeMutex.Unlock()
+	delete(j.inflight, pos)
+}
+
+// resumePosition returns the lowest input position that has not been fully processed yet
+func (j *Job) resumePosition() int {
+	j.resumeMutex.Lock()
+	defer j.resumeMutex.Unlock()
+	pos := j.lastDispatched + 1
+	for p := range j.inflight {
+		if p < pos {
+			pos = p
+		}
+	}
+	return pos
+}
+
+// resumeState collects the current state of the job
+func (j *Job) resumeState() *ResumeState {
+	// The position is taken before the results: the results of all of the positions before it have been reported
+	// by then, and the ones of the later positions are dropped when the state is restored.
+	position := j.resumePosition()
+	state := ResumeState{
+		Options:          j.resumeOptions,
+		Time:             time.Now(),
+		Position:         position,
+		QueueJobs:        make([]ResumeQueueJob, 0),
+		Results:          j.Output.GetResults(),
+		CurrentResults:   j.Output.GetCurrentResults(),
+		CalibrationState: j.calibrationState(),
+	}
+	j.ErrorMutex.Lock()
+	state.Errors, state.Retries, state.Failed = j.ErrorCounter, j.RetryCounter, j.FailedCounter
+	j.ErrorMutex.Unlock()
+	j.queueMutex.Lock()
+	state.QueuePos = j.queuepos
+	for _, qj := range j.queuejobs {
+		state.QueueJobs = append(state.QueueJobs, ResumeQueueJob{Url: qj.Url, Depth: qj.depth, Request: qj.req})
+	}
+	j.queueMutex.Unlock()
+	return &state
+}
+
+// saveResumeState writes a checkpoint of the job state to the resume file
+func (j *Job) saveResumeState() {
+	j.lastCheckpoint = time.Now()
+	err := WriteResumeState(j.Config.ResumeFile, j.resumeState())
+	if err != nil {
+		j.Output.Error("Could not write resume state: " + err.Error())
+	}
+}
+
+// restoreResumeState restores the job queue, results and calibrated filters from a resume state
+func (j *Job) restoreResumeState(state *ResumeState) {
+	if len(state.QueueJobs) > 0 {
+		j.queuejobs = make([]QueueJob, 0)
+		for _, qj := range state.QueueJobs {
+			j.queuejobs = append(j.queuejobs, QueueJob{Url: qj.Url, depth: qj.Depth, req: qj.Request})
+		}
+	}
+	// prepareQueueJob will move the queue position forward to the job that was running
+	if state.QueuePos > 0 {
+		j.queuepos = state.QueuePos - 1
+	}
+	j.resumeFrom = state.Position
+	j.ErrorCounter = state.Errors
+	j.RetryCounter = state.Retries
+	j.FailedCounter = state.Failed
+	// The results of the finished queue jobs were already cycled out of the current ones. The positions from the
+	// resume position on are run again, so their results would be reported twice.
+	j.Output.SetResults(state.Results)
+	current := make([]Result, 0, len(state.CurrentResults))
+	for _, r := range state.CurrentResults {
+		if r.Position < state.Position {
+			current = append(current, r)
+		}
+	}
+	j.Output.SetCurrentResults(current)
+	j.restoreCalibration(state.CalibrationState)
+}
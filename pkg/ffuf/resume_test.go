@@ -0,0 +1,103 @@
+package ffuf
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestResumeStateRoundtrip(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "ffuf-test")
+	if err != nil {
+		t.Fatalf("Failed to create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+	filename := filepath.Join(tmpDir, "resume.json")
+
+	state := ResumeState{
+		Options:  *NewConfigOptions(),
+		Position: 1234,
+		QueuePos: 2,
+		QueueJobs: []ResumeQueueJob{
+			{Url: "http://example.com/FUZZ", Depth: 0, Request: Request{Method: "GET", Url: "http://example.com/FUZZ"}},
+			{Url: "http://example.com/admin/FUZZ", Depth: 1, Request: Request{Method: "GET", Url: "http://example.com/admin/FUZZ"}},
+		},
+		Results:        []Result{{Input: map[string][]byte{"FUZZ": []byte("admin")}, Position: 10, StatusCode: 301}},
+		CurrentResults: []Result{{Input: map[string][]byte{"FUZZ": []byte("login")}, Position: 3, StatusCode: 200}},
+		CalibrationState: CalibrationState{
+			Calibrated:  true,
+			Filters:     map[string]string{"size": "42"},
+			HostFilters: map[string]map[string]string{"example.com": {"size": "42,1337"}},
+		},
+	}
+	state.Options.HTTP.URL = "http://example.com/FUZZ"
+
+	err = WriteResumeState(filename, &state)
+	if err != nil {
+		t.Fatalf("Failed to write resume state: %v", err)
+	}
+	if FileExists(filename + ".tmp") {
+		t.Errorf("Temporary resume file was left behind")
+	}
+
+	restored, err := ReadResumeState(filename)
+	if err != nil {
+		t.Fatalf("Failed to read resume state: %v", err)
+	}
+	if restored.Position != 1234 || restored.QueuePos != 2 {
+		t.Errorf("Expected position 1234 and queue position 2, got %d and %d", restored.Position, restored.QueuePos)
+	}
+	if len(restored.QueueJobs) != 2 || restored.QueueJobs[1].Depth != 1 || restored.QueueJobs[1].Request.Url != "http://example.com/admin/FUZZ" {
+		t.Errorf("Queue jobs were not restored correctly: %v", restored.QueueJobs)
+	}
+	if len(restored.Results) != 1 || string(restored.Results[0].Input["FUZZ"]) != "admin" {
+		t.Errorf("Results were not restored correctly: %v", restored.Results)
+	}
+	if len(restored.CurrentResults) != 1 || string(restored.CurrentResults[0].Input["FUZZ"]) != "login" {
+		t.Errorf("Current results were not restored correctly: %v", restored.CurrentResults)
+	}
+	if restored.Options.HTTP.URL != "http://example.com/FUZZ" {
+		t.Errorf("Options were not restored correctly")
+	}
+	if !restored.Calibrated || restored.Filters["size"] != "42" || restored.HostFilters["example.com"]["size"] != "42,1337" {
+		t.Errorf("Calibrated filters were not restored correctly")
+	}
+}
+
+func TestResumePosition(t *testing.T) {
+	job := NewJob(&Config{})
+	if pos := job.resumePosition(); pos != 1 {
+		t.Errorf("Expected a fresh job to resume from position 1, got %d", pos)
+	}
+	job.markInflight(1)
+	job.markInflight(2)
+	job.markInflight(3)
+	job.unmarkInflight(1)
+	job.unmarkInflight(3)
+	if pos := job.resumePosition(); pos != 2 {
+		t.Errorf("Expected to resume from the lowest unfinished position 2, got %d", pos)
+	}
+	job.unmarkInflight(2)
+	if pos := job.resumePosition(); pos != 4 {
+		t.Errorf("Expected to resume after the last dispatched position, got %d", pos)
+	}
+}
+
+func TestRestoreResumeStateResults(t *testing.T) {
+	out := NewNullOutput()
+	job := NewJob(&Config{MatcherManager: newTestMatcherManager()})
+	job.Output = out
+	state := ResumeState{
+		Position: 5,
+		CurrentResults: []Result{
+			{Input: map[string][]byte{"FUZZ": []byte("a")}, Position: 2},
+			{Input: map[string][]byte{"FUZZ": []byte("b")}, Position: 5},
+			{Input: map[string][]byte{"FUZZ": []byte("c")}, Position: 7},
+		},
+	}
+	job.restoreResumeState(&state)
+	// The positions from 5 on are run again, and their results found again
+	if len(out.Results) != 1 || out.Results[0].Position != 2 {
+		t.Errorf("Expected only the result before the resume position to be restored, got %v", out.Results)
+	}
+}
@@ -0,0 +1,72 @@
+package ffuf
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+)
+
+// RETRY_BACKOFF_MAX is the longest time to wait before retrying a request
+const RETRY_BACKOFF_MAX = time.Minute
+
+// executeWithRetries sends the request, and retries it on errors and on the status codes defined with -retry-on
+// using exponential backoff. Requests that still fail after all the retries are counted as failed.
+func (j *Job) executeWithRetries(req *Request) (Response, error) {
+	ratehost := RateLimitHost(req)
+	for attempt := 0; ; attempt++ {
+		j.Rate.WaitForHost(ratehost)
+		resp, err := j.Runner.Execute(req)
+		j.Rate.HostFeedback(ratehost, &resp, err)
+		resp.Retries = attempt
+		if !j.shouldRetry(resp, err) {
+			return resp, err
+		}
+		if attempt >= j.Config.Retries {
+			j.incFailed()
+			if err == nil {
+				// the request errors are recorded by the caller
+				j.recordError(req.Input, req.Position, req.Url, fmt.Errorf("Status code %d after %d retries", resp.StatusCode, attempt))
+			}
+			return resp, err
+		}
+		j.incRetry()
+		// makes the backoff cancellable by context
+		select {
+		case <-j.Config.Context.Done():
+			return resp, err
+		case <-time.After(j.retryDelay(attempt, resp)):
+		}
+	}
+}
+
+// shouldRetry checks if the request should be sent again based on the response or error
+func (j *Job) shouldRetry(resp Response, err error) bool {
+	if err != nil {
+		// do not retry requests that were cancelled because the job is stopping
+		return j.Config.Context.Err() == nil
+	}
+	for _, vr := range j.Config.RetryOnStatus {
+		if resp.StatusCode >= vr.Min && resp.StatusCode <= vr.Max {
+			return true
+		}
+	}
+	return false
+}
+
+// retryDelay returns the time to wait before the next retry. The delay doubles on each attempt, and is randomized
+// between half and full delay to avoid retrying requests of all the threads at once. A longer wait requested in
+// Retry-After header of the response is honored.
+func (j *Job) retryDelay(attempt int, resp Response) time.Duration {
+	delay := time.Duration(j.Config.RetryBackoff * float64(time.Second) * math.Pow(2, float64(attempt)))
+	if delay > RETRY_BACKOFF_MAX || delay < 0 {
+		delay = RETRY_BACKOFF_MAX
+	}
+	if delay > 0 {
+		delay = delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+	}
+	if wait := retryAfter(&resp, time.Now()); wait > delay {
+		delay = wait
+	}
+	return delay
+}
@@ -0,0 +1,90 @@
+package ffuf
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// flakyRunner fails the first requests with an error or a status code
+type flakyRunner struct {
+	failures int
+	status   int64
+	requests int
+}
+
+func (r *flakyRunner) Prepare(input map[string][]byte, basereq *Request) (Request, error) {
+	return CopyRequest(basereq), nil
+}
+
+func (r *flakyRunner) Execute(req *Request) (Response, error) {
+	r.requests++
+	if r.requests <= r.failures {
+		if r.status == 0 {
+			return Response{}, fmt.Errorf("connection reset by peer")
+		}
+		return Response{StatusCode: r.status, Request: req}, nil
+	}
+	return Response{StatusCode: 200, Request: req}, nil
+}
+
+func (r *flakyRunner) Dump(req *Request) ([]byte, error) { return []byte{}, nil }
+
+func TestExecuteWithRetries(t *testing.T) {
+	for i, test := range []struct {
+		runner   flakyRunner
+		retries  int
+		status   int64
+		err      bool
+		requests int
+		retried  int
+		failed   int
+	}{
+		{flakyRunner{failures: 1}, 1, 200, false, 2, 1, 0},
+		{flakyRunner{failures: 2}, 1, 0, true, 2, 1, 1},
+		{flakyRunner{failures: 2}, 3, 200, false, 3, 2, 0},
+		{flakyRunner{failures: 3, status: 503}, 5, 200, false, 4, 3, 0},
+		{flakyRunner{failures: 3, status: 503}, 2, 503, false, 3, 2, 1},
+		{flakyRunner{failures: 3, status: 404}, 5, 404, false, 1, 0, 0},
+		{flakyRunner{failures: 1}, 0, 0, true, 1, 0, 1},
+	} {
+		conf := NewConfig(context.Background(), func() {})
+		conf.Retries = test.retries
+		conf.RetryOnStatus = []ValueRange{{429, 429}, {502, 504}}
+		job := NewJob(&conf)
+		job.Runner = &test.runner
+		req := Request{Url: "http://example.com/"}
+		resp, err := job.executeWithRetries(&req)
+		if (err != nil) != test.err || resp.StatusCode != test.status {
+			t.Errorf("Retry test %d: expected status %d and error %t, got %d and %v", i, test.status, test.err, resp.StatusCode, err)
+		}
+		if test.runner.requests != test.requests || job.RetryCounter != test.retried || job.FailedCounter != test.failed {
+			t.Errorf("Retry test %d: expected %d requests, %d retried and %d failed, got %d, %d and %d", i, test.requests, test.retried, test.failed,
+				test.runner.requests, job.RetryCounter, job.FailedCounter)
+		}
+		if !test.err && resp.Retries != test.retried {
+			t.Errorf("Retry test %d: expected the response to have %d retries, got %d", i, test.retried, resp.Retries)
+		}
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	conf := NewConfig(context.Background(), func() {})
+	conf.RetryBackoff = 0.5
+	job := NewJob(&conf)
+	for attempt, max := range []time.Duration{500 * time.Millisecond, time.Second, 2 * time.Second, 4 * time.Second} {
+		for i := 0; i < 10; i++ {
+			if delay := job.retryDelay(attempt, Response{}); delay < max/2 || delay > max {
+				t.Errorf("Expected the delay of attempt %d to be between %s and %s, got %s", attempt, max/2, max, delay)
+			}
+		}
+	}
+	if delay := job.retryDelay(20, Response{}); delay > RETRY_BACKOFF_MAX {
+		t.Errorf("Expected the delay to be capped at %s, got %s", RETRY_BACKOFF_MAX, delay)
+	}
+	resp := Response{StatusCode: 429, Headers: map[string][]string{"Retry-After": {"10"}}}
+	if delay := job.retryDelay(0, resp); delay != 10*time.Second {
+		t.Errorf("Expected Retry-After header to be honored, got %s", delay)
+	}
+}
@@ -0,0 +1,269 @@
+package ffuf
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SESSION_MIN_LIFETIME is the time after a login during which logged out responses are not considered expired
+// sessions, but responses of the fuzzed resource
+const SESSION_MIN_LIFETIME = 5 * time.Second
+
+// SessionExtractor extracts a value from the login response to replace a keyword in the requests
+type SessionExtractor struct {
+	Keyword string
+	Type    string
+	Value   string
+	regexp  *regexp.Regexp
+}
+
+// Session runs the login request, and keeps the cookies and values extracted from the login response for the
+// requests of the job. The login is run again when a response matches the logged out matchers.
+type Session struct {
+	config     *Config
+	mutex      sync.RWMutex
+	extractors []SessionExtractor
+	status     []ValueRange
+	regexp     *regexp.Regexp
+	cookies    map[string]string
+	values     map[string]string
+	generation int
+	lastLogin  time.Time
+}
+
+// ParseSessionExtractor parses a session value definition in format KEYWORD=regexp:<regexp> or KEYWORD=json:<path>
+func ParseSessionExtractor(value string) (SessionExtractor, error) {
+	var e SessionExtractor
+	kv := strings.SplitN(value, "=", 2)
+	if len(kv) != 2 || kv[0] == "" {
+		return e, fmt.Errorf("expected format KEYWORD=regexp:<regexp> or KEYWORD=json:<path>, got %s", value)
+	}
+	e.Keyword = kv[0]
+	tv := strings.SplitN(kv[1], ":", 2)
+	if len(tv) != 2 || tv[1] == "" {
+		return e, fmt.Errorf("expected format KEYWORD=regexp:<regexp> or KEYWORD=json:<path>, got %s", value)
+	}
+	e.Type = tv[0]
+	e.Value = tv[1]
+	switch e.Type {
+	case "regexp":
+		re, err := regexp.Compile(e.Value)
+		if err != nil {
+			return e, err
+		}
+		e.regexp = re
+	case "json":
+	default:
+		return e, fmt.Errorf("unknown extractor type %s, expected regexp or json", e.Type)
+	}
+	return e, nil
+}
+
+// Extract returns the value from the login response. A regexp returns its first capture group if it has one, and
+// the whole match otherwise. It is matched against the response headers and body.
+func (e *SessionExtractor) Extract(resp *Response) (string, bool) {
+	if e.Type == "json" {
+		return jsonPathValue(resp.Data, e.Value)
+	}
+	var raw strings.Builder
+	for k, vals := range resp.Headers {
+		for _, v := range vals {
+			raw.WriteString(k + ": " + v + "\n")
+		}
+	}
+	raw.WriteString("\n")
+	raw.Write(resp.Data)
+	match := e.regexp.FindStringSubmatch(raw.String())
+	if match == nil {
+		return "", false
+	}
+	if len(match) > 1 {
+		return match[1], true
+	}
+	return match[0], true
+}
+
+// jsonPathValue returns the value from a JSON document at a dot separated path, eg. data.tokens.0.value
+func jsonPathValue(data []byte, path string) (string, bool) {
+	var doc interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return "", false
+	}
+	for _, key := range strings.Split(path, ".") {
+		switch node := doc.(type) {
+		case map[string]interface{}:
+			val, ok := node[key]
+			if !ok {
+				return "", false
+			}
+			doc = val
+		case []interface{}:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(node) {
+				return "", false
+			}
+			doc = node[i]
+		default:
+			return "", false
+		}
+	}
+	switch val := doc.(type) {
+	case nil:
+		return "", false
+	case string:
+		return val, true
+	default:
+		b, _ := json.Marshal(val)
+		return string(b), true
+	}
+}
+
+// NewSession creates a session from the configuration. The options are validated when parsing the configuration.
+func NewSession(conf *Config) *Session {
+	s := &Session{
+		config:     conf,
+		extractors: make([]SessionExtractor, 0),
+		cookies:    make(map[string]string),
+		values:     make(map[string]string),
+	}
+	for _, v := range conf.SessionExtract {
+		e, err := ParseSessionExtractor(v)
+		if err == nil {
+			s.extractors = append(s.extractors, e)
+		}
+	}
+	s.status = conf.SessionMatchStatus
+	if conf.SessionMatchRegexp != "" {
+		s.regexp, _ = regexp.Compile(conf.SessionMatchRegexp)
+	}
+	return s
+}
+
+// Login sends the login request, and replaces the session cookies and values with the ones from the response
+func (s *Session) Login(runner RunnerProvider) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.login(runner)
+}
+
+func (s *Session) login(runner RunnerProvider) error {
+	req := CopyRequest(s.config.SessionRequest)
+	resp, err := runner.Execute(&req)
+	if err != nil {
+		return fmt.Errorf("login request failed: %s", err)
+	}
+	cookies := make(map[string]string)
+	for _, sc := range resp.Headers["Set-Cookie"] {
+		c, err := http.ParseSetCookie(sc)
+		if err == nil {
+			cookies[c.Name] = c.Value
+		}
+	}
+	values := make(map[string]string)
+	for _, e := range s.extractors {
+		val, ok := e.Extract(&resp)
+		if !ok {
+			return fmt.Errorf("could not extract %s from the login response (status %d)", e.Keyword, resp.StatusCode)
+		}
+		values[e.Keyword] = val
+	}
+	s.cookies = cookies
+	s.values = values
+	s.generation++
+	s.lastLogin = time.Now()
+	return nil
+}
+
+// Refresh runs the login again after a request of the session generation got a logged out response, and returns
+// true if the session has been renewed since. The login is skipped if another request has already done it, or if
+// the session was created too recently to have expired.
+func (s *Session) Refresh(runner RunnerProvider, generation int) (bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.generation != generation {
+		return true, nil
+	}
+	if time.Since(s.lastLogin) < SESSION_MIN_LIFETIME {
+		return false, nil
+	}
+	if err := s.login(runner); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Apply returns a copy of the request with the session cookies and values added, and the session generation
+func (s *Session) Apply(basereq *Request) (Request, int) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	req := CopyRequest(basereq)
+	for keyword, val := range s.values {
+		req.Url = strings.ReplaceAll(req.Url, keyword, val)
+		req.Data = []byte(strings.ReplaceAll(string(req.Data), keyword, val))
+		headers := make(map[string]string, len(req.Headers))
+		for h, v := range req.Headers {
+			headers[strings.ReplaceAll(h, keyword, val)] = strings.ReplaceAll(v, keyword, val)
+		}
+		req.Headers = headers
+	}
+	if len(s.cookies) > 0 {
+		req.Headers["Cookie"] = MergeCookies(req.Headers["Cookie"], s.cookies)
+	}
+	return req, s.generation
+}
+
+// Expired checks if the response matches the logged out matchers
+func (s *Session) Expired(resp *Response) bool {
+	for _, vr := range s.status {
+		if resp.StatusCode >= vr.Min && resp.StatusCode <= vr.Max {
+			return true
+		}
+	}
+	return s.regexp != nil && s.regexp.Match(resp.Data)
+}
+
+// Values returns the names of the session cookies and extracted keywords
+func (s *Session) Values() []string {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	names := make([]string, 0)
+	for k := range s.cookies {
+		names = append(names, "cookie "+k)
+	}
+	for k := range s.values {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// MergeCookies adds the cookies to a Cookie header value, replacing the existing cookies with the same name
+func MergeCookies(header string, cookies map[string]string) string {
+	merged := make([]string, 0)
+	for _, c := range strings.Split(header, ";") {
+		c = strings.TrimSpace(c)
+		if c == "" {
+			continue
+		}
+		name := strings.SplitN(c, "=", 2)[0]
+		if _, ok := cookies[name]; !ok {
+			merged = append(merged, c)
+		}
+	}
+	names := make([]string, 0, len(cookies))
+	for name := range cookies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		merged = append(merged, name+"="+cookies[name])
+	}
+	return strings.Join(merged, "; ")
+}
@@ -0,0 +1,136 @@
+package ffuf
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// loginRunner returns a login response with a new session cookie and token on each request
+type loginRunner struct {
+	logins int
+}
+
+func (r *loginRunner) Prepare(input map[string][]byte, basereq *Request) (Request, error) {
+	return CopyRequest(basereq), nil
+}
+
+func (r *loginRunner) Execute(req *Request) (Response, error) {
+	r.logins++
+	token := string(rune('a' + r.logins))
+	return Response{
+		StatusCode: 200,
+		Headers:    map[string][]string{"Set-Cookie": {"sid=session" + token + "; Path=/; HttpOnly"}},
+		Data:       []byte(`<form><input type="hidden" name="csrf" value="csrf` + token + `"></form>`),
+		Request:    req,
+	}, nil
+}
+
+func (r *loginRunner) Dump(req *Request) ([]byte, error) { return []byte{}, nil }
+
+func TestParseSessionExtractor(t *testing.T) {
+	for _, test := range []struct {
+		value string
+		err   bool
+	}{
+		{"TOKEN=json:data.token", false},
+		{"CSRF=regexp:value=\"([^\"]+)\"", false},
+		{"CSRF=regexp:value=([", true},
+		{"CSRF=xpath://input", true},
+		{"CSRF=regexp:", true},
+		{"=json:token", true},
+		{"json:token", true},
+	} {
+		_, err := ParseSessionExtractor(test.value)
+		if (err != nil) != test.err {
+			t.Errorf("Session extractor %s: expected error %t, got %v", test.value, test.err, err)
+		}
+	}
+}
+
+func TestSessionExtract(t *testing.T) {
+	resp := Response{
+		Headers: map[string][]string{"X-Auth-Token": {"headertoken"}},
+		Data:    []byte(`{"data":{"tokens":[{"value":"first"},{"value":"second"}],"expires":3600}}`),
+	}
+	for _, test := range []struct {
+		extractor string
+		value     string
+		ok        bool
+	}{
+		{"T=json:data.tokens.1.value", "second", true},
+		{"T=json:data.expires", "3600", true},
+		{"T=json:data.tokens.2.value", "", false},
+		{"T=json:data.missing", "", false},
+		{"T=regexp:X-Auth-Token: (\\w+)", "headertoken", true},
+		{"T=regexp:\"first\"", "\"first\"", true},
+		{"T=regexp:nomatch", "", false},
+	} {
+		e, _ := ParseSessionExtractor(test.extractor)
+		value, ok := e.Extract(&resp)
+		if value != test.value || ok != test.ok {
+			t.Errorf("Session extractor %s: expected %s and %t, got %s and %t", test.extractor, test.value, test.ok, value, ok)
+		}
+	}
+}
+
+func TestMergeCookies(t *testing.T) {
+	cookies := map[string]string{"sid": "new", "auth": "token"}
+	if merged := MergeCookies("lang=en; sid=old", cookies); merged != "lang=en; auth=token; sid=new" {
+		t.Errorf("Expected session cookies to replace the existing ones, got %s", merged)
+	}
+	if merged := MergeCookies("", cookies); merged != "auth=token; sid=new" {
+		t.Errorf("Expected only the session cookies, got %s", merged)
+	}
+}
+
+func TestSession(t *testing.T) {
+	conf := NewConfig(context.Background(), func() {})
+	conf.SessionRequest = &Request{Method: "POST", Url: "http://example.com/login", Headers: map[string]string{}}
+	conf.SessionExtract = []string{"CSRFTOKEN=regexp:name=\"csrf\" value=\"([^\"]+)\""}
+	conf.SessionMatchStatus = []ValueRange{{401, 401}}
+	conf.SessionMatchRegexp = "Please log in"
+	session := NewSession(&conf)
+	runner := &loginRunner{}
+	if err := session.Login(runner); err != nil {
+		t.Fatalf("Login failed: %v", err)
+	}
+
+	basereq := Request{Url: "http://example.com/FUZZ", Data: []byte("csrf=CSRFTOKEN"), Headers: map[string]string{"Cookie": "lang=en", "X-Csrf": "CSRFTOKEN"}}
+	req, generation := session.Apply(&basereq)
+	if string(req.Data) != "csrf=csrfb" || req.Headers["X-Csrf"] != "csrfb" || req.Headers["Cookie"] != "lang=en; sid=sessionb" {
+		t.Errorf("Expected the session values in the request, got %s and %v", req.Data, req.Headers)
+	}
+	if basereq.Headers["X-Csrf"] != "CSRFTOKEN" {
+		t.Errorf("Expected the base request not to be modified")
+	}
+
+	if !session.Expired(&Response{StatusCode: 401}) || !session.Expired(&Response{StatusCode: 200, Data: []byte("Please log in")}) {
+		t.Errorf("Expected logged out responses to be detected")
+	}
+	if session.Expired(&Response{StatusCode: 200, Data: []byte("Welcome")}) {
+		t.Errorf("Expected a logged in response not to be detected as logged out")
+	}
+
+	// A session that was just created is not refreshed
+	if renewed, _ := session.Refresh(runner, generation); renewed || runner.logins != 1 {
+		t.Errorf("Expected a new session not to be refreshed, got %t with %d logins", renewed, runner.logins)
+	}
+	session.lastLogin = time.Now().Add(-SESSION_MIN_LIFETIME)
+	if renewed, err := session.Refresh(runner, generation); !renewed || err != nil || runner.logins != 2 {
+		t.Errorf("Expected the session to be refreshed, got %t and %v with %d logins", renewed, err, runner.logins)
+	}
+	// Concurrent requests of the old session reuse the new session
+	if renewed, _ := session.Refresh(runner, generation); !renewed || runner.logins != 2 {
+		t.Errorf("Expected the renewed session to be reused, got %t with %d logins", renewed, runner.logins)
+	}
+	req, _ = session.Apply(&basereq)
+	if req.Headers["X-Csrf"] != "csrfc" || req.Headers["Cookie"] != "lang=en; sid=sessionc" {
+		t.Errorf("Expected the renewed session values in the request, got %v", req.Headers)
+	}
+
+	conf.SessionExtract = []string{"TOKEN=json:token"}
+	if err := NewSession(&conf).Login(runner); err == nil {
+		t.Errorf("Expected an error when a session value is missing from the login response")
+	}
+}
@@ -0,0 +1,69 @@
+package ffuf
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"math/bits"
+	"strings"
+	"unicode"
+)
+
+// Simhash is a 128 bit similarity preserving hash of the words in a response body. Small changes in
+// the body, like timestamps or tokens, only change a few bits of the hash.
+type Simhash [2]uint64
+
+// NewSimhash calculates the simhash of the data
+func NewSimhash(data []byte) Simhash {
+	tokens := strings.FieldsFunc(string(data), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	var weights [128]int
+	for _, token := range tokens {
+		h := fnv.New128a()
+		_, _ = h.Write([]byte(token))
+		sum := h.Sum(nil)
+		for b := 0; b < 128; b++ {
+			if sum[b/8]&(1<<(b%8)) != 0 {
+				weights[b]++
+			} else {
+				weights[b]--
+			}
+		}
+	}
+	var s Simhash
+	for b := 0; b < 128; b++ {
+		if weights[b] > 0 {
+			s[b/64] |= 1 << (b % 64)
+		}
+	}
+	return s
+}
+
+// ParseSimhash parses a simhash from its hex representation
+func ParseSimhash(value string) (Simhash, error) {
+	var s Simhash
+	b, err := hex.DecodeString(value)
+	if err != nil || len(b) != 16 {
+		return s, fmt.Errorf("invalid simhash: %s", value)
+	}
+	s[0] = binary.BigEndian.Uint64(b[0:8])
+	s[1] = binary.BigEndian.Uint64(b[8:16])
+	return s, nil
+}
+
+func (s Simhash) String() string {
+	return fmt.Sprintf("%016x%016x", s[0], s[1])
+}
+
+// Similarity returns the estimated cosine similarity of the word counts of the two hashed bodies in percent
+func (s Simhash) Similarity(other Simhash) int {
+	distance := bits.OnesCount64(s[0]^other[0]) + bits.OnesCount64(s[1]^other[1])
+	similarity := math.Cos(math.Pi * float64(distance) / 128)
+	if similarity < 0 {
+		return 0
+	}
+	return int(math.Round(similarity * 100))
+}
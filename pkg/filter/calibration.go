@@ -0,0 +1,60 @@
+package filter
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/ffuf/ffuf/v2/pkg/ffuf"
+)
+
+// CalibrationFilter is a composite filter built by autocalibration. It filters responses that belong to
+// any of the learned response clusters.
+type CalibrationFilter struct {
+	Clusters []ffuf.ResponseCluster
+}
+
+func NewCalibrationFilter(value string) (ffuf.FilterProvider, error) {
+	var f CalibrationFilter
+	for _, sv := range strings.Split(value, ",") {
+		c, err := ffuf.ParseResponseCluster(strings.TrimSpace(sv))
+		if err != nil {
+			return &f, fmt.Errorf("Autocalibration filter: %s", err)
+		}
+		f.Clusters = append(f.Clusters, c)
+	}
+	return &f, nil
+}
+
+func (f *CalibrationFilter) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&struct {
+		Value string `json:"value"`
+	}{
+		Value: f.Repr(),
+	})
+}
+
+func (f *CalibrationFilter) Filter(response *ffuf.Response) (bool, error) {
+	for _, c := range f.Clusters {
+		if c.Match(response) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (f *CalibrationFilter) Repr() string {
+	var strval []string
+	for _, c := range f.Clusters {
+		strval = append(strval, c.String())
+	}
+	return strings.Join(strval, ",")
+}
+
+func (f *CalibrationFilter) ReprVerbose() string {
+	var strval []string
+	for _, c := range f.Clusters {
+		strval = append(strval, "["+c.Description()+"]")
+	}
+	return fmt.Sprintf("Autocalibrated responses: %s", strings.Join(strval, " "))
+}
@@ -7,10 +7,11 @@ import (
 	"github.com/ffuf/ffuf/v2/pkg/ffuf"
 )
 
-// MatcherManager handles both filters and matchers.
+// MatcherManager handles both filters and matchers. The filters can change while the requests are running, eg. when
+// the autocalibration is run again, so the getters return copies of the filter maps.
 type MatcherManager struct {
 	IsCalibrated     bool
-	Mutex            sync.Mutex
+	Mutex            sync.RWMutex
 	Matchers         map[string]ffuf.FilterProvider
 	Filters          map[string]ffuf.FilterProvider
 	PerDomainFilters map[string]*PerDomainFilter
@@ -21,8 +22,10 @@ type PerDomainFilter struct {
 	Filters      map[string]ffuf.FilterProvider
 }
 
+// NewPerDomainFilter starts the filters of a domain from a copy of the global filters, so that the calibration of one
+// domain does not end up in the filters of the others
 func NewPerDomainFilter(globfilters map[string]ffuf.FilterProvider) *PerDomainFilter {
-	return &PerDomainFilter{IsCalibrated: false, Filters: globfilters}
+	return &PerDomainFilter{IsCalibrated: false, Filters: copyFilters(globfilters)}
 }
 
 func (p *PerDomainFilter) SetCalibrated(value bool) {
@@ -39,10 +42,14 @@ func NewMatcherManager() ffuf.MatcherManager {
 }
 
 func (f *MatcherManager) SetCalibrated(value bool) {
+	f.Mutex.Lock()
+	defer f.Mutex.Unlock()
 	f.IsCalibrated = value
 }
 
 func (f *MatcherManager) SetCalibratedForHost(host string, value bool) {
+	f.Mutex.Lock()
+	defer f.Mutex.Unlock()
 	if f.PerDomainFilters[host] != nil {
 		f.PerDomainFilters[host].IsCalibrated = value
 	} else {
@@ -71,6 +78,12 @@ func NewFilterByName(name string, value string) (ffuf.FilterProvider, error) {
 	if name == "time" {
 		return NewTimeFilter(value)
 	}
+	if name == "similarity" {
+		return NewSimilarityFilter(value)
+	}
+	if name == "calibration" {
+		return NewCalibrationFilter(value)
+	}
 	return nil, fmt.Errorf("Could not create filter with name %s", name)
 }
 
@@ -78,18 +91,13 @@ func NewFilterByName(name string, value string) (ffuf.FilterProvider, error) {
 func (f *MatcherManager) AddFilter(name string, option string, replace bool) error {
 	f.Mutex.Lock()
 	defer f.Mutex.Unlock()
+	if f.Filters[name] != nil && !replace {
+		// append to the existing filter
+		option = f.Filters[name].Repr() + "," + option
+	}
 	newf, err := NewFilterByName(name, option)
 	if err == nil {
-		// valid filter create or append
-		if f.Filters[name] == nil || replace {
-			f.Filters[name] = newf
-		} else {
-			newoption := f.Filters[name].Repr() + "," + option
-			newerf, err := NewFilterByName(name, newoption)
-			if err == nil {
-				f.Filters[name] = newerf
-			}
-		}
+		f.Filters[name] = newf
 	}
 	return err
 }
@@ -104,18 +112,13 @@ func (f *MatcherManager) AddPerDomainFilter(domain string, name string, option s
 	} else {
 		pdFilters = NewPerDomainFilter(f.Filters)
 	}
+	if pdFilters.Filters[name] != nil {
+		// append to the existing filter
+		option = pdFilters.Filters[name].Repr() + "," + option
+	}
 	newf, err := NewFilterByName(name, option)
 	if err == nil {
-		// valid filter create or append
-		if pdFilters.Filters[name] == nil {
-			pdFilters.Filters[name] = newf
-		} else {
-			newoption := pdFilters.Filters[name].Repr() + "," + option
-			newerf, err := NewFilterByName(name, newoption)
-			if err == nil {
-				pdFilters.Filters[name] = newerf
-			}
-		}
+		pdFilters.Filters[name] = newf
 	}
 	f.PerDomainFilters[domain] = pdFilters
 	return err
@@ -132,38 +135,54 @@ func (f *MatcherManager) RemoveFilter(name string) {
 func (f *MatcherManager) AddMatcher(name string, option string) error {
 	f.Mutex.Lock()
 	defer f.Mutex.Unlock()
+	if f.Matchers[name] != nil {
+		// append to the existing matcher
+		option = f.Matchers[name].Repr() + "," + option
+	}
 	newf, err := NewFilterByName(name, option)
 	if err == nil {
-		// valid filter create or append
-		if f.Matchers[name] == nil {
-			f.Matchers[name] = newf
-		} else {
-			newoption := f.Matchers[name].Repr() + "," + option
-			newerf, err := NewFilterByName(name, newoption)
-			if err == nil {
-				f.Matchers[name] = newerf
-			}
-		}
+		f.Matchers[name] = newf
 	}
 	return err
 }
 
+// copyFilters returns a copy of a filter map. The filters themselves are not changed after they are created, so they
+// can be shared.
+func copyFilters(filters map[string]ffuf.FilterProvider) map[string]ffuf.FilterProvider {
+	ret := make(map[string]ffuf.FilterProvider, len(filters))
+	for name, f := range filters {
+		ret[name] = f
+	}
+	return ret
+}
+
+// GetFilters returns a copy of the filters
 func (f *MatcherManager) GetFilters() map[string]ffuf.FilterProvider {
-	return f.Filters
+	f.Mutex.RLock()
+	defer f.Mutex.RUnlock()
+	return copyFilters(f.Filters)
 }
 
+// GetMatchers returns a copy of the matchers
 func (f *MatcherManager) GetMatchers() map[string]ffuf.FilterProvider {
-	return f.Matchers
+	f.Mutex.RLock()
+	defer f.Mutex.RUnlock()
+	return copyFilters(f.Matchers)
 }
 
+// FiltersForDomain returns a copy of the filters of a domain, or of the global filters if the domain has none
 func (f *MatcherManager) FiltersForDomain(domain string) map[string]ffuf.FilterProvider {
+	f.Mutex.RLock()
+	defer f.Mutex.RUnlock()
 	if f.PerDomainFilters[domain] == nil {
-		return f.Filters
+		return copyFilters(f.Filters)
 	}
-	return f.PerDomainFilters[domain].Filters
+	return copyFilters(f.PerDomainFilters[domain].Filters)
 }
 
 func (f *MatcherManager) CalibratedForDomain(domain string) bool {
+	f.Mutex.RLock()
+	defer f.Mutex.RUnlock()
 	if f.PerDomainFilters[domain] != nil {
 		return f.PerDomainFilters[domain].IsCalibrated
 	}
@@ -171,5 +190,7 @@ func (f *MatcherManager) CalibratedForDomain(domain string) bool {
 }
 
 func (f *MatcherManager) Calibrated() bool {
+	f.Mutex.RLock()
+	defer f.Mutex.RUnlock()
 	return f.IsCalibrated
 }
@@ -1,7 +1,11 @@
 package filter
 
 import (
+	"fmt"
+	"sync"
 	"testing"
+
+	"github.com/ffuf/ffuf/v2/pkg/ffuf"
 )
 
 func TestNewFilterByName(t *testing.T) {
@@ -34,6 +38,29 @@ func TestNewFilterByName(t *testing.T) {
 	if _, ok := tf.(*TimeFilter); !ok {
 		t.Errorf("Was expecting timefilter")
 	}
+
+	simf, _ := NewFilterByName("similarity", "95")
+	if _, ok := simf.(*SimilarityFilter); !ok {
+		t.Errorf("Was expecting similarityfilter")
+	}
+}
+
+func TestAddFilterAppend(t *testing.T) {
+	m := NewMatcherManager()
+	_ = m.AddFilter("size", "42", false)
+	_ = m.AddFilter("size", "1337", false)
+	if m.GetFilters()["size"].Repr() != "42,1337" {
+		t.Errorf("Was expecting appended size filter, got %s", m.GetFilters()["size"].Repr())
+	}
+	// Similarity baselines are only valid when appended to a filter with a threshold
+	_ = m.AddFilter("similarity", "95", false)
+	err := m.AddFilter("similarity", "0123456789abcdef0123456789abcdef", false)
+	if err != nil || m.GetFilters()["similarity"].Repr() != "95,0123456789abcdef0123456789abcdef" {
+		t.Errorf("Was expecting appended similarity baseline, got %s (%v)", m.GetFilters()["similarity"].Repr(), err)
+	}
+	if err := m.AddFilter("size", "invalid", false); err == nil || m.GetFilters()["size"].Repr() != "42,1337" {
+		t.Errorf("Was expecting an error and the existing filter to be kept")
+	}
 }
 
 func TestNewFilterByNameError(t *testing.T) {
@@ -49,3 +76,64 @@ func TestNewFilterByNameNotFound(t *testing.T) {
 		t.Errorf("Was expecing an error with invalid filter name")
 	}
 }
+
+// TestMatcherManagerRecalibration runs a recalibration while requests are being matched, for the race detector
+func TestMatcherManagerRecalibration(t *testing.T) {
+	m := NewMatcherManager()
+	_ = m.AddFilter("size", "100", false)
+	m.SetCalibrated(true)
+	m.SetCalibratedForHost("http://example.com", true)
+	resp := &ffuf.Response{StatusCode: 200, ContentLength: 100, ContentWords: 10, ContentLines: 1}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for n := 0; n < 200; n++ {
+				for _, f := range m.GetFilters() {
+					_, _ = f.Filter(resp)
+				}
+				for _, f := range m.FiltersForDomain("http://example.com") {
+					_, _ = f.Filter(resp)
+				}
+				_ = m.Calibrated()
+				_ = m.CalibratedForDomain("http://example.com")
+			}
+		}()
+	}
+	for n := 0; n < 50; n++ {
+		m.SetCalibrated(false)
+		_ = m.AddFilter("size", fmt.Sprintf("%d", n), false)
+		m.SetCalibrated(true)
+		m.SetCalibratedForHost("http://example.com", false)
+		_ = m.AddPerDomainFilter("http://example.com", "size", fmt.Sprintf("%d", n))
+		m.SetCalibratedForHost("http://example.com", true)
+	}
+	wg.Wait()
+	if len(m.GetFilters()) != 1 {
+		t.Errorf("Expected the size filter, got %v", m.GetFilters())
+	}
+}
+
+func TestPerDomainFiltersSeparate(t *testing.T) {
+	m := NewMatcherManager()
+	_ = m.AddFilter("status", "500", false)
+	m.SetCalibratedForHost("http://a.example.com", false)
+	_ = m.AddPerDomainFilter("http://a.example.com", "size", "100")
+	m.SetCalibratedForHost("http://a.example.com", true)
+	_ = m.AddPerDomainFilter("http://b.example.com", "size", "200")
+	m.SetCalibratedForHost("http://b.example.com", true)
+
+	a := m.FiltersForDomain("http://a.example.com")
+	b := m.FiltersForDomain("http://b.example.com")
+	if a["size"] == nil || a["size"].Repr() != "100" || a["status"] == nil {
+		t.Errorf("Expected the status filter and the calibration of the first host, got %v", a)
+	}
+	if b["size"] == nil || b["size"].Repr() != "200" || b["status"] == nil {
+		t.Errorf("Expected the status filter and the calibration of the second host, got %v", b)
+	}
+	if global := m.GetFilters(); len(global) != 1 || global["status"] == nil {
+		t.Errorf("Expected the global filters not to include the calibration of the hosts, got %v", global)
+	}
+}
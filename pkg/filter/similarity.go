@@ -0,0 +1,96 @@
+package filter
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/ffuf/ffuf/v2/pkg/ffuf"
+)
+
+// SimilarityFilter matches responses whose body is similar to one of the baseline bodies. The bodies are
+// compared using simhashes, so small dynamic changes like timestamps or tokens do not affect the result much.
+type SimilarityFilter struct {
+	Threshold int
+	Baselines []ffuf.Simhash
+}
+
+// NewSimilarityFilter creates a new similarity filter. The value is the similarity threshold in percent,
+// optionally followed by a colon and a path to a file containing a baseline response body. Baseline hashes
+// added through autocalibration follow the threshold as a comma separated list, eg. "95,9a3f0c2b1d4e5f609a3f0c2b1d4e5f60"
+func NewSimilarityFilter(value string) (ffuf.FilterProvider, error) {
+	var f SimilarityFilter
+	parts := strings.Split(value, ",")
+	threshold := strings.TrimSpace(parts[0])
+	baselinefile := ""
+	if p := strings.SplitN(threshold, ":", 2); len(p) == 2 {
+		threshold = p[0]
+		baselinefile = p[1]
+	}
+	t, err := strconv.Atoi(threshold)
+	if err != nil || t < 0 || t > 100 {
+		return &f, fmt.Errorf("Similarity filter or matcher (-fsim): invalid threshold: %s, expected a percentage from 0 to 100", threshold)
+	}
+	f.Threshold = t
+	if baselinefile != "" {
+		data, err := os.ReadFile(baselinefile)
+		if err != nil {
+			return &f, fmt.Errorf("Similarity filter or matcher (-fsim): could not read baseline file: %s", err)
+		}
+		f.Baselines = append(f.Baselines, ffuf.NewSimhash(data))
+	}
+	for _, sv := range parts[1:] {
+		h, err := ffuf.ParseSimhash(strings.TrimSpace(sv))
+		if err != nil {
+			return &f, fmt.Errorf("Similarity filter or matcher (-fsim): invalid baseline hash: %s", sv)
+		}
+		if !f.hasBaseline(h) {
+			f.Baselines = append(f.Baselines, h)
+		}
+	}
+	return &f, nil
+}
+
+func (f *SimilarityFilter) hasBaseline(hash ffuf.Simhash) bool {
+	for _, b := range f.Baselines {
+		if b == hash {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *SimilarityFilter) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&struct {
+		Value string `json:"value"`
+	}{
+		Value: f.Repr(),
+	})
+}
+
+func (f *SimilarityFilter) Filter(response *ffuf.Response) (bool, error) {
+	if len(f.Baselines) == 0 {
+		return false, nil
+	}
+	hash := ffuf.NewSimhash(response.Data)
+	for _, b := range f.Baselines {
+		if hash.Similarity(b) >= f.Threshold {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (f *SimilarityFilter) Repr() string {
+	strval := []string{strconv.Itoa(f.Threshold)}
+	for _, b := range f.Baselines {
+		strval = append(strval, b.String())
+	}
+	return strings.Join(strval, ",")
+}
+
+func (f *SimilarityFilter) ReprVerbose() string {
+	return fmt.Sprintf("Response similarity: >= %d%% to %d baseline(s)", f.Threshold, len(f.Baselines))
+}
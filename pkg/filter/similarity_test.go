@@ -0,0 +1,80 @@
+package filter
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ffuf/ffuf/v2/pkg/ffuf"
+)
+
+func soft404Page(path string, token string) string {
+	var b strings.Builder
+	b.WriteString("<html><head><title>Page not found</title></head><body>\n")
+	b.WriteString("<div class=\"header\"><a href=\"/\">Home</a> <a href=\"/about\">About us</a> <a href=\"/contact\">Contact</a></div>\n")
+	b.WriteString(fmt.Sprintf("<h1>The page %s could not be found</h1>\n", path))
+	b.WriteString("<p>The page you are looking for might have been removed, had its name changed or is temporarily unavailable.</p>\n")
+	b.WriteString("<p>Please check the spelling of the address, or head back to the front page to find what you were looking for.</p>\n")
+	b.WriteString(fmt.Sprintf("<form><input type=\"hidden\" name=\"csrf\" value=\"%s\"><input name=\"q\"><button>Search</button></form>\n", token))
+	b.WriteString("<div class=\"footer\">Copyright example corp. All rights reserved. Privacy policy. Terms of service.</div></body></html>\n")
+	return b.String()
+}
+
+func TestNewSimilarityFilter(t *testing.T) {
+	f, _ := NewSimilarityFilter("95,000000000000000000000000000000ff,000000000000000000000000000000ff,0123456789abcdef0123456789abcdef")
+	if f.Repr() != "95,000000000000000000000000000000ff,0123456789abcdef0123456789abcdef" {
+		t.Errorf("Similarity filter was expected to have threshold and two unique baselines, got %s", f.Repr())
+	}
+	// Repr needs to be parseable for appending baselines
+	f2, _ := NewSimilarityFilter(f.Repr() + ",11111111111111111111111111111111")
+	if len(f2.(*SimilarityFilter).Baselines) != 3 {
+		t.Errorf("Was expecting appended baseline, got %s", f2.Repr())
+	}
+}
+
+func TestNewSimilarityFilterError(t *testing.T) {
+	for _, value := range []string{"invalid", "101", "95,nothex", "95,00ff", "95:/nonexistent/baseline"} {
+		_, err := NewSimilarityFilter(value)
+		if err == nil {
+			t.Errorf("Was expecting an error from errenous input data: %s", value)
+		}
+	}
+}
+
+func TestSimilarityFiltering(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "ffuf-test")
+	if err != nil {
+		t.Fatalf("Failed to create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+	baseline := filepath.Join(tmpDir, "baseline.html")
+	_ = os.WriteFile(baseline, []byte(soft404Page("/abcdef", "8f2a9c1e7b")), 0644)
+
+	f, err := NewSimilarityFilter("95:" + baseline)
+	if err != nil {
+		t.Fatalf("Failed to create similarity filter: %v", err)
+	}
+	for i, test := range []struct {
+		input  string
+		output bool
+	}{
+		{soft404Page("/abcdef", "8f2a9c1e7b"), true},
+		{soft404Page("/backup", "d41d8cd98f"), true},
+		{soft404Page("/admin/login.php", "0cc175b9c0"), true},
+		{"<html><body><h1>Admin login</h1><form><input name=\"user\"><input name=\"password\" type=\"password\"></form></body></html>", false},
+		{"", false},
+	} {
+		resp := ffuf.Response{Data: []byte(test.input)}
+		filterReturn, _ := f.Filter(&resp)
+		if filterReturn != test.output {
+			t.Errorf("Filter test %d: Was expecing filter return value of %t but got %t", i, test.output, filterReturn)
+		}
+	}
+
+	nobaseline, _ := NewSimilarityFilter("0")
+	if match, _ := nobaseline.Filter(&ffuf.Response{Data: []byte("anything")}); match {
+		t.Errorf("Similarity filter without baselines should not filter anything")
+	}
+}
@@ -1,14 +1,28 @@
 package input
 
 import (
+	"bufio"
 	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
 	"os"
 	"os/exec"
 	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/ffuf/ffuf/v2/pkg/ffuf"
 )
 
+// The record an input command can start its output with to announce the number of payloads, eg. "FFUF_TOTAL=1000"
+const COMMAND_TOTAL_PREFIX = "FFUF_TOTAL="
+
+// How long a long-lived input command in request mode has to reply to the total request before it is stopped
+var commandTotalTimeout = 10 * time.Second
+
 type CommandInput struct {
 	config  *ffuf.Config
 	count   int
@@ -67,8 +81,8 @@ func (c *CommandInput) Next() bool {
 // Value returns the input from command stdoutput
 func (c *CommandInput) Value() []byte {
 	var stdout bytes.Buffer
-	os.Setenv("FFUF_NUM", strconv.Itoa(c.count))
 	cmd := exec.Command(c.shell, SHELL_ARG, c.command)
+	cmd.Env = append(os.Environ(), "FFUF_NUM="+strconv.Itoa(c.count))
 	cmd.Stdout = &stdout
 	err := cmd.Run()
 	if err != nil {
@@ -93,3 +107,241 @@ func (c *CommandInput) Enable() {
 func (c *CommandInput) Disable() {
 	c.active = false
 }
+
+// announcedTotal parses the record announcing the number of payloads of an input command
+func announcedTotal(record []byte) (int, bool) {
+	value, found := strings.CutPrefix(string(record), COMMAND_TOTAL_PREFIX)
+	if !found {
+		return 0, false
+	}
+	total, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || total < 0 {
+		return 0, false
+	}
+	return total, true
+}
+
+// commandDelimiter returns the delimiter of the payloads written by the long-lived input commands
+func commandDelimiter(conf *ffuf.Config) byte {
+	if conf.InputCmdNul {
+		return 0
+	}
+	return '\n'
+}
+
+func commandShell(conf *ffuf.Config) string {
+	if conf.InputShell != "" {
+		return conf.InputShell
+	}
+	return SHELL_CMD
+}
+
+func commandContext(conf *ffuf.Config) context.Context {
+	if conf.Context != nil {
+		return conf.Context
+	}
+	return context.Background()
+}
+
+// stopWithPipes kills the input command when its context is done, after closing its pipes, so that the processes it
+// started stop too once they write to or read from them
+func stopWithPipes(cmd *exec.Cmd, pipes ...io.Closer) {
+	cmd.Cancel = func() error {
+		for _, p := range pipes {
+			_ = p.Close()
+		}
+		return cmd.Process.Kill()
+	}
+}
+
+// NewCommandStreamInput starts the input command once, and reads the payloads from its output as they are written.
+// The output is spooled to disk the same way as a streamed wordlist from stdin, so the command can write any number of
+// payloads.
+func NewCommandStreamInput(keyword string, value string, conf *ffuf.Config) (*StreamWordlistInput, error) {
+	var err error
+	wl := &StreamWordlistInput{active: true, keyword: keyword, config: conf, delim: commandDelimiter(conf), raw: true, cachedOffset: -1}
+	wl.record = make([]byte, indexRecordSize)
+	wl.cond = sync.NewCond(&wl.mutex)
+	wl.index, err = tempFile("ffuf-index-")
+	if err != nil {
+		return wl, err
+	}
+	wl.source, err = tempFile("ffuf-cmd-")
+	if err != nil {
+		wl.Close()
+		return wl, err
+	}
+	wl.spooled = true
+	cmd := exec.CommandContext(commandContext(conf), commandShell(conf), SHELL_ARG, value)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		wl.Close()
+		return wl, err
+	}
+	// The command is stopped when the job is done, even if it would keep writing payloads
+	stopWithPipes(cmd, stdout)
+	if err := cmd.Start(); err != nil {
+		wl.Close()
+		return wl, fmt.Errorf("Could not start the input command %s: %s", value, err)
+	}
+	go func() {
+		wl.buildIndex(stdout, wl.source)
+		if err := cmd.Wait(); err != nil && commandContext(conf).Err() == nil {
+			log.Printf("Input command %s exited with error: %s", value, err)
+		}
+	}()
+	return wl, nil
+}
+
+// CommandRequestInput runs a long-lived input command that is asked for the payloads one at a time. ffuf writes the
+// position of each payload as a line to the stdin of the command, and the command replies with the payload. Before
+// the first position, ffuf writes a "FFUF_TOTAL" line, and the command replies with the number of payloads, or an empty
+// payload to use -input-num instead.
+type CommandRequestInput struct {
+	active   bool
+	keyword  string
+	command  string
+	position int
+	total    int
+	delim    byte
+	mutex    sync.Mutex
+	stdin    io.WriteCloser
+	stdout   *bufio.Reader
+	err      error
+	cancel   context.CancelFunc
+}
+
+func NewCommandRequestInput(keyword string, value string, conf *ffuf.Config) (*CommandRequestInput, error) {
+	c := &CommandRequestInput{active: true, keyword: keyword, command: value, total: conf.InputNum, delim: commandDelimiter(conf)}
+	ctx, cancel := context.WithCancel(commandContext(conf))
+	cmd := exec.CommandContext(ctx, commandShell(conf), SHELL_ARG, value)
+	var err error
+	c.stdin, err = cmd.StdinPipe()
+	if err != nil {
+		cancel()
+		return c, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		cancel()
+		return c, err
+	}
+	// The command is stopped when the job is done, or when it does not reply to the total request
+	stopWithPipes(cmd, c.stdin, stdout)
+	if err := cmd.Start(); err != nil {
+		cancel()
+		return c, fmt.Errorf("Could not start the input command %s: %s", value, err)
+	}
+	go func() {
+		<-ctx.Done()
+		_ = cmd.Wait()
+	}()
+	c.stdout = bufio.NewReader(stdout)
+	c.cancel = cancel
+	errs := make(chan error, 1)
+	var reply []byte
+	go func() {
+		var err error
+		reply, err = c.request(strings.TrimSuffix(COMMAND_TOTAL_PREFIX, "="))
+		errs <- err
+	}()
+	select {
+	case err = <-errs:
+	case <-time.After(commandTotalTimeout):
+		err = fmt.Errorf("no reply in %s", commandTotalTimeout)
+	}
+	if err != nil {
+		c.cancel()
+		return c, fmt.Errorf("Input command %s did not reply to the total request: %s", value, err)
+	}
+	if len(reply) > 0 {
+		c.total, err = strconv.Atoi(strings.TrimSpace(string(reply)))
+		if err != nil || c.total < 0 {
+			c.cancel()
+			return c, fmt.Errorf("Input command %s replied with an invalid total: %q", value, reply)
+		}
+	}
+	return c, nil
+}
+
+// request writes a line to the command and reads the reply
+func (c *CommandRequestInput) request(line string) ([]byte, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.err != nil {
+		return []byte{}, c.err
+	}
+	if _, err := fmt.Fprintf(c.stdin, "%s\n", line); err != nil {
+		c.err = err
+		return []byte{}, err
+	}
+	reply, err := c.stdout.ReadBytes(c.delim)
+	if err != nil {
+		// A reply without a delimiter at the end of the output is still a reply
+		if err != io.EOF || len(reply) == 0 {
+			c.err = err
+			return []byte{}, err
+		}
+	}
+	reply = bytes.TrimSuffix(reply, []byte{c.delim})
+	if c.delim == '\n' {
+		reply = bytes.TrimSuffix(reply, []byte("\r"))
+	}
+	return reply, nil
+}
+
+// Keyword returns the keyword assigned to this InternalInputProvider
+func (c *CommandRequestInput) Keyword() string {
+	return c.keyword
+}
+
+// Position will return the current position in the input list
+func (c *CommandRequestInput) Position() int {
+	return c.position
+}
+
+// SetPosition will set the current position of the inputprovider
+func (c *CommandRequestInput) SetPosition(pos int) {
+	c.position = pos
+}
+
+// ResetPosition will reset the current position of the InternalInputProvider
+func (c *CommandRequestInput) ResetPosition() {
+	c.position = 0
+}
+
+// IncrementPosition increments the current position in the inputprovider
+func (c *CommandRequestInput) IncrementPosition() {
+	c.position += 1
+}
+
+// Next will increment the cursor position, and return a boolean telling if there's iterations left
+func (c *CommandRequestInput) Next() bool {
+	return c.position < c.total
+}
+
+// Value asks the input command for the payload at the current position
+func (c *CommandRequestInput) Value() []byte {
+	reply, err := c.request(strconv.Itoa(c.position))
+	if err != nil {
+		log.Printf("Could not read the payload at position %d from input command %s: %s", c.position, c.command, err)
+	}
+	return reply
+}
+
+// Total returns the number of payloads
+func (c *CommandRequestInput) Total() int {
+	return c.total
+}
+
+func (c *CommandRequestInput) Active() bool {
+	return c.active
+}
+
+func (c *CommandRequestInput) Enable() {
+	c.active = true
+}
+
+func (c *CommandRequestInput) Disable() {
+	c.active = false
+}
@@ -0,0 +1,151 @@
+package input
+
+import (
+	"context"
+	"runtime"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/ffuf/ffuf/v2/pkg/ffuf"
+)
+
+func TestCommandInput(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("The test commands need a POSIX shell")
+	}
+	c, _ := NewCommandInput("FUZZ", "printf value-$FFUF_NUM", &ffuf.Config{InputNum: 3})
+	c.SetPosition(2)
+	if string(c.Value()) != "value-2" {
+		t.Errorf("Expected the position to be passed to the command, got %q", c.Value())
+	}
+}
+
+func TestCommandStreamInput(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("The test commands need a POSIX shell")
+	}
+	tests := []struct {
+		command  string
+		conf     ffuf.Config
+		expected []string
+	}{
+		{"printf 'one\\ntwo\\r\\nthree'", ffuf.Config{}, []string{"one", "two", "three"}},
+		{"printf 'FFUF_TOTAL=2\\na\\nb\\n'", ffuf.Config{}, []string{"a", "b"}},
+		{"printf 'line\\none\\0two\\0'", ffuf.Config{InputCmdNul: true}, []string{"line\none", "two"}},
+		{"true", ffuf.Config{}, []string{}},
+	}
+	for _, test := range tests {
+		conf := test.conf
+		c, err := NewCommandStreamInput("FUZZ", test.command, &conf)
+		if err != nil {
+			t.Fatalf("Failed to start input command %s: %v", test.command, err)
+		}
+		defer c.Close()
+		c.waitComplete()
+		c.waitForInput(len(test.expected))
+		if c.Total() != len(test.expected) {
+			t.Errorf("Expected %d payloads from %s, got %d", len(test.expected), test.command, c.Total())
+			continue
+		}
+		for i, expected := range test.expected {
+			c.SetPosition(i)
+			if string(c.Value()) != expected {
+				t.Errorf("Expected payload %d of %s to be %q, got %q", i, test.command, expected, c.Value())
+			}
+		}
+	}
+}
+
+func TestCommandRequestInput(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("The test commands need a POSIX shell")
+	}
+	script := `while read l; do if [ "$l" = FFUF_TOTAL ]; then echo 1000000; else echo "value-$l"; fi; done`
+	c, err := NewCommandRequestInput("FUZZ", script, &ffuf.Config{InputNum: 10})
+	if err != nil {
+		t.Fatalf("Failed to start input command: %v", err)
+	}
+	if c.Total() != 1000000 {
+		t.Errorf("Expected the announced total to be used, got %d", c.Total())
+	}
+	for _, pos := range []int{0, 999999, 42} {
+		c.SetPosition(pos)
+		if string(c.Value()) != "value-"+strconv.Itoa(pos) {
+			t.Errorf("Expected the payload of position %d, got %q", pos, c.Value())
+		}
+	}
+
+	// Without an announced total, -input-num is used
+	c, err = NewCommandRequestInput("FUZZ", `while read l; do if [ "$l" = FFUF_TOTAL ]; then echo; else echo "$l"; fi; done`, &ffuf.Config{InputNum: 10})
+	if err != nil {
+		t.Fatalf("Failed to start input command: %v", err)
+	}
+	if c.Total() != 10 {
+		t.Errorf("Expected the total to be -input-num, got %d", c.Total())
+	}
+
+	if _, err := NewCommandRequestInput("FUZZ", "echo abc", &ffuf.Config{InputNum: 10}); err == nil {
+		t.Errorf("Expected an error for an invalid total")
+	}
+}
+
+func TestCommandInputStop(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("The test commands need a POSIX shell")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	s, err := NewCommandStreamInput("FUZZ", "while true; do echo a; done", &ffuf.Config{Context: ctx})
+	if err != nil {
+		t.Fatalf("Failed to start input command: %v", err)
+	}
+	defer s.Close()
+	s.waitForInput(10)
+	r, err := NewCommandRequestInput("FUZZ", `while read l; do if [ "$l" = FFUF_TOTAL ]; then echo; else echo "$l"; fi; done`, &ffuf.Config{Context: ctx, InputNum: 10})
+	if err != nil {
+		t.Fatalf("Failed to start input command: %v", err)
+	}
+	cancel()
+
+	// The endless command stops writing payloads once the job is done
+	done := make(chan struct{})
+	go func() {
+		s.mutex.Lock()
+		for !s.complete {
+			s.cond.Wait()
+		}
+		s.mutex.Unlock()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Errorf("Expected the streaming input command to be stopped with the job")
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if _, err := r.request("1"); err != nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Errorf("Expected the input command in request mode to be stopped with the job")
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestCommandRequestInputTimeout(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("The test commands need a POSIX shell")
+	}
+	defer func(timeout time.Duration) { commandTotalTimeout = timeout }(commandTotalTimeout)
+	commandTotalTimeout = 100 * time.Millisecond
+	start := time.Now()
+	if _, err := NewCommandRequestInput("FUZZ", "cat > /dev/null", &ffuf.Config{InputNum: 10}); err == nil {
+		t.Errorf("Expected an error for an input command that does not reply to the total request")
+	}
+	if time.Since(start) > 5*time.Second {
+		t.Errorf("Expected to give up on the input command after the timeout, took %s", time.Since(start))
+	}
+}
@@ -2,12 +2,19 @@ package input
 
 import (
 	"fmt"
+	"io"
 	"github.com/ffuf/ffuf/v2/pkg/ffuf"
 	"strings"
 
 	"github.com/ffuf/pencode/pkg/pencode"
 )
 
+// streamingInput is implemented by the inputproviders that can still be reading their input while the job is running
+type streamingInput interface {
+	waitForInput(n int)
+	waitComplete()
+}
+
 type MainInputProvider struct {
 	Providers   []ffuf.InternalInputProvider
 	Encoders    map[string]*pencode.Chain
@@ -36,13 +43,51 @@ func NewInputProvider(conf *ffuf.Config) (ffuf.InputProvider, ffuf.Multierror) {
 			errs.Add(err)
 		}
 	}
+	if len(mainip.Providers) > 1 {
+		// Iterating multiple inputproviders requires the sizes of all of them to be known beforehand
+		for _, p := range mainip.Providers {
+			if sp, ok := p.(streamingInput); ok {
+				sp.waitComplete()
+			}
+		}
+	}
 	return &mainip, errs
 }
 
 func (i *MainInputProvider) AddProvider(provider ffuf.InputProviderConfig) error {
-	if provider.Name == "command" {
+	if provider.Name == "command" && i.Config.InputCmdMode == "stream" {
+		newcomm, err := NewCommandStreamInput(provider.Keyword, provider.Value, i.Config)
+		if err != nil {
+			return err
+		}
+		i.Providers = append(i.Providers, newcomm)
+	} else if provider.Name == "command" && i.Config.InputCmdMode == "request" {
+		newcomm, err := NewCommandRequestInput(provider.Keyword, provider.Value, i.Config)
+		if err != nil {
+			return err
+		}
+		i.Providers = append(i.Providers, newcomm)
+	} else if provider.Name == "command" {
 		newcomm, _ := NewCommandInput(provider.Keyword, provider.Value, i.Config)
 		i.Providers = append(i.Providers, newcomm)
+	} else if provider.Name == "range" {
+		newrange, err := NewRangeInput(provider.Keyword, provider.Value, i.Config)
+		if err != nil {
+			return err
+		}
+		i.Providers = append(i.Providers, newrange)
+	} else if provider.Name == "mask" {
+		newmask, err := NewMaskInput(provider.Keyword, provider.Value, i.Config)
+		if err != nil {
+			return err
+		}
+		i.Providers = append(i.Providers, newmask)
+	} else if i.Config.StreamWordlists {
+		newwl, err := NewStreamWordlistInput(provider.Keyword, provider.Value, i.Config)
+		if err != nil {
+			return err
+		}
+		i.Providers = append(i.Providers, newwl)
 	} else {
 		// Default to wordlist
 		newwl, err := NewWordlistInput(provider.Keyword, provider.Value, i.Config)
@@ -51,6 +96,21 @@ func (i *MainInputProvider) AddProvider(provider ffuf.InputProviderConfig) error
 		}
 		i.Providers = append(i.Providers, newwl)
 	}
+	if provider.Rules != "" {
+		rules, err := ReadRules(provider.Rules)
+		if err != nil {
+			return err
+		}
+		i.Providers[len(i.Providers)-1] = NewRuleInput(i.Providers[len(i.Providers)-1], rules)
+	}
+	if provider.Name == "wordlist" && provider.Keyword == "FUZZ" && len(i.Config.Extensions) > 0 && !i.Config.DirSearchCompat {
+		// The extensions are rules appending them to the words, after the word itself
+		rules := []Rule{{text: ":"}}
+		for _, ext := range i.Config.Extensions {
+			rules = append(rules, appendRule(ext))
+		}
+		i.Providers[len(i.Providers)-1] = NewRuleInput(i.Providers[len(i.Providers)-1], rules)
+	}
 	if len(provider.Encoders) > 0 {
 		chain := pencode.NewChain()
 		err := chain.Initialize(strings.Split(strings.TrimSpace(provider.Encoders), " "))
@@ -98,13 +158,24 @@ func (i *MainInputProvider) Keywords() []string {
 
 // Next will increment the cursor position, and return a boolean telling if there's inputs left
 func (i *MainInputProvider) Next() bool {
-	if i.position >= i.Total() {
+	if i.position >= i.Total() && !i.waitForInput() {
 		return false
 	}
 	i.position++
 	return true
 }
 
+// waitForInput waits for streaming inputproviders that are still reading their input, and returns a boolean
+// telling if there are new inputs available
+func (i *MainInputProvider) waitForInput() bool {
+	for _, p := range i.Providers {
+		if sp, ok := p.(streamingInput); ok && p.Active() {
+			sp.waitForInput(i.position)
+		}
+	}
+	return i.position < i.Total()
+}
+
 // Value returns a map of inputs for keywords
 func (i *MainInputProvider) Value() map[string][]byte {
 	retval := make(map[string][]byte)
@@ -158,9 +229,18 @@ func (i *MainInputProvider) pitchforkValue() map[string][]byte {
 }
 
 func (i *MainInputProvider) setpitchforkPosition(pos int) {
+	i.Reset()
+	if pos < 1 || pos > i.Total() {
+		// noop
+		return
+	}
 	for _, p := range i.Providers {
-		p.SetPosition(pos)
+		if p.Total() > 0 {
+			// Shorter inputproviders loop back to beginning
+			p.SetPosition((pos - 1) % p.Total())
+		}
 	}
+	i.position = pos - 1
 }
 
 // clusterbombValue returns map of keyword:value pairs including all inputs.
@@ -230,6 +310,19 @@ func (i *MainInputProvider) clusterbombIteratorReset() {
 }
 
 // Total returns the amount of input combinations available
+// Close closes the inputproviders that hold on to files, like the temporary files of the streamed wordlists
+func (i *MainInputProvider) Close() error {
+	var err error
+	for _, p := range i.Providers {
+		if c, ok := p.(io.Closer); ok {
+			if cerr := c.Close(); err == nil {
+				err = cerr
+			}
+		}
+	}
+	return err
+}
+
 func (i *MainInputProvider) Total() int {
 	count := 0
 	if i.Config.InputMode == "pitchfork" {
@@ -0,0 +1,174 @@
+package input
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/ffuf/ffuf/v2/pkg/ffuf"
+)
+
+// The built-in charsets of the masks, the same as in hashcat
+var maskCharsets = map[byte]string{
+	'l': "abcdefghijklmnopqrstuvwxyz",
+	'u': "ABCDEFGHIJKLMNOPQRSTUVWXYZ",
+	'd': "0123456789",
+	'h': "0123456789abcdef",
+	'H': "0123456789ABCDEF",
+	's': " !\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~",
+}
+
+// MaskInput generates the values of a hashcat style mask, eg. "?u?l?l?d?d" or "token-?h?h?h?h". The values are
+// computed from the position, with the last character of the mask changing the fastest.
+type MaskInput struct {
+	active   bool
+	keyword  string
+	position int
+	charsets [][]byte
+	total    int
+}
+
+func NewMaskInput(keyword string, value string, conf *ffuf.Config) (*MaskInput, error) {
+	m := MaskInput{active: true, keyword: keyword, position: 0, charsets: make([][]byte, 0), total: 1}
+	custom := make([][]byte, 0)
+	for _, cs := range conf.MaskCharsets {
+		// Custom charsets can include the built-in charsets, but not each other
+		chars, err := expandCharset(cs, nil)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid mask charset %s: %s", cs, err)
+		}
+		custom = append(custom, chars)
+	}
+	for i := 0; i < len(value); i++ {
+		chars := []byte{value[i]}
+		if value[i] == '?' {
+			if i+1 >= len(value) {
+				return nil, fmt.Errorf("Invalid mask %s: ends with a lone ?", value)
+			}
+			i++
+			var err error
+			chars, err = maskCharset(value[i], custom)
+			if err != nil {
+				return nil, fmt.Errorf("Invalid mask %s: %s", value, err)
+			}
+		}
+		if m.total > math.MaxInt/len(chars) {
+			return nil, fmt.Errorf("Mask %s has too many values", value)
+		}
+		m.total *= len(chars)
+		m.charsets = append(m.charsets, chars)
+	}
+	if len(m.charsets) == 0 {
+		return nil, fmt.Errorf("Empty mask")
+	}
+	return &m, nil
+}
+
+// maskCharset returns the characters of a charset placeholder, eg. l for ?l or 1 for the first custom charset
+func maskCharset(c byte, custom [][]byte) ([]byte, error) {
+	switch {
+	case c == '?':
+		return []byte{'?'}, nil
+	case c == 'a':
+		return []byte(maskCharsets['l'] + maskCharsets['u'] + maskCharsets['d'] + maskCharsets['s']), nil
+	case c == 'b':
+		chars := make([]byte, 256)
+		for i := range chars {
+			chars[i] = byte(i)
+		}
+		return chars, nil
+	case c >= '1' && c <= '9':
+		if int(c-'1') >= len(custom) {
+			return nil, fmt.Errorf("custom charset ?%c is not defined with -mask-charset", c)
+		}
+		return custom[c-'1'], nil
+	}
+	if chars, ok := maskCharsets[c]; ok {
+		return []byte(chars), nil
+	}
+	return nil, fmt.Errorf("unknown charset ?%c", c)
+}
+
+// expandCharset expands the built-in charsets in a custom charset, and removes the duplicate characters
+func expandCharset(value string, custom [][]byte) ([]byte, error) {
+	chars := make([]byte, 0)
+	seen := make(map[byte]bool)
+	for i := 0; i < len(value); i++ {
+		add := []byte{value[i]}
+		if value[i] == '?' && i+1 < len(value) {
+			i++
+			var err error
+			add, err = maskCharset(value[i], custom)
+			if err != nil {
+				return nil, err
+			}
+		}
+		for _, c := range add {
+			if !seen[c] {
+				seen[c] = true
+				chars = append(chars, c)
+			}
+		}
+	}
+	if len(chars) == 0 {
+		return nil, fmt.Errorf("empty charset")
+	}
+	return chars, nil
+}
+
+// Keyword returns the keyword assigned to this InternalInputProvider
+func (m *MaskInput) Keyword() string {
+	return m.keyword
+}
+
+// Position will return the current position in the input list
+func (m *MaskInput) Position() int {
+	return m.position
+}
+
+// SetPosition will set the current position of the inputprovider
+func (m *MaskInput) SetPosition(pos int) {
+	m.position = pos
+}
+
+// ResetPosition will reset the current position of the InternalInputProvider
+func (m *MaskInput) ResetPosition() {
+	m.position = 0
+}
+
+// IncrementPosition increments the current position in the inputprovider
+func (m *MaskInput) IncrementPosition() {
+	m.position += 1
+}
+
+// Next will increment the cursor position, and return a boolean telling if there's iterations left
+func (m *MaskInput) Next() bool {
+	return m.position < m.total
+}
+
+// Value returns the value of the mask at the current position
+func (m *MaskInput) Value() []byte {
+	val := make([]byte, len(m.charsets))
+	pos := m.position
+	for i := len(m.charsets) - 1; i >= 0; i-- {
+		val[i] = m.charsets[i][pos%len(m.charsets[i])]
+		pos /= len(m.charsets[i])
+	}
+	return val
+}
+
+// Total returns the number of values of the mask
+func (m *MaskInput) Total() int {
+	return m.total
+}
+
+func (m *MaskInput) Active() bool {
+	return m.active
+}
+
+func (m *MaskInput) Enable() {
+	m.active = true
+}
+
+func (m *MaskInput) Disable() {
+	m.active = false
+}
@@ -0,0 +1,47 @@
+package input
+
+import (
+	"testing"
+
+	"github.com/ffuf/ffuf/v2/pkg/ffuf"
+)
+
+func TestMaskInput(t *testing.T) {
+	conf := &ffuf.Config{MaskCharsets: []string{"?dxy", "ab"}}
+	tests := []struct {
+		value    string
+		total    int
+		expected map[int]string
+	}{
+		{"?d?d", 100, map[int]string{0: "00", 7: "07", 42: "42", 99: "99"}},
+		{"id-?l", 26, map[int]string{0: "id-a", 25: "id-z"}},
+		{"?u?l", 676, map[int]string{27: "Bb"}},
+		{"?h?H", 256, map[int]string{255: "fF"}},
+		{"??", 1, map[int]string{0: "?"}},
+		{"?1?2", 24, map[int]string{0: "0a", 1: "0b", 23: "yb"}},
+		{"?a", 95, map[int]string{94: "~"}},
+		{"?b", 256, map[int]string{0: "\x00", 255: "\xff"}},
+	}
+	for _, test := range tests {
+		m, err := NewMaskInput("FUZZ", test.value, conf)
+		if err != nil {
+			t.Errorf("Failed to parse mask %s: %v", test.value, err)
+			continue
+		}
+		if m.Total() != test.total {
+			t.Errorf("Expected mask %s to have %d values, got %d", test.value, test.total, m.Total())
+		}
+		for pos, val := range test.expected {
+			m.SetPosition(pos)
+			if string(m.Value()) != val {
+				t.Errorf("Expected value %q at position %d of mask %s, got %q", val, pos, test.value, m.Value())
+			}
+		}
+	}
+
+	for _, value := range []string{"", "?", "?z", "?3", "?b?b?b?b?b?b?b?b?b"} {
+		if _, err := NewMaskInput("FUZZ", value, conf); err == nil {
+			t.Errorf("Expected an error for mask %s", value)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package input
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/ffuf/ffuf/v2/pkg/ffuf"
+)
+
+// RangeInput generates the numbers of a range, eg. "1-1000", "0001-9999" (zero-padded), "0-1000/5" (with a step) or
+// "0x00-0xff" (hexadecimal). The values are computed from the position, so there is nothing to read or run.
+type RangeInput struct {
+	active   bool
+	keyword  string
+	position int
+	start    uint64
+	step     uint64
+	reverse  bool
+	total    int
+	base     int
+	width    int
+	upper    bool
+}
+
+func NewRangeInput(keyword string, value string, conf *ffuf.Config) (*RangeInput, error) {
+	r := RangeInput{active: true, keyword: keyword, position: 0, step: 1, base: 10}
+	spec, step, hasStep := strings.Cut(value, "/")
+	startStr, endStr, found := strings.Cut(spec, "-")
+	if !found || startStr == "" || endStr == "" {
+		return nil, fmt.Errorf("Invalid range %s, expected START-END[/STEP], eg. 1-1000 or 0x00-0xff/2", value)
+	}
+	if strings.HasPrefix(strings.ToLower(startStr), "0x") {
+		r.base = 16
+		startStr = startStr[2:]
+		if strings.HasPrefix(strings.ToLower(endStr), "0x") {
+			endStr = endStr[2:]
+		}
+	}
+	if len(startStr) > 1 && startStr[0] == '0' {
+		r.width = len(startStr)
+	}
+	r.upper = strings.ToLower(startStr+endStr) != startStr+endStr
+	start, err := strconv.ParseUint(startStr, r.base, 64)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid start of range %s: %s", value, err)
+	}
+	end, err := strconv.ParseUint(endStr, r.base, 64)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid end of range %s: %s", value, err)
+	}
+	if hasStep {
+		r.step, err = strconv.ParseUint(step, 10, 64)
+		if err != nil || r.step == 0 {
+			return nil, fmt.Errorf("Invalid step of range %s, expected a positive number", value)
+		}
+	}
+	r.start = start
+	diff := end - start
+	if end < start {
+		r.reverse = true
+		diff = start - end
+	}
+	if diff/r.step >= math.MaxInt {
+		return nil, fmt.Errorf("Range %s has too many values", value)
+	}
+	r.total = int(diff/r.step) + 1
+	return &r, nil
+}
+
+// Keyword returns the keyword assigned to this InternalInputProvider
+func (r *RangeInput) Keyword() string {
+	return r.keyword
+}
+
+// Position will return the current position in the input list
+func (r *RangeInput) Position() int {
+	return r.position
+}
+
+// SetPosition will set the current position of the inputprovider
+func (r *RangeInput) SetPosition(pos int) {
+	r.position = pos
+}
+
+// ResetPosition will reset the current position of the InternalInputProvider
+func (r *RangeInput) ResetPosition() {
+	r.position = 0
+}
+
+// IncrementPosition increments the current position in the inputprovider
+func (r *RangeInput) IncrementPosition() {
+	r.position += 1
+}
+
+// Next will increment the cursor position, and return a boolean telling if there's iterations left
+func (r *RangeInput) Next() bool {
+	return r.position < r.total
+}
+
+// Value returns the number at the current position
+func (r *RangeInput) Value() []byte {
+	n := r.start + uint64(r.position)*r.step
+	if r.reverse {
+		n = r.start - uint64(r.position)*r.step
+	}
+	val := strconv.FormatUint(n, r.base)
+	if r.upper {
+		val = strings.ToUpper(val)
+	}
+	if len(val) < r.width {
+		val = strings.Repeat("0", r.width-len(val)) + val
+	}
+	return []byte(val)
+}
+
+// Total returns the number of values in the range
+func (r *RangeInput) Total() int {
+	return r.total
+}
+
+func (r *RangeInput) Active() bool {
+	return r.active
+}
+
+func (r *RangeInput) Enable() {
+	r.active = true
+}
+
+func (r *RangeInput) Disable() {
+	r.active = false
+}
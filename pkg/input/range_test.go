@@ -0,0 +1,49 @@
+package input
+
+import (
+	"testing"
+
+	"github.com/ffuf/ffuf/v2/pkg/ffuf"
+)
+
+func TestRangeInput(t *testing.T) {
+	tests := []struct {
+		value    string
+		total    int
+		expected map[int]string
+	}{
+		{"1-1000", 1000, map[int]string{0: "1", 9: "10", 999: "1000"}},
+		{"0001-9999", 9999, map[int]string{0: "0001", 41: "0042", 9998: "9999"}},
+		{"0-100/5", 21, map[int]string{0: "0", 1: "5", 20: "100"}},
+		{"0-9/4", 3, map[int]string{2: "8"}},
+		{"10-1", 10, map[int]string{0: "10", 9: "1"}},
+		{"0x00-0xff", 256, map[int]string{10: "0a", 255: "ff"}},
+		{"0x0-0xFF", 256, map[int]string{10: "A", 255: "FF"}},
+	}
+	for _, test := range tests {
+		r, err := NewRangeInput("FUZZ", test.value, &ffuf.Config{})
+		if err != nil {
+			t.Errorf("Failed to parse range %s: %v", test.value, err)
+			continue
+		}
+		if r.Total() != test.total {
+			t.Errorf("Expected range %s to have %d values, got %d", test.value, test.total, r.Total())
+		}
+		for pos, val := range test.expected {
+			r.SetPosition(pos)
+			if string(r.Value()) != val {
+				t.Errorf("Expected value %s at position %d of range %s, got %s", val, pos, test.value, r.Value())
+			}
+		}
+		r.SetPosition(test.total)
+		if r.Next() {
+			t.Errorf("Expected range %s to end at position %d", test.value, test.total)
+		}
+	}
+
+	for _, value := range []string{"5", "a-b", "1-", "1-10/0", "1-10/x", "0-18446744073709551615"} {
+		if _, err := NewRangeInput("FUZZ", value, &ffuf.Config{}); err == nil {
+			t.Errorf("Expected an error for range %s", value)
+		}
+	}
+}
@@ -0,0 +1,393 @@
+package input
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ffuf/ffuf/v2/pkg/ffuf"
+)
+
+// Rule is a wordlist mutation rule, using a subset of the hashcat and John the Ripper rule syntax
+type Rule struct {
+	text  string
+	funcs []ruleFunc
+}
+
+type ruleFunc func(word []byte) []byte
+
+// ParseRule parses a rule, eg. "c $1 $2 $3" or "sa@ se3 so0". The spaces between the rule functions are ignored.
+func ParseRule(text string) (Rule, error) {
+	r := Rule{text: text, funcs: make([]ruleFunc, 0)}
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		if c == ' ' || c == '\t' {
+			continue
+		}
+		// The number of arguments of the function, N for positions and X for characters
+		args := ""
+		switch c {
+		case 'T', 'p', 'D', '\'', 'z', 'Z':
+			args = "N"
+		case '$', '^', '@':
+			args = "X"
+		case 's':
+			args = "XX"
+		case 'i', 'o':
+			args = "NX"
+		case 'x':
+			args = "NN"
+		}
+		if i+len(args) >= len(text) && len(args) > 0 {
+			return r, fmt.Errorf("missing arguments for rule function %c", c)
+		}
+		argv := make([]int, len(args))
+		chars := make([]byte, len(args))
+		for a, kind := range args {
+			i++
+			chars[a] = text[i]
+			if kind == 'N' {
+				n, ok := rulePosition(text[i])
+				if !ok {
+					return r, fmt.Errorf("invalid position %c for rule function %c", text[i], c)
+				}
+				argv[a] = n
+			}
+		}
+		f, err := ruleFunction(c, argv, chars)
+		if err != nil {
+			return r, err
+		}
+		r.funcs = append(r.funcs, f)
+	}
+	return r, nil
+}
+
+// rulePosition parses a position argument: 0-9 and A-Z for 10-35
+func rulePosition(c byte) (int, bool) {
+	switch {
+	case c >= '0' && c <= '9':
+		return int(c - '0'), true
+	case c >= 'A' && c <= 'Z':
+		return int(c-'A') + 10, true
+	}
+	return 0, false
+}
+
+func ruleFunction(c byte, n []int, x []byte) (ruleFunc, error) {
+	switch c {
+	case ':':
+		return func(w []byte) []byte { return w }, nil
+	case 'l':
+		return bytes.ToLower, nil
+	case 'u':
+		return bytes.ToUpper, nil
+	case 'c':
+		return func(w []byte) []byte {
+			w = bytes.ToLower(w)
+			if len(w) > 0 {
+				w[0] = toUpper(w[0])
+			}
+			return w
+		}, nil
+	case 'C':
+		return func(w []byte) []byte {
+			w = bytes.ToUpper(w)
+			if len(w) > 0 {
+				w[0] = toLower(w[0])
+			}
+			return w
+		}, nil
+	case 't':
+		return func(w []byte) []byte {
+			for i := range w {
+				w[i] = toggleCase(w[i])
+			}
+			return w
+		}, nil
+	case 'T':
+		return func(w []byte) []byte {
+			if n[0] < len(w) {
+				w[n[0]] = toggleCase(w[n[0]])
+			}
+			return w
+		}, nil
+	case 'r':
+		return func(w []byte) []byte {
+			for i, j := 0, len(w)-1; i < j; i, j = i+1, j-1 {
+				w[i], w[j] = w[j], w[i]
+			}
+			return w
+		}, nil
+	case 'd':
+		return func(w []byte) []byte { return append(w, w...) }, nil
+	case 'p':
+		return func(w []byte) []byte { return bytes.Repeat(w, n[0]+1) }, nil
+	case 'f':
+		return func(w []byte) []byte {
+			for i := len(w) - 1; i >= 0; i-- {
+				w = append(w, w[i])
+			}
+			return w
+		}, nil
+	case '{':
+		return func(w []byte) []byte {
+			if len(w) > 0 {
+				w = append(w[1:], w[0])
+			}
+			return w
+		}, nil
+	case '}':
+		return func(w []byte) []byte {
+			if len(w) > 0 {
+				w = append([]byte{w[len(w)-1]}, w[:len(w)-1]...)
+			}
+			return w
+		}, nil
+	case '$':
+		return func(w []byte) []byte { return append(w, x[0]) }, nil
+	case '^':
+		return func(w []byte) []byte { return append([]byte{x[0]}, w...) }, nil
+	case '[':
+		return func(w []byte) []byte {
+			if len(w) > 0 {
+				w = w[1:]
+			}
+			return w
+		}, nil
+	case ']':
+		return func(w []byte) []byte {
+			if len(w) > 0 {
+				w = w[:len(w)-1]
+			}
+			return w
+		}, nil
+	case 'D':
+		return func(w []byte) []byte {
+			if n[0] < len(w) {
+				w = append(w[:n[0]], w[n[0]+1:]...)
+			}
+			return w
+		}, nil
+	case '\'':
+		return func(w []byte) []byte {
+			if n[0] < len(w) {
+				w = w[:n[0]]
+			}
+			return w
+		}, nil
+	case 'x':
+		return func(w []byte) []byte {
+			if n[0] >= len(w) {
+				return w[:0]
+			}
+			end := n[0] + n[1]
+			if end > len(w) {
+				end = len(w)
+			}
+			return w[n[0]:end]
+		}, nil
+	case 'i':
+		return func(w []byte) []byte {
+			if n[0] <= len(w) {
+				w = append(w[:n[0]], append([]byte{x[1]}, w[n[0]:]...)...)
+			}
+			return w
+		}, nil
+	case 'o':
+		return func(w []byte) []byte {
+			if n[0] < len(w) {
+				w[n[0]] = x[1]
+			}
+			return w
+		}, nil
+	case 's':
+		return func(w []byte) []byte { return bytes.ReplaceAll(w, x[0:1], x[1:2]) }, nil
+	case '@':
+		return func(w []byte) []byte { return bytes.ReplaceAll(w, x[0:1], []byte{}) }, nil
+	case 'z':
+		return func(w []byte) []byte {
+			if len(w) > 0 {
+				w = append(bytes.Repeat(w[0:1], n[0]), w...)
+			}
+			return w
+		}, nil
+	case 'Z':
+		return func(w []byte) []byte {
+			if len(w) > 0 {
+				w = append(w, bytes.Repeat(w[len(w)-1:], n[0])...)
+			}
+			return w
+		}, nil
+	case 'q':
+		return func(w []byte) []byte {
+			out := make([]byte, 0, len(w)*2)
+			for _, b := range w {
+				out = append(out, b, b)
+			}
+			return out
+		}, nil
+	}
+	return nil, fmt.Errorf("unsupported rule function %c", c)
+}
+
+func toUpper(c byte) byte {
+	if c >= 'a' && c <= 'z' {
+		return c - 32
+	}
+	return c
+}
+
+func toLower(c byte) byte {
+	if c >= 'A' && c <= 'Z' {
+		return c + 32
+	}
+	return c
+}
+
+func toggleCase(c byte) byte {
+	if c >= 'a' && c <= 'z' {
+		return c - 32
+	}
+	return toLower(c)
+}
+
+// appendRule creates a rule appending a string to the word, used for the extensions (-e)
+func appendRule(suffix string) Rule {
+	return Rule{text: "append " + suffix, funcs: []ruleFunc{func(w []byte) []byte { return append(w, suffix...) }}}
+}
+
+// Apply returns the word mutated by the rule
+func (r Rule) Apply(word []byte) []byte {
+	w := append([]byte{}, word...)
+	for _, f := range r.funcs {
+		w = f(w)
+	}
+	return w
+}
+
+// String returns the rule as it was written
+func (r Rule) String() string {
+	return r.text
+}
+
+// ReadRules reads a rules file, one rule per line. Empty lines and lines starting with # are skipped.
+func ReadRules(filename string) ([]Rule, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	rules := make([]Rule, 0)
+	scanner := bufio.NewScanner(f)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		rule, err := ParseRule(line)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid rule on line %d of %s: %s", lineNum, filename, err)
+		}
+		rules = append(rules, rule)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(rules) == 0 {
+		return nil, fmt.Errorf("No rules in %s", filename)
+	}
+	return rules, nil
+}
+
+// RuleInput applies mutation rules to the values of another inputprovider. Each value produces one value per rule,
+// so the total is the total of the inputprovider multiplied by the number of rules. The rules are applied lazily when
+// the value at the position is requested.
+type RuleInput struct {
+	provider    ffuf.InternalInputProvider
+	rules       []Rule
+	position    int
+	cachedPos   int
+	cachedValue []byte
+}
+
+func NewRuleInput(provider ffuf.InternalInputProvider, rules []Rule) *RuleInput {
+	return &RuleInput{provider: provider, rules: rules, position: 0, cachedPos: -1}
+}
+
+// Keyword returns the keyword assigned to this InternalInputProvider
+func (r *RuleInput) Keyword() string {
+	return r.provider.Keyword()
+}
+
+// Position will return the current position in the input list
+func (r *RuleInput) Position() int {
+	return r.position
+}
+
+// SetPosition will set the current position of the inputprovider
+func (r *RuleInput) SetPosition(pos int) {
+	r.position = pos
+}
+
+// ResetPosition will reset the current position of the InternalInputProvider
+func (r *RuleInput) ResetPosition() {
+	r.position = 0
+}
+
+// IncrementPosition increments the current position in the inputprovider
+func (r *RuleInput) IncrementPosition() {
+	r.position += 1
+}
+
+// Next will increment the cursor position, and return a boolean telling if there's iterations left
+func (r *RuleInput) Next() bool {
+	return r.position < r.Total()
+}
+
+// Value returns the value of the inputprovider mutated by the rule at the current position
+func (r *RuleInput) Value() []byte {
+	pos := r.position / len(r.rules)
+	if pos != r.cachedPos {
+		// The values of the inputprovider are read once for all of the rules
+		r.provider.SetPosition(pos)
+		r.cachedValue = r.provider.Value()
+		r.cachedPos = pos
+	}
+	return r.rules[r.position%len(r.rules)].Apply(r.cachedValue)
+}
+
+// Total returns the number of values after the rules are applied
+func (r *RuleInput) Total() int {
+	return r.provider.Total() * len(r.rules)
+}
+
+func (r *RuleInput) Active() bool {
+	return r.provider.Active()
+}
+
+func (r *RuleInput) Enable() {
+	r.provider.Enable()
+}
+
+func (r *RuleInput) Disable() {
+	r.provider.Disable()
+}
+
+// waitForInput waits for a streaming inputprovider to read the value of position n
+func (r *RuleInput) waitForInput(n int) {
+	if sp, ok := r.provider.(streamingInput); ok {
+		sp.waitForInput(n / len(r.rules))
+	}
+}
+
+// waitComplete waits for a streaming inputprovider to read all of its input
+func (r *RuleInput) waitComplete() {
+	if sp, ok := r.provider.(streamingInput); ok {
+		sp.waitComplete()
+	}
+}
@@ -0,0 +1,104 @@
+package input
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ffuf/ffuf/v2/pkg/ffuf"
+)
+
+func TestParseRule(t *testing.T) {
+	tests := []struct {
+		rule     string
+		word     string
+		expected string
+	}{
+		{":", "Password", "Password"},
+		{"l", "PassWord", "password"},
+		{"u", "PassWord", "PASSWORD"},
+		{"c", "passWord", "Password"},
+		{"C", "passWord", "pASSWORD"},
+		{"t", "PassWord", "pASSwORD"},
+		{"T0 T4", "password", "PassWord"},
+		{"r", "password", "drowssap"},
+		{"d", "pass", "passpass"},
+		{"p2", "ab", "ababab"},
+		{"f", "abc", "abccba"},
+		{"{", "abc", "bca"},
+		{"}", "abc", "cab"},
+		{"$1 $2 $3", "admin", "admin123"},
+		{"^2^0", "admin", "02admin"},
+		{"[", "admin", "dmin"},
+		{"]", "admin", "admi"},
+		{"D1", "admin", "amin"},
+		{"'3", "admin", "adm"},
+		{"x12", "password", "as"},
+		{"i3-", "admin", "adm-in"},
+		{"o0A", "admin", "Admin"},
+		{"sa4 se3 so0", "password", "p4ssw0rd"},
+		{"@s", "password", "paword"},
+		{"z2", "ab", "aaab"},
+		{"Z2", "ab", "abbb"},
+		{"q", "ab", "aabb"},
+		{"c $!", "admin", "Admin!"},
+		{"$ ", "admin", "admin "},
+		{"TA", "short", "short"},
+		{"]]]]]]", "abc", ""},
+	}
+	for _, test := range tests {
+		r, err := ParseRule(test.rule)
+		if err != nil {
+			t.Errorf("Failed to parse rule %q: %v", test.rule, err)
+			continue
+		}
+		if res := string(r.Apply([]byte(test.word))); res != test.expected {
+			t.Errorf("Expected rule %q to turn %q to %q, got %q", test.rule, test.word, test.expected, res)
+		}
+	}
+
+	for _, rule := range []string{"Q", "$", "sa", "Ta", "i1", "x1"} {
+		if _, err := ParseRule(rule); err == nil {
+			t.Errorf("Expected an error for rule %q", rule)
+		}
+	}
+}
+
+func TestRuleInput(t *testing.T) {
+	tmpDir := t.TempDir()
+	wordlist := filepath.Join(tmpDir, "wordlist.txt")
+	rules := filepath.Join(tmpDir, "rules.txt")
+	_ = os.WriteFile(wordlist, []byte("admin\nlogin\n"), 0644)
+	_ = os.WriteFile(rules, []byte("# keep the word\n:\n\nu\n$1\n"), 0644)
+
+	for _, stream := range []bool{false, true} {
+		conf := &ffuf.Config{InputMode: "clusterbomb", StreamWordlists: stream, Extensions: []string{".php"}}
+		conf.InputProviders = []ffuf.InputProviderConfig{{Name: "wordlist", Keyword: "FUZZ", Value: wordlist, Rules: rules}}
+		ip, errs := NewInputProvider(conf)
+		if errs.ErrorOrNil() != nil {
+			t.Fatalf("Failed to create the input provider: %v", errs.ErrorOrNil())
+		}
+		expected := []string{"admin", "admin.php", "ADMIN", "ADMIN.php", "admin1", "admin1.php", "login", "login.php", "LOGIN", "LOGIN.php", "login1", "login1.php"}
+		if ip.Total() != len(expected) {
+			t.Errorf("Expected %d inputs, got %d", len(expected), ip.Total())
+		}
+		values := make([]string, 0)
+		for ip.Next() {
+			values = append(values, string(ip.Value()["FUZZ"]))
+		}
+		if strings.Join(values, " ") != strings.Join(expected, " ") {
+			t.Errorf("Expected inputs %v, got %v", expected, values)
+		}
+		// Random access
+		ip.SetPosition(9)
+		if v := string(ip.Value()["FUZZ"]); v != "LOGIN" {
+			t.Errorf("Expected input LOGIN at position 9, got %s", v)
+		}
+	}
+
+	_ = os.WriteFile(rules, []byte("# no rules\n"), 0644)
+	if _, err := ReadRules(rules); err == nil {
+		t.Errorf("Expected an error for a rules file without rules")
+	}
+}
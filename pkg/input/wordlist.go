@@ -158,11 +158,6 @@ func (w *WordlistInput) readFile(path string) error {
 				}
 			}
 			data = append(data, []byte(text))
-			if w.keyword == "FUZZ" && len(w.config.Extensions) > 0 {
-				for _, ext := range w.config.Extensions {
-					data = append(data, []byte(text+ext))
-				}
-			}
 		}
 	}
 	w.data = data
@@ -0,0 +1,380 @@
+package input
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"log"
+	"math"
+	"os"
+	"regexp"
+	"sync"
+
+	"github.com/ffuf/ffuf/v2/pkg/ffuf"
+)
+
+// size of a single index record: line offset (int64) + entry variant (uint32)
+const indexRecordSize = 12
+
+var extRegexp = regexp.MustCompile(`(?i)%ext%`)
+
+// StreamWordlistInput is a wordlist inputprovider that reads the entries lazily from disk instead of
+// loading the whole wordlist to memory. The entries are located using an on-disk index of line offsets.
+// It also reads the output of input commands run in the stream mode, see NewCommandStreamInput.
+type StreamWordlistInput struct {
+	active       bool
+	config       *ffuf.Config
+	keyword      string
+	position     int
+	source       *os.File
+	spooled      bool
+	index        *os.File
+	closed       bool
+	total        int
+	announced    int
+	complete     bool
+	delim        byte
+	raw          bool
+	err          error
+	mutex        sync.Mutex
+	cond         *sync.Cond
+	record       []byte
+	reader       *bufio.Reader
+	readerOffset int64
+	cachedOffset int64
+	cachedLine   []byte
+}
+
+func NewStreamWordlistInput(keyword string, value string, conf *ffuf.Config) (*StreamWordlistInput, error) {
+	var wl StreamWordlistInput
+	var err error
+	wl.active = true
+	wl.keyword = keyword
+	wl.config = conf
+	wl.position = 0
+	wl.delim = '\n'
+	wl.cachedOffset = -1
+	wl.record = make([]byte, indexRecordSize)
+	wl.cond = sync.NewCond(&wl.mutex)
+
+	wl.index, err = tempFile("ffuf-index-")
+	if err != nil {
+		return &wl, err
+	}
+	if value == "-" {
+		// Spool stdin to disk in the background, the job can start before we reach EOF
+		wl.source, err = tempFile("ffuf-stdin-")
+		if err != nil {
+			wl.Close()
+			return &wl, err
+		}
+		wl.spooled = true
+		go wl.buildIndex(os.Stdin, wl.source)
+		return &wl, nil
+	}
+	wl.source, err = os.Open(value)
+	if err != nil {
+		wl.Close()
+		return &wl, err
+	}
+	wl.buildIndex(wl.source, nil)
+	if wl.err != nil {
+		wl.Close()
+	}
+	return &wl, wl.err
+}
+
+// tempFile creates a temporary file for the index or the spooled input. It is removed when the inputprovider is
+// closed.
+func tempFile(pattern string) (*os.File, error) {
+	return os.CreateTemp("", pattern)
+}
+
+// Close closes the wordlist, and removes the index and the spooled input from the temporary directory. The files
+// are closed before they are removed, as open files can not be removed on Windows.
+func (w *StreamWordlistInput) Close() error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	var err error
+	if w.index != nil {
+		err = closeTemp(w.index, true)
+	}
+	if w.source != nil {
+		if serr := closeTemp(w.source, w.spooled); err == nil {
+			err = serr
+		}
+	}
+	return err
+}
+
+func closeTemp(f *os.File, remove bool) error {
+	err := f.Close()
+	if remove {
+		if rerr := os.Remove(f.Name()); err == nil {
+			err = rerr
+		}
+	}
+	return err
+}
+
+// buildIndex reads the wordlist line by line and writes an index record for every entry.
+// If spool is set, the data read is also written to it to be able to read the entries later.
+func (w *StreamWordlistInput) buildIndex(src io.Reader, spool io.Writer) {
+	var spoolWriter *bufio.Writer
+	if spool != nil {
+		spoolWriter = bufio.NewWriter(spool)
+	}
+	indexWriter := bufio.NewWriter(w.index)
+	reader := bufio.NewReaderSize(src, 65536)
+	record := make([]byte, indexRecordSize)
+	offset := int64(0)
+	count := 0
+	for {
+		line, err := reader.ReadBytes(w.delim)
+		if len(line) > 0 && offset == 0 && w.raw {
+			// The input command can announce the number of payloads before writing them
+			if total, ok := announcedTotal(w.trim(line)); ok {
+				if spoolWriter != nil {
+					_, _ = spoolWriter.Write(line)
+				}
+				offset += int64(len(line))
+				w.mutex.Lock()
+				w.announced = total
+				w.mutex.Unlock()
+				w.cond.Broadcast()
+				line = nil
+			}
+		}
+		if len(line) > 0 {
+			if spoolWriter != nil {
+				_, _ = spoolWriter.Write(line)
+			}
+			variants := w.lineVariants(w.trim(line))
+			for v := 0; v < variants; v++ {
+				binary.LittleEndian.PutUint64(record[0:8], uint64(offset))
+				binary.LittleEndian.PutUint32(record[8:12], uint32(v))
+				_, _ = indexWriter.Write(record)
+			}
+			count += variants
+			offset += int64(len(line))
+		}
+		if err != nil {
+			if err != io.EOF {
+				w.setError(err)
+			}
+			break
+		}
+		if spoolWriter != nil && reader.Buffered() == 0 {
+			// The next read might block waiting for more input, publish the entries read so far
+			w.publish(spoolWriter, indexWriter, count, false)
+		}
+	}
+	w.publish(spoolWriter, indexWriter, count, true)
+}
+
+// publish flushes the written data to disk and makes the new entries available
+func (w *StreamWordlistInput) publish(spoolWriter *bufio.Writer, indexWriter *bufio.Writer, total int, complete bool) {
+	if spoolWriter != nil {
+		if err := spoolWriter.Flush(); err != nil {
+			w.setError(err)
+		}
+	}
+	if err := indexWriter.Flush(); err != nil {
+		w.setError(err)
+	}
+	w.mutex.Lock()
+	w.total = total
+	w.complete = complete
+	w.mutex.Unlock()
+	w.cond.Broadcast()
+}
+
+func (w *StreamWordlistInput) setError(err error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if w.err == nil {
+		w.err = err
+	}
+}
+
+// waitForInput blocks until the wordlist has more than n entries or the whole input has been read
+func (w *StreamWordlistInput) waitForInput(n int) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	for w.total <= n && !w.complete {
+		w.cond.Wait()
+	}
+}
+
+// waitComplete blocks until the whole input has been read, or the input command has announced the number of payloads
+func (w *StreamWordlistInput) waitComplete() {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	for !w.complete && w.announced == 0 {
+		w.cond.Wait()
+	}
+}
+
+// lineVariants returns the number of entries a wordlist line expands to, zero meaning that the line is skipped
+func (w *StreamWordlistInput) lineVariants(line []byte) int {
+	if w.raw {
+		return 1
+	}
+	if w.config.DirSearchCompat && len(w.config.Extensions) > 0 {
+		if extRegexp.Match(line) {
+			return len(w.config.Extensions)
+		}
+	}
+	if w.config.IgnoreWordlistComments {
+		if _, ok := stripComments(string(line)); !ok {
+			return 0
+		}
+	}
+	return 1
+}
+
+// entry returns the wordlist entry for a line and its variant number
+func (w *StreamWordlistInput) entry(line []byte, variant int) []byte {
+	if w.raw {
+		return append([]byte{}, line...)
+	}
+	if w.config.DirSearchCompat && len(w.config.Extensions) > 0 {
+		if extRegexp.Match(line) {
+			return extRegexp.ReplaceAll(line, []byte(w.config.Extensions[variant]))
+		}
+	}
+	text := string(line)
+	if w.config.IgnoreWordlistComments {
+		text, _ = stripComments(text)
+	}
+	return []byte(text)
+}
+
+// readLine returns the line starting from offset in the wordlist data
+func (w *StreamWordlistInput) readLine(offset int64) ([]byte, error) {
+	if offset == w.cachedOffset {
+		return w.cachedLine, nil
+	}
+	if w.reader == nil || offset != w.readerOffset {
+		w.reader = bufio.NewReader(&growingFileReader{io.NewSectionReader(w.source, offset, math.MaxInt64-offset)})
+		w.readerOffset = offset
+	}
+	line, err := w.reader.ReadBytes(w.delim)
+	if err != nil && err != io.EOF {
+		w.reader = nil
+		return []byte{}, err
+	}
+	w.readerOffset += int64(len(line))
+	w.cachedOffset = offset
+	w.cachedLine = w.trim(line)
+	return w.cachedLine, nil
+}
+
+// growingFileReader only returns io.EOF from reads that return no data. This keeps bufio.Reader from
+// holding on to an EOF while the spool file is still being written to.
+type growingFileReader struct {
+	r io.Reader
+}
+
+func (g *growingFileReader) Read(p []byte) (int, error) {
+	n, err := g.r.Read(p)
+	if n > 0 && err == io.EOF {
+		err = nil
+	}
+	return n, err
+}
+
+// trim removes the delimiter from the end of an entry
+func (w *StreamWordlistInput) trim(line []byte) []byte {
+	if w.delim != '\n' {
+		return bytes.TrimSuffix(line, []byte{w.delim})
+	}
+	return trimLine(line)
+}
+
+// trimLine removes the line ending
+func trimLine(line []byte) []byte {
+	line = bytes.TrimSuffix(line, []byte("\n"))
+	return bytes.TrimSuffix(line, []byte("\r"))
+}
+
+// Position will return the current position in the input list
+func (w *StreamWordlistInput) Position() int {
+	return w.position
+}
+
+// SetPosition sets the current position of the inputprovider
+func (w *StreamWordlistInput) SetPosition(pos int) {
+	w.position = pos
+}
+
+// ResetPosition resets the position back to beginning of the wordlist.
+func (w *StreamWordlistInput) ResetPosition() {
+	w.position = 0
+}
+
+// Keyword returns the keyword assigned to this InternalInputProvider
+func (w *StreamWordlistInput) Keyword() string {
+	return w.keyword
+}
+
+// Next will return a boolean telling if there's words left in the list
+func (w *StreamWordlistInput) Next() bool {
+	return w.position < w.Total()
+}
+
+// IncrementPosition will increment the current position in the inputprovider
+func (w *StreamWordlistInput) IncrementPosition() {
+	w.position += 1
+}
+
+// Value returns the value from wordlist at current cursor position
+func (w *StreamWordlistInput) Value() []byte {
+	if w.raw {
+		// The announced total can be ahead of the payloads read so far
+		w.waitForInput(w.position)
+	}
+	_, err := w.index.ReadAt(w.record, int64(w.position)*indexRecordSize)
+	if err != nil {
+		log.Printf("Could not read wordlist index at position %d: %s", w.position, err)
+		return []byte("")
+	}
+	offset := int64(binary.LittleEndian.Uint64(w.record[0:8]))
+	variant := int(binary.LittleEndian.Uint32(w.record[8:12]))
+	line, err := w.readLine(offset)
+	if err != nil {
+		log.Printf("Could not read wordlist entry at position %d: %s", w.position, err)
+		return []byte("")
+	}
+	return w.entry(line, variant)
+}
+
+// Total returns the size of wordlist
+func (w *StreamWordlistInput) Total() int {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if !w.complete && w.announced > w.total {
+		return w.announced
+	}
+	return w.total
+}
+
+// Active returns boolean if the inputprovider is active
+func (w *StreamWordlistInput) Active() bool {
+	return w.active
+}
+
+// Enable sets the inputprovider as active
+func (w *StreamWordlistInput) Enable() {
+	w.active = true
+}
+
+// Disable disables the inputprovider
+func (w *StreamWordlistInput) Disable() {
+	w.active = false
+}
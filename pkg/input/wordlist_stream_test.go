@@ -0,0 +1,87 @@
+package input
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/ffuf/ffuf/v2/pkg/ffuf"
+)
+
+func TestStreamWordlistMatchesWordlist(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "ffuf-test")
+	if err != nil {
+		t.Fatalf("Failed to create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+	wordlist := filepath.Join(tmpDir, "wordlist.txt")
+	err = os.WriteFile(wordlist, []byte("admin\r\n# comment\nindex.%EXT%\n\nbackup # old\nlast"), 0644)
+	if err != nil {
+		t.Fatalf("Failed to write wordlist: %v", err)
+	}
+
+	for _, conf := range []ffuf.Config{
+		{},
+		{IgnoreWordlistComments: true},
+		{Extensions: []string{".php", ".bak"}},
+		{Extensions: []string{".php", ".bak"}, DirSearchCompat: true, IgnoreWordlistComments: true},
+	} {
+		conf := conf
+		wl, err := NewWordlistInput("FUZZ", wordlist, &conf)
+		if err != nil {
+			t.Fatalf("Failed to read wordlist: %v", err)
+		}
+		swl, err := NewStreamWordlistInput("FUZZ", wordlist, &conf)
+		if err != nil {
+			t.Fatalf("Failed to read stream wordlist: %v", err)
+		}
+		defer swl.Close()
+		if wl.Total() != swl.Total() {
+			t.Fatalf("Expected stream wordlist to have %d entries, got %d", wl.Total(), swl.Total())
+		}
+		for wl.Next() {
+			if string(wl.Value()) != string(swl.Value()) {
+				t.Errorf("Expected entry %d to be %q, got %q", wl.Position(), wl.Value(), swl.Value())
+			}
+			wl.IncrementPosition()
+			swl.IncrementPosition()
+		}
+		// Random access
+		swl.SetPosition(1)
+		if string(swl.Value()) != string(wl.data[1]) {
+			t.Errorf("Expected entry 1 to be %q after SetPosition, got %q", wl.data[1], swl.Value())
+		}
+	}
+}
+
+func TestStreamWordlistClose(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("TMPDIR", tmpDir)
+	t.Setenv("TMP", tmpDir)
+	wordlist := filepath.Join(tmpDir, "wordlist.txt")
+	if err := os.WriteFile(wordlist, []byte("admin\nlogin\n"), 0644); err != nil {
+		t.Fatalf("Failed to write wordlist: %v", err)
+	}
+	swl, err := NewStreamWordlistInput("FUZZ", wordlist, &ffuf.Config{})
+	if err != nil {
+		t.Fatalf("Failed to read stream wordlist: %v", err)
+	}
+	if runtime.GOOS != "windows" {
+		cmd, err := NewCommandStreamInput("FUZZ", "printf 'a\\nb\\n'", &ffuf.Config{})
+		if err != nil {
+			t.Fatalf("Failed to start input command: %v", err)
+		}
+		cmd.waitComplete()
+		if err := cmd.Close(); err != nil {
+			t.Errorf("Failed to close the input command: %v", err)
+		}
+	}
+	if err := swl.Close(); err != nil {
+		t.Errorf("Failed to close the stream wordlist: %v", err)
+	}
+	entries, _ := os.ReadDir(tmpDir)
+	if len(entries) != 1 || entries[0].Name() != "wordlist.txt" {
+		t.Errorf("Expected the temporary files to be removed and the wordlist to be kept, got %v", entries)
+	}
+}
@@ -0,0 +1,141 @@
+package output
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/xml"
+	"net/url"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ffuf/ffuf/v2/pkg/ffuf"
+)
+
+// The items format of Burp Suite, as written by "Save items" and read by the site map import
+type burpItems struct {
+	XMLName     xml.Name   `xml:"items"`
+	BurpVersion string     `xml:"burpVersion,attr"`
+	ExportTime  string     `xml:"exportTime,attr"`
+	Items       []burpItem `xml:"item"`
+}
+
+type burpItem struct {
+	Time           string   `xml:"time"`
+	Url            burpData `xml:"url"`
+	Host           burpHost `xml:"host"`
+	Port           int      `xml:"port"`
+	Protocol       string   `xml:"protocol"`
+	Method         burpData `xml:"method"`
+	Path           burpData `xml:"path"`
+	Extension      string   `xml:"extension"`
+	Request        burpData `xml:"request"`
+	Status         int64    `xml:"status"`
+	ResponseLength int64    `xml:"responselength"`
+	MimeType       string   `xml:"mimetype"`
+	Response       burpData `xml:"response"`
+	Comment        string   `xml:"comment"`
+}
+
+type burpHost struct {
+	IP   string `xml:"ip,attr"`
+	Name string `xml:",chardata"`
+}
+
+type burpData struct {
+	Base64 string `xml:"base64,attr,omitempty"`
+	Value  string `xml:",cdata"`
+}
+
+// writeBurp writes the results as Burp Suite items. The requests and responses are included if they were saved to
+// the output directory (-od).
+func writeBurp(filename string, config *ffuf.Config, res []ffuf.Result) error {
+	now := time.Now().Format(time.UnixDate)
+	out := burpItems{BurpVersion: "ffuf " + ffuf.Version(), ExportTime: now, Items: make([]burpItem, 0, len(res))}
+	for _, r := range res {
+		out.Items = append(out.Items, toBurp(r, config, now))
+	}
+	outXML, err := xml.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, append([]byte(xml.Header), outXML...), 0644)
+}
+
+func toBurp(r ffuf.Result, config *ffuf.Config, now string) burpItem {
+	item := burpItem{
+		Time:           now,
+		Url:            burpData{Value: r.Url},
+		Host:           burpHost{Name: r.Host},
+		Method:         burpData{Value: config.Method},
+		Extension:      "null",
+		Request:        burpData{Base64: "true"},
+		Status:         r.StatusCode,
+		ResponseLength: r.ContentLength,
+		MimeType:       burpMimeType(r.ContentType),
+		Response:       burpData{Base64: "true"},
+		Comment:        "ffuf",
+	}
+	if u, err := url.Parse(r.Url); err == nil {
+		item.Host.Name = u.Hostname()
+		item.Protocol = u.Scheme
+		item.Port, _ = strconv.Atoi(u.Port())
+		if item.Port == 0 {
+			item.Port = 80
+			if u.Scheme == "https" {
+				item.Port = 443
+			}
+		}
+		item.Path.Value = u.RequestURI()
+		if ext := path.Ext(u.Path); ext != "" {
+			item.Extension = strings.TrimPrefix(ext, ".")
+		}
+	}
+	if req, resp, ok := readResultFile(config.OutputDirectory, r.ResultFile); ok {
+		if method, _, found := bytes.Cut(req, []byte(" ")); found {
+			item.Method.Value = string(method)
+		}
+		item.Request.Value = base64.StdEncoding.EncodeToString(req)
+		item.Response.Value = base64.StdEncoding.EncodeToString(resp)
+		item.ResponseLength = int64(len(resp))
+	}
+	return item
+}
+
+// readResultFile reads the request and the response of a result from the output directory
+func readResultFile(dir, name string) ([]byte, []byte, bool) {
+	if dir == "" || name == "" {
+		return nil, nil, false
+	}
+	content, err := os.ReadFile(path.Join(dir, name))
+	if err != nil {
+		return nil, nil, false
+	}
+	return bytes.Cut(content, []byte(RESULTFILE_SEPARATOR))
+}
+
+// burpMimeType maps a content type to the mime type names of Burp Suite
+func burpMimeType(contentType string) string {
+	ct := strings.ToLower(contentType)
+	switch {
+	case ct == "":
+		return ""
+	case strings.Contains(ct, "html"):
+		return "HTML"
+	case strings.Contains(ct, "json"):
+		return "JSON"
+	case strings.Contains(ct, "xml"):
+		return "XML"
+	case strings.Contains(ct, "javascript"):
+		return "script"
+	case strings.Contains(ct, "css"):
+		return "CSS"
+	case strings.HasPrefix(ct, "image/"):
+		return "image"
+	case strings.HasPrefix(ct, "text/"):
+		return "text"
+	}
+	return "app"
+}
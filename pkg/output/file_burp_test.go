@@ -0,0 +1,53 @@
+package output
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ffuf/ffuf/v2/pkg/ffuf"
+)
+
+func TestWriteBurp(t *testing.T) {
+	conf := ffuf.NewConfig(context.Background(), func() {})
+	conf.OutputDirectory = t.TempDir()
+	rawReq := "POST /admin.php?id=1 HTTP/1.1\r\nHost: example.com:8443\r\n\r\n"
+	rawResp := "HTTP/1.1 200 OK\r\nContent-Type: text/html\r\n\r\n<html></html>"
+	if err := os.WriteFile(filepath.Join(conf.OutputDirectory, "abc"), []byte(rawReq+RESULTFILE_SEPARATOR+rawResp), 0644); err != nil {
+		t.Fatalf("Failed to write the result file: %v", err)
+	}
+	res := []ffuf.Result{
+		{StatusCode: 200, Url: "https://example.com:8443/admin.php?id=1", ContentType: "text/html", ResultFile: "abc"},
+		{StatusCode: 404, Url: "http://example.com/missing"},
+	}
+	filename := filepath.Join(t.TempDir(), "results.xml")
+	if err := writeBurp(filename, &conf, res); err != nil {
+		t.Fatalf("writeBurp failed: %v", err)
+	}
+	data, _ := os.ReadFile(filename)
+	var items burpItems
+	if err := xml.Unmarshal(data, &items); err != nil {
+		t.Fatalf("Failed to parse the Burp items file: %v", err)
+	}
+	if len(items.Items) != 2 {
+		t.Fatalf("Expected two items, got %s", data)
+	}
+	item := items.Items[0]
+	if item.Host.Name != "example.com" || item.Port != 8443 || item.Protocol != "https" || item.Path.Value != "/admin.php?id=1" || item.Extension != "php" {
+		t.Errorf("Expected the location of the result, got %+v", item)
+	}
+	if item.Method.Value != "POST" || item.MimeType != "HTML" {
+		t.Errorf("Expected the method of the saved request and the mime type, got %s and %s", item.Method.Value, item.MimeType)
+	}
+	req, _ := base64.StdEncoding.DecodeString(item.Request.Value)
+	resp, _ := base64.StdEncoding.DecodeString(item.Response.Value)
+	if string(req) != rawReq || string(resp) != rawResp {
+		t.Errorf("Expected the saved request and response, got %q and %q", req, resp)
+	}
+	if items.Items[1].Port != 80 || items.Items[1].Method.Value != "GET" || items.Items[1].Request.Value != "" {
+		t.Errorf("Expected the defaults for a result without a result file, got %+v", items.Items[1])
+	}
+}
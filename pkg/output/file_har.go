@@ -0,0 +1,285 @@
+package output
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/ffuf/ffuf/v2/pkg/ffuf"
+)
+
+// The HAR 1.2 format, see http://www.softwareishard.com/blog/har-12-spec/
+type harContent struct {
+	Version string     `json:"version"`
+	Creator harCreator `json:"creator"`
+	Comment string     `json:"comment,omitempty"`
+}
+
+type harCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type harEntry struct {
+	StartedDateTime string      `json:"startedDateTime"`
+	Time            float64     `json:"time"`
+	Request         harRequest  `json:"request"`
+	Response        harResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         harTimings  `json:"timings"`
+	Matched         bool        `json:"_matched"`
+	Input           []harPair   `json:"_input,omitempty"`
+}
+
+type harRequest struct {
+	Method      string       `json:"method"`
+	Url         string       `json:"url"`
+	HTTPVersion string       `json:"httpVersion"`
+	Cookies     []harPair    `json:"cookies"`
+	Headers     []harPair    `json:"headers"`
+	QueryString []harPair    `json:"queryString"`
+	PostData    *harPostData `json:"postData,omitempty"`
+	HeadersSize int          `json:"headersSize"`
+	BodySize    int          `json:"bodySize"`
+}
+
+type harPostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+type harResponse struct {
+	Status      int64     `json:"status"`
+	StatusText  string    `json:"statusText"`
+	HTTPVersion string    `json:"httpVersion"`
+	Cookies     []harPair `json:"cookies"`
+	Headers     []harPair `json:"headers"`
+	Content     harBody   `json:"content"`
+	RedirectURL string    `json:"redirectURL"`
+	HeadersSize int       `json:"headersSize"`
+	BodySize    int       `json:"bodySize"`
+}
+
+type harBody struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text,omitempty"`
+	Encoding string `json:"encoding,omitempty"`
+}
+
+type harTimings struct {
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
+
+type harPair struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// harFile streams the entries to a HAR output file as the responses arrive. The file is a complete HAR document once
+// it is closed.
+type harFile struct {
+	filename string
+	mutex    sync.Mutex
+	file     *os.File
+	writer   *bufio.Writer
+	entries  int
+}
+
+// newHARFile creates the output file and writes the beginning of the HAR document to it
+func newHARFile(filename string, config *ffuf.Config) (*harFile, error) {
+	f, err := os.Create(filename)
+	if err != nil {
+		return nil, err
+	}
+	h := &harFile{filename: filename, file: f, writer: bufio.NewWriter(f)}
+	header, err := json.Marshal(harContent{
+		Version: "1.2",
+		Creator: harCreator{Name: "ffuf", Version: ffuf.Version()},
+		Comment: config.CommandLine,
+	})
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	// Leave the entries array open for the entries to come
+	_, _ = h.writer.WriteString(`{"log":`)
+	_, _ = h.writer.Write(header[:len(header)-1])
+	_, err = h.writer.WriteString(`,"entries":[`)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return h, nil
+}
+
+// WriteEntry appends an entry to the file
+func (h *harFile) WriteEntry(entry harEntry) error {
+	entryBytes, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	if h.entries > 0 {
+		_ = h.writer.WriteByte(',')
+	}
+	h.entries++
+	_, err = h.writer.Write(entryBytes)
+	return err
+}
+
+// Close finishes the HAR document and closes the file
+func (h *harFile) Close() error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	_, err := h.writer.WriteString("]}}")
+	if err == nil {
+		err = h.writer.Flush()
+	}
+	if err != nil {
+		h.file.Close()
+		return err
+	}
+	return h.file.Close()
+}
+
+// writeHAR writes a HAR file of the results. The results do not include the headers and bodies, so the entries are
+// limited to what is known of the responses.
+func writeHAR(filename string, config *ffuf.Config, res []ffuf.Result) error {
+	h, err := newHARFile(filename, config)
+	if err != nil {
+		return err
+	}
+	for _, r := range res {
+		if err := h.WriteEntry(resultToHAR(r, config)); err != nil {
+			h.file.Close()
+			return err
+		}
+	}
+	return h.Close()
+}
+
+// responseToHAR creates a HAR entry of a response
+func responseToHAR(resp ffuf.Response, matched bool) harEntry {
+	req := resp.Request
+	entry := harEntry{
+		StartedDateTime: time.Now().Add(-resp.Time).Format(time.RFC3339Nano),
+		Time:            durationMs(resp.Time),
+		Timings:         harTimings{Send: 0, Wait: durationMs(resp.Time), Receive: 0},
+		Matched:         matched,
+		Request: harRequest{
+			Method:      req.Method,
+			Url:         req.Url,
+			HTTPVersion: "HTTP/1.1",
+			Cookies:     []harPair{},
+			Headers:     make([]harPair, 0, len(req.Headers)),
+			QueryString: harQueryString(req.Url),
+			HeadersSize: -1,
+			BodySize:    len(req.Data),
+		},
+		Response: harResponse{
+			Status:      resp.StatusCode,
+			StatusText:  http.StatusText(int(resp.StatusCode)),
+			HTTPVersion: harHTTPVersion(resp.Raw),
+			Cookies:     []harPair{},
+			Headers:     make([]harPair, 0, len(resp.Headers)),
+			Content:     harContentBody(resp.Data, resp.ContentType),
+			RedirectURL: resp.GetRedirectLocation(true),
+			HeadersSize: -1,
+			BodySize:    len(resp.Data),
+		},
+	}
+	for name, value := range req.Headers {
+		entry.Request.Headers = append(entry.Request.Headers, harPair{Name: name, Value: value})
+	}
+	sortHARPairs(entry.Request.Headers)
+	if len(req.Data) > 0 {
+		entry.Request.PostData = &harPostData{MimeType: req.Headers["Content-Type"], Text: string(req.Data)}
+	}
+	for name, values := range resp.Headers {
+		for _, value := range values {
+			entry.Response.Headers = append(entry.Response.Headers, harPair{Name: name, Value: value})
+		}
+	}
+	sortHARPairs(entry.Response.Headers)
+	for keyword, value := range req.Input {
+		if keyword != "FFUFHASH" {
+			entry.Input = append(entry.Input, harPair{Name: keyword, Value: string(value)})
+		}
+	}
+	sortHARPairs(entry.Input)
+	return entry
+}
+
+// resultToHAR creates a HAR entry of a result
+func resultToHAR(r ffuf.Result, config *ffuf.Config) harEntry {
+	req := &ffuf.Request{Method: config.Method, Url: r.Url, Input: r.Input, Headers: config.Headers}
+	resp := ffuf.Response{
+		StatusCode:  r.StatusCode,
+		Headers:     map[string][]string{},
+		ContentType: r.ContentType,
+		Request:     req,
+		Time:        r.Duration,
+	}
+	if r.RedirectLocation != "" {
+		resp.Headers["Location"] = []string{r.RedirectLocation}
+	}
+	entry := responseToHAR(resp, true)
+	entry.Response.Content.Size = int(r.ContentLength)
+	entry.Response.BodySize = int(r.ContentLength)
+	return entry
+}
+
+func durationMs(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
+
+func harQueryString(rawurl string) []harPair {
+	pairs := make([]harPair, 0)
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return pairs
+	}
+	for name, values := range u.Query() {
+		for _, value := range values {
+			pairs = append(pairs, harPair{Name: name, Value: value})
+		}
+	}
+	sortHARPairs(pairs)
+	return pairs
+}
+
+// harHTTPVersion reads the HTTP version from the status line of a raw response
+func harHTTPVersion(raw string) string {
+	if version, _, found := strings.Cut(raw, " "); found && strings.HasPrefix(version, "HTTP/") {
+		return version
+	}
+	return "HTTP/1.1"
+}
+
+// harContentBody stores a body as text, or base64 encoded if it is not valid UTF-8
+func harContentBody(data []byte, contentType string) harBody {
+	body := harBody{Size: len(data), MimeType: contentType}
+	if utf8.Valid(data) {
+		body.Text = string(data)
+	} else {
+		body.Text = base64.StdEncoding.EncodeToString(data)
+		body.Encoding = "base64"
+	}
+	return body
+}
+
+func sortHARPairs(pairs []harPair) {
+	sort.SliceStable(pairs, func(i, j int) bool { return pairs[i].Name < pairs[j].Name })
+}
@@ -0,0 +1,92 @@
+package output
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ffuf/ffuf/v2/pkg/ffuf"
+)
+
+type testHAR struct {
+	Log struct {
+		Version string     `json:"version"`
+		Entries []harEntry `json:"entries"`
+	} `json:"log"`
+}
+
+func readHAR(t *testing.T, filename string) testHAR {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("Failed to read the HAR file: %v", err)
+	}
+	var har testHAR
+	if err := json.Unmarshal(data, &har); err != nil {
+		t.Fatalf("Failed to parse the HAR file: %v\n%s", err, data)
+	}
+	return har
+}
+
+func TestHARStreaming(t *testing.T) {
+	conf := ffuf.NewConfig(context.Background(), func() {})
+	conf.Quiet = true
+	conf.HarAll = true
+	conf.OutputFile = filepath.Join(t.TempDir(), "results.har")
+	conf.OutputFormat = "har"
+	s := NewStdoutput(&conf)
+
+	resp := testResponse("admin?id=1", 200)
+	resp.Request.Method = "POST"
+	resp.Request.Headers = map[string]string{"Content-Type": "application/x-www-form-urlencoded"}
+	resp.Request.Data = []byte("user=FUZZ")
+	resp.Headers = map[string][]string{"Content-Type": {"text/html"}, "Set-Cookie": {"a=1", "b=2"}}
+	resp.Data = []byte("<html>admin</html>")
+	resp.ContentType = "text/html"
+	resp.Time = 250 * time.Millisecond
+	s.Result(resp)
+	s.Record(testResponse("missing", 404))
+	if err := s.Finalize(); err != nil {
+		t.Fatalf("Finalize failed: %v", err)
+	}
+
+	har := readHAR(t, conf.OutputFile)
+	if har.Log.Version != "1.2" || len(har.Log.Entries) != 2 {
+		t.Fatalf("Expected a HAR 1.2 log with 2 entries, got %+v", har.Log)
+	}
+	e := har.Log.Entries[0]
+	if !e.Matched || e.Request.Method != "POST" || e.Request.Url != "http://example.com/admin?id=1" || e.Response.Status != 200 {
+		t.Errorf("Expected the entry of the result, got %+v", e)
+	}
+	if e.Time != 250 || e.Timings.Wait != 250 {
+		t.Errorf("Expected the response time as the timings, got %f and %f", e.Time, e.Timings.Wait)
+	}
+	if e.Request.PostData == nil || e.Request.PostData.Text != "user=FUZZ" || len(e.Request.QueryString) != 1 {
+		t.Errorf("Expected the request body and the query string, got %+v", e.Request)
+	}
+	if len(e.Response.Headers) != 3 || e.Response.Content.Text != "<html>admin</html>" || e.Response.Content.MimeType != "text/html" {
+		t.Errorf("Expected the response headers and body, got %+v", e.Response)
+	}
+	if har.Log.Entries[1].Matched || har.Log.Entries[1].Response.Status != 404 {
+		t.Errorf("Expected the entry of the recorded response, got %+v", har.Log.Entries[1])
+	}
+}
+
+func TestWriteHAR(t *testing.T) {
+	conf := ffuf.NewConfig(context.Background(), func() {})
+	conf.Quiet = true
+	s := NewStdoutput(&conf)
+	s.Result(testResponse("a", 200))
+	// Only the results are recorded without -har-all
+	s.Record(testResponse("b", 404))
+	filename := filepath.Join(t.TempDir(), "saved.har")
+	if err := s.SaveFile(filename, "har"); err != nil {
+		t.Fatalf("SaveFile failed: %v", err)
+	}
+	har := readHAR(t, filename)
+	if len(har.Log.Entries) != 1 || har.Log.Entries[0].Request.Url != "http://example.com/a" {
+		t.Errorf("Expected an entry for the result, got %+v", har.Log.Entries)
+	}
+}
@@ -11,10 +11,18 @@ import (
 type ejsonFileOutput struct {
 	CommandLine string        `json:"commandline"`
 	Time        string        `json:"time"`
+	Stats       JsonStats     `json:"stats"`
 	Results     []ffuf.Result `json:"results"`
 	Config      *ffuf.Config  `json:"config"`
 }
 
+// JsonStats holds the request counters of the whole ffuf run
+type JsonStats struct {
+	Errors  int `json:"errors"`
+	Retries int `json:"retries"`
+	Failed  int `json:"failed"`
+}
+
 type JsonResult struct {
 	Input            map[string]string   `json:"input"`
 	Position         int                 `json:"position"`
@@ -29,20 +37,23 @@ type JsonResult struct {
 	ResultFile       string              `json:"resultfile"`
 	Url              string              `json:"url"`
 	Host             string              `json:"host"`
+	Retries          int                 `json:"retries"`
 }
 
 type jsonFileOutput struct {
 	CommandLine string       `json:"commandline"`
 	Time        string       `json:"time"`
+	Stats       JsonStats    `json:"stats"`
 	Results     []JsonResult `json:"results"`
 	Config      *ffuf.Config `json:"config"`
 }
 
-func writeEJSON(filename string, config *ffuf.Config, res []ffuf.Result) error {
+func writeEJSON(filename string, config *ffuf.Config, stats JsonStats, res []ffuf.Result) error {
 	t := time.Now()
 	outJSON := ejsonFileOutput{
 		CommandLine: config.CommandLine,
 		Time:        t.Format(time.RFC3339),
+		Stats:       stats,
 		Results:     res,
 	}
 
@@ -57,33 +68,16 @@ func writeEJSON(filename string, config *ffuf.Config, res []ffuf.Result) error {
 	return nil
 }
 
-func writeJSON(filename string, config *ffuf.Config, res []ffuf.Result) error {
+func writeJSON(filename string, config *ffuf.Config, stats JsonStats, res []ffuf.Result) error {
 	t := time.Now()
 	jsonRes := make([]JsonResult, 0)
 	for _, r := range res {
-		strinput := make(map[string]string)
-		for k, v := range r.Input {
-			strinput[k] = string(v)
-		}
-		jsonRes = append(jsonRes, JsonResult{
-			Input:            strinput,
-			Position:         r.Position,
-			StatusCode:       r.StatusCode,
-			ContentLength:    r.ContentLength,
-			ContentWords:     r.ContentWords,
-			ContentLines:     r.ContentLines,
-			ContentType:      r.ContentType,
-			RedirectLocation: r.RedirectLocation,
-			ScraperData:      r.ScraperData,
-			Duration:         r.Duration,
-			ResultFile:       r.ResultFile,
-			Url:              r.Url,
-			Host:             r.Host,
-		})
+		jsonRes = append(jsonRes, toJsonResult(r))
 	}
 	outJSON := jsonFileOutput{
 		CommandLine: config.CommandLine,
 		Time:        t.Format(time.RFC3339),
+		Stats:       stats,
 		Results:     jsonRes,
 		Config:      config,
 	}
@@ -97,3 +91,27 @@ func writeJSON(filename string, config *ffuf.Config, res []ffuf.Result) error {
 	}
 	return nil
 }
+
+// toJsonResult converts a result to the format of the json output file
+func toJsonResult(r ffuf.Result) JsonResult {
+	strinput := make(map[string]string)
+	for k, v := range r.Input {
+		strinput[k] = string(v)
+	}
+	return JsonResult{
+		Input:            strinput,
+		Position:         r.Position,
+		StatusCode:       r.StatusCode,
+		ContentLength:    r.ContentLength,
+		ContentWords:     r.ContentWords,
+		ContentLines:     r.ContentLines,
+		ContentType:      r.ContentType,
+		RedirectLocation: r.RedirectLocation,
+		ScraperData:      r.ScraperData,
+		Duration:         r.Duration,
+		ResultFile:       r.ResultFile,
+		Url:              r.Url,
+		Host:             r.Host,
+		Retries:          r.Retries,
+	}
+}
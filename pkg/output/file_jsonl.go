@@ -0,0 +1,144 @@
+package output
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ffuf/ffuf/v2/pkg/ffuf"
+)
+
+const JSONL_FLUSH_INTERVAL = time.Second
+
+// The lines of a jsonl output file: a header with the configuration, a line for each result and a trailer with the
+// request counters, told apart by the type field
+type jsonlHeader struct {
+	Type        string       `json:"type"`
+	CommandLine string       `json:"commandline"`
+	Time        string       `json:"time"`
+	Config      *ffuf.Config `json:"config"`
+}
+
+type jsonlResult struct {
+	Type string `json:"type"`
+	JsonResult
+}
+
+type jsonlTrailer struct {
+	Type  string    `json:"type"`
+	Time  string    `json:"time"`
+	Stats JsonStats `json:"stats"`
+}
+
+// jsonlFile streams the results in the jsonl format as they are found, to the jsonl output file (-of jsonl) or to
+// the jsonl output provider (-output-provider jsonl)
+type jsonlFile struct {
+	filename  string
+	mutex     sync.Mutex
+	closer    io.Closer
+	writer    *bufio.Writer
+	lastFlush time.Time
+}
+
+// newJSONLWriter writes the header line to w, and returns a jsonlFile writing the rest of the lines to it
+func newJSONLWriter(w io.Writer, config *ffuf.Config) (*jsonlFile, error) {
+	j := &jsonlFile{writer: bufio.NewWriter(w), lastFlush: time.Now()}
+	err := j.writeLine(jsonlHeader{
+		Type:        "config",
+		CommandLine: config.CommandLine,
+		Time:        time.Now().Format(time.RFC3339),
+		Config:      config,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return j, nil
+}
+
+// newJSONLFile creates the output file and writes the header line to it
+func newJSONLFile(filename string, config *ffuf.Config) (*jsonlFile, error) {
+	f, err := os.Create(filename)
+	if err != nil {
+		return nil, err
+	}
+	j, err := newJSONLWriter(f, config)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	j.filename = filename
+	j.closer = f
+	return j, nil
+}
+
+func (j *jsonlFile) writeLine(line interface{}) error {
+	lineBytes, err := json.Marshal(line)
+	if err != nil {
+		return err
+	}
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	_, err = j.writer.Write(append(lineBytes, '\n'))
+	return err
+}
+
+// WriteResult appends a result to the file. The buffered lines are flushed to disk at most JSONL_FLUSH_INTERVAL after
+// they were written, as long as the job keeps reporting on its progress.
+func (j *jsonlFile) WriteResult(res ffuf.Result) error {
+	err := j.writeLine(jsonlResult{Type: "result", JsonResult: toJsonResult(res)})
+	if err != nil {
+		return err
+	}
+	return j.FlushIfDue()
+}
+
+// FlushIfDue flushes the buffered lines to disk if they have not been flushed for JSONL_FLUSH_INTERVAL
+func (j *jsonlFile) FlushIfDue() error {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	if time.Since(j.lastFlush) < JSONL_FLUSH_INTERVAL {
+		return nil
+	}
+	j.lastFlush = time.Now()
+	return j.writer.Flush()
+}
+
+// Flush flushes the buffered lines right away
+func (j *jsonlFile) Flush() error {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	j.lastFlush = time.Now()
+	return j.writer.Flush()
+}
+
+// Close writes the trailer line with the request counters, and closes the file if there is one
+func (j *jsonlFile) Close(stats JsonStats) error {
+	err := j.writeLine(jsonlTrailer{Type: "stats", Time: time.Now().Format(time.RFC3339), Stats: stats})
+	if err == nil {
+		err = j.Flush()
+	}
+	if j.closer != nil {
+		if cerr := j.closer.Close(); err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
+// writeJSONL writes a complete jsonl output file of the results
+func writeJSONL(filename string, config *ffuf.Config, stats JsonStats, res []ffuf.Result) error {
+	j, err := newJSONLFile(filename, config)
+	if err != nil {
+		return err
+	}
+	for _, r := range res {
+		if err := j.writeLine(jsonlResult{Type: "result", JsonResult: toJsonResult(r)}); err != nil {
+			j.closer.Close()
+			return err
+		}
+	}
+	return j.Close(stats)
+}
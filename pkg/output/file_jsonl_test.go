@@ -0,0 +1,77 @@
+package output
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ffuf/ffuf/v2/pkg/ffuf"
+)
+
+func readJSONLTypes(t *testing.T, filename string) []string {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("Failed to read the jsonl file: %v", err)
+	}
+	types := make([]string, 0)
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		var l struct {
+			Type string `json:"type"`
+		}
+		if err := json.Unmarshal([]byte(line), &l); err != nil {
+			t.Fatalf("Failed to parse line %s: %v", line, err)
+		}
+		types = append(types, l.Type)
+	}
+	return types
+}
+
+func TestJSONLStreaming(t *testing.T) {
+	conf := ffuf.NewConfig(context.Background(), func() {})
+	conf.Quiet = true
+	conf.OutputFile = filepath.Join(t.TempDir(), "results.jsonl")
+	conf.OutputFormat = "jsonl"
+	s := NewStdoutput(&conf)
+
+	s.Result(testResponse("a", 200))
+	s.Result(testResponse("b", 200))
+	// The results are on disk once flushed, before the job is done
+	s.jsonl.lastFlush = time.Now().Add(-JSONL_FLUSH_INTERVAL)
+	s.Progress(ffuf.Progress{StartedAt: time.Now(), ErrorCount: 3})
+	types := readJSONLTypes(t, conf.OutputFile)
+	if strings.Join(types, ",") != "config,result,result" {
+		t.Errorf("Expected the header and two results before the job is done, got %v", types)
+	}
+
+	s.Result(testResponse("c", 200))
+	if err := s.Finalize(); err != nil {
+		t.Fatalf("Finalize failed: %v", err)
+	}
+	types = readJSONLTypes(t, conf.OutputFile)
+	if strings.Join(types, ",") != "config,result,result,result,stats" {
+		t.Errorf("Expected the header, three results and the trailer, got %v", types)
+	}
+	data, _ := os.ReadFile(conf.OutputFile)
+	if !strings.Contains(string(data), `{"type":"stats"`) || !strings.Contains(string(data), `"errors":3`) {
+		t.Errorf("Expected the trailer to include the stats, got %s", data)
+	}
+}
+
+func TestWriteJSONL(t *testing.T) {
+	conf := ffuf.NewConfig(context.Background(), func() {})
+	conf.Quiet = true
+	s := NewStdoutput(&conf)
+	s.Result(testResponse("a", 200))
+	filename := filepath.Join(t.TempDir(), "saved.jsonl")
+	if err := s.SaveFile(filename, "jsonl"); err != nil {
+		t.Fatalf("SaveFile failed: %v", err)
+	}
+	types := readJSONLTypes(t, filename)
+	if strings.Join(types, ",") != "config,result,stats" {
+		t.Errorf("Expected the header, the result and the trailer, got %v", types)
+	}
+}
@@ -0,0 +1,99 @@
+package output
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ffuf/ffuf/v2/pkg/ffuf"
+)
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name       string          `xml:"name,attr"`
+	Tests      int             `xml:"tests,attr"`
+	Failures   int             `xml:"failures,attr"`
+	Timestamp  string          `xml:"timestamp,attr"`
+	Properties []junitProperty `xml:"properties>property"`
+	TestCases  []junitTestCase `xml:"testcase"`
+}
+
+type junitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// writeJUnit writes the results as a JUnit XML test suite, with a failed test case for each result, so a CI pipeline
+// fails when the job finds anything
+func writeJUnit(filename string, config *ffuf.Config, res []ffuf.Result) error {
+	suite := junitTestSuite{
+		Name:       "ffuf",
+		Tests:      len(res),
+		Failures:   len(res),
+		Timestamp:  time.Now().Format("2006-01-02T15:04:05"),
+		Properties: []junitProperty{{Name: "commandline", Value: config.CommandLine}},
+		TestCases:  make([]junitTestCase, 0, len(res)),
+	}
+	for _, r := range res {
+		suite.TestCases = append(suite.TestCases, toJUnit(r))
+	}
+	outXML, err := xml.MarshalIndent(junitTestSuites{Name: "ffuf", Tests: suite.Tests, Failures: suite.Failures, Suites: []junitTestSuite{suite}}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, append([]byte(xml.Header), outXML...), 0644)
+}
+
+func toJUnit(r ffuf.Result) junitTestCase {
+	inputs := make([]string, 0)
+	for k, v := range r.Input {
+		if k != "FFUFHASH" {
+			inputs = append(inputs, fmt.Sprintf("%s: %s", k, v))
+		}
+	}
+	sort.Strings(inputs)
+	details := []string{
+		fmt.Sprintf("URL: %s", r.Url),
+		fmt.Sprintf("Input: %s", strings.Join(inputs, ", ")),
+		fmt.Sprintf("Status: %d, Size: %d, Words: %d, Lines: %d, Duration: %dms", r.StatusCode, r.ContentLength, r.ContentWords, r.ContentLines, r.Duration.Milliseconds()),
+	}
+	if r.RedirectLocation != "" {
+		details = append(details, fmt.Sprintf("Redirect location: %s", r.RedirectLocation))
+	}
+	if r.ResultFile != "" {
+		details = append(details, fmt.Sprintf("Result file: %s", r.ResultFile))
+	}
+	return junitTestCase{
+		Name:      r.Url,
+		ClassName: r.Host,
+		Time:      fmt.Sprintf("%.3f", r.Duration.Seconds()),
+		Failure: &junitFailure{
+			Message: fmt.Sprintf("Matched response [Status: %d, Size: %d, Words: %d, Lines: %d]", r.StatusCode, r.ContentLength, r.ContentWords, r.ContentLines),
+			Type:    "match",
+			Text:    strings.Join(details, "\n"),
+		},
+	}
+}
@@ -0,0 +1,39 @@
+package output
+
+import (
+	"context"
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ffuf/ffuf/v2/pkg/ffuf"
+)
+
+func TestWriteJUnit(t *testing.T) {
+	conf := ffuf.NewConfig(context.Background(), func() {})
+	res := []ffuf.Result{
+		{Input: map[string][]byte{"FUZZ": []byte("admin")}, StatusCode: 200, Url: "https://example.com/admin", Host: "example.com", Duration: 1500 * time.Millisecond},
+		{Input: map[string][]byte{"FUZZ": []byte("login")}, StatusCode: 302, Url: "https://example.com/login", Host: "example.com"},
+	}
+	filename := filepath.Join(t.TempDir(), "results.xml")
+	if err := writeJUnit(filename, &conf, res); err != nil {
+		t.Fatalf("writeJUnit failed: %v", err)
+	}
+	data, _ := os.ReadFile(filename)
+	var suites junitTestSuites
+	if err := xml.Unmarshal(data, &suites); err != nil {
+		t.Fatalf("Failed to parse the JUnit file: %v", err)
+	}
+	if suites.Tests != 2 || suites.Failures != 2 || len(suites.Suites) != 1 {
+		t.Fatalf("Expected one suite with two failed tests, got %s", data)
+	}
+	tc := suites.Suites[0].TestCases[0]
+	if tc.Name != "https://example.com/admin" || tc.ClassName != "example.com" || tc.Time != "1.500" {
+		t.Errorf("Expected a test case for the result, got %+v", tc)
+	}
+	if tc.Failure == nil || tc.Failure.Message != "Matched response [Status: 200, Size: 0, Words: 0, Lines: 0]" {
+		t.Errorf("Expected the test case to fail with the response details, got %+v", tc.Failure)
+	}
+}
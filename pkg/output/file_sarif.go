@@ -0,0 +1,170 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ffuf/ffuf/v2/pkg/ffuf"
+)
+
+const (
+	SARIF_SCHEMA  = "https://json.schemastore.org/sarif-2.1.0.json"
+	SARIF_VERSION = "2.1.0"
+	SARIF_RULE_ID = "ffuf/match"
+)
+
+// The subset of the SARIF 2.1.0 format used for the results of a run
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool        sarifTool         `json:"tool"`
+	Invocations []sarifInvocation `json:"invocations"`
+	Results     []sarifResult     `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	Name             string       `json:"name"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifInvocation struct {
+	CommandLine         string `json:"commandLine"`
+	EndTimeUtc          string `json:"endTimeUtc"`
+	ExecutionSuccessful bool   `json:"executionSuccessful"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID           string                 `json:"ruleId"`
+	Level            string                 `json:"level"`
+	Message          sarifMessage           `json:"message"`
+	Locations        []sarifLocation        `json:"locations"`
+	RelatedLocations []sarifLocation        `json:"relatedLocations,omitempty"`
+	Properties       map[string]interface{} `json:"properties"`
+}
+
+type sarifLocation struct {
+	ID               int                    `json:"id,omitempty"`
+	PhysicalLocation *sarifPhysicalLocation `json:"physicalLocation,omitempty"`
+	Message          *sarifMessage          `json:"message,omitempty"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+func writeSARIF(filename string, config *ffuf.Config, res []ffuf.Result) error {
+	results := make([]sarifResult, 0, len(res))
+	for _, r := range res {
+		results = append(results, toSARIF(r))
+	}
+	invocation := sarifInvocation{
+		CommandLine:         config.CommandLine,
+		EndTimeUtc:          time.Now().UTC().Format(time.RFC3339),
+		ExecutionSuccessful: true,
+	}
+	outSARIF := sarifLog{
+		Schema:  SARIF_SCHEMA,
+		Version: SARIF_VERSION,
+		Runs: []sarifRun{{
+			Tool: sarifTool{Driver: sarifDriver{
+				Name:           "ffuf",
+				Version:        ffuf.Version(),
+				InformationURI: "https://github.com/ffuf/ffuf",
+				Rules: []sarifRule{{
+					ID:               SARIF_RULE_ID,
+					Name:             "MatchedResponse",
+					ShortDescription: sarifMessage{Text: "A response matched the matchers and filters of the ffuf run"},
+				}},
+			}},
+			Invocations: []sarifInvocation{invocation},
+			Results:     results,
+		}},
+	}
+	outBytes, err := json.Marshal(outSARIF)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, outBytes, 0644)
+}
+
+// toSARIF maps a result to a SARIF result with the URL as the location, and the scraper data as related locations
+func toSARIF(r ffuf.Result) sarifResult {
+	inputs := make(map[string]string)
+	keywords := make([]string, 0)
+	for k, v := range r.Input {
+		inputs[k] = string(v)
+		if k != "FFUFHASH" {
+			keywords = append(keywords, fmt.Sprintf("%s: %s", k, v))
+		}
+	}
+	sort.Strings(keywords)
+	props := map[string]interface{}{
+		"status":   r.StatusCode,
+		"size":     r.ContentLength,
+		"words":    r.ContentWords,
+		"lines":    r.ContentLines,
+		"duration": r.Duration.Milliseconds(),
+		"input":    inputs,
+		"position": r.Position,
+	}
+	if r.ContentType != "" {
+		props["content-type"] = r.ContentType
+	}
+	if r.RedirectLocation != "" {
+		props["redirectlocation"] = r.RedirectLocation
+	}
+	if r.ResultFile != "" {
+		props["resultfile"] = r.ResultFile
+	}
+	related := make([]sarifLocation, 0)
+	names := make([]string, 0, len(r.ScraperData))
+	for name := range r.ScraperData {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, value := range r.ScraperData[name] {
+			related = append(related, sarifLocation{
+				ID:               len(related) + 1,
+				PhysicalLocation: &sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: r.Url}},
+				Message:          &sarifMessage{Text: fmt.Sprintf("%s: %s", name, value)},
+			})
+		}
+	}
+	return sarifResult{
+		RuleID:           SARIF_RULE_ID,
+		Level:            "note",
+		Message:          sarifMessage{Text: strings.TrimSpace(fmt.Sprintf("%s [Status: %d, Size: %d, Words: %d, Lines: %d] %s", r.Url, r.StatusCode, r.ContentLength, r.ContentWords, r.ContentLines, strings.Join(keywords, ", ")))},
+		Locations:        []sarifLocation{{PhysicalLocation: &sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: r.Url}}}},
+		RelatedLocations: related,
+		Properties:       props,
+	}
+}
@@ -0,0 +1,54 @@
+package output
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ffuf/ffuf/v2/pkg/ffuf"
+)
+
+func TestWriteSARIF(t *testing.T) {
+	conf := ffuf.NewConfig(context.Background(), func() {})
+	conf.CommandLine = "ffuf -w words.txt -u https://example.com/FUZZ"
+	res := []ffuf.Result{{
+		Input:         map[string][]byte{"FUZZ": []byte("admin"), "FFUFHASH": []byte("abc")},
+		StatusCode:    200,
+		ContentLength: 42,
+		ContentWords:  3,
+		ContentLines:  1,
+		Url:           "https://example.com/admin",
+		ScraperData:   map[string][]string{"title": {"Admin panel"}},
+	}}
+	filename := filepath.Join(t.TempDir(), "results.sarif")
+	if err := writeSARIF(filename, &conf, res); err != nil {
+		t.Fatalf("writeSARIF failed: %v", err)
+	}
+	data, _ := os.ReadFile(filename)
+	var log sarifLog
+	if err := json.Unmarshal(data, &log); err != nil {
+		t.Fatalf("Failed to parse the SARIF file: %v", err)
+	}
+	if log.Version != "2.1.0" || len(log.Runs) != 1 {
+		t.Fatalf("Expected a SARIF 2.1.0 log with one run, got %s", data)
+	}
+	run := log.Runs[0]
+	if run.Invocations[0].CommandLine != conf.CommandLine {
+		t.Errorf("Expected the command line as the invocation, got %s", run.Invocations[0].CommandLine)
+	}
+	if len(run.Results) != 1 {
+		t.Fatalf("Expected one result, got %d", len(run.Results))
+	}
+	r := run.Results[0]
+	if r.Locations[0].PhysicalLocation.ArtifactLocation.URI != "https://example.com/admin" {
+		t.Errorf("Expected the URL as the location, got %s", r.Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	}
+	if r.Properties["status"] != float64(200) || r.Properties["size"] != float64(42) || r.Properties["words"] != float64(3) || r.Properties["lines"] != float64(1) {
+		t.Errorf("Expected the response details as properties, got %v", r.Properties)
+	}
+	if len(r.RelatedLocations) != 1 || r.RelatedLocations[0].Message.Text != "title: Admin panel" {
+		t.Errorf("Expected the scraper data as a related location, got %v", r.RelatedLocations)
+	}
+}
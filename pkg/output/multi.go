@@ -0,0 +1,134 @@
+package output
+
+import (
+	"github.com/ffuf/ffuf/v2/pkg/ffuf"
+)
+
+// MultiOutput passes the output of a job to multiple output providers. The current results are read from the first
+// provider that keeps them, as the streaming providers do not.
+type MultiOutput struct {
+	outputs []ffuf.OutputProvider
+}
+
+func NewMultiOutput(outputs ...ffuf.OutputProvider) *MultiOutput {
+	return &MultiOutput{outputs: outputs}
+}
+
+func (m *MultiOutput) Banner() {
+	for _, o := range m.outputs {
+		o.Banner()
+	}
+}
+
+func (m *MultiOutput) Finalize() error {
+	errs := ffuf.NewMultierror()
+	for _, o := range m.outputs {
+		if err := o.Finalize(); err != nil {
+			errs.Add(err)
+		}
+	}
+	return errs.ErrorOrNil()
+}
+
+func (m *MultiOutput) Progress(status ffuf.Progress) {
+	for _, o := range m.outputs {
+		o.Progress(status)
+	}
+}
+
+func (m *MultiOutput) Info(infostring string) {
+	for _, o := range m.outputs {
+		o.Info(infostring)
+	}
+}
+
+func (m *MultiOutput) Error(errstring string) {
+	for _, o := range m.outputs {
+		o.Error(errstring)
+	}
+}
+
+func (m *MultiOutput) Raw(output string) {
+	for _, o := range m.outputs {
+		o.Raw(output)
+	}
+}
+
+func (m *MultiOutput) Warning(warnstring string) {
+	for _, o := range m.outputs {
+		o.Warning(warnstring)
+	}
+}
+
+func (m *MultiOutput) Result(resp ffuf.Response) {
+	for _, o := range m.outputs {
+		o.Result(resp)
+	}
+}
+
+// Record passes a response that is not a result to the providers recording all of the responses
+func (m *MultiOutput) Record(resp ffuf.Response) {
+	for _, o := range m.outputs {
+		if rec, ok := o.(ffuf.ResponseRecorder); ok {
+			rec.Record(resp)
+		}
+	}
+}
+
+func (m *MultiOutput) PrintResult(res ffuf.Result) {
+	for _, o := range m.outputs {
+		o.PrintResult(res)
+	}
+}
+
+func (m *MultiOutput) SaveFile(filename, format string) error {
+	errs := ffuf.NewMultierror()
+	for _, o := range m.outputs {
+		if err := o.SaveFile(filename, format); err != nil {
+			errs.Add(err)
+		}
+	}
+	return errs.ErrorOrNil()
+}
+
+func (m *MultiOutput) GetResults() []ffuf.Result {
+	for _, o := range m.outputs {
+		if res := o.GetResults(); res != nil {
+			return res
+		}
+	}
+	return nil
+}
+
+func (m *MultiOutput) SetResults(results []ffuf.Result) {
+	for _, o := range m.outputs {
+		o.SetResults(results)
+	}
+}
+
+func (m *MultiOutput) GetCurrentResults() []ffuf.Result {
+	for _, o := range m.outputs {
+		if res := o.GetCurrentResults(); res != nil {
+			return res
+		}
+	}
+	return nil
+}
+
+func (m *MultiOutput) SetCurrentResults(results []ffuf.Result) {
+	for _, o := range m.outputs {
+		o.SetCurrentResults(results)
+	}
+}
+
+func (m *MultiOutput) Reset() {
+	for _, o := range m.outputs {
+		o.Reset()
+	}
+}
+
+func (m *MultiOutput) Cycle() {
+	for _, o := range m.outputs {
+		o.Cycle()
+	}
+}
@@ -1,10 +1,90 @@
 package output
 
 import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+
 	"github.com/ffuf/ffuf/v2/pkg/ffuf"
 )
 
-func NewOutputProviderByName(name string, conf *ffuf.Config) ffuf.OutputProvider {
-	//We have only one outputprovider at the moment
-	return NewStdoutput(conf)
+// ProviderFactory creates an output provider. The argument is the part of the -output-provider value after the
+// first colon, eg. the file name of "jsonl:results.jsonl", or an empty string if there is none.
+type ProviderFactory func(conf *ffuf.Config, arg string) (ffuf.OutputProvider, error)
+
+var (
+	providersMutex sync.RWMutex
+	providers      = map[string]ProviderFactory{
+		"stdout": func(conf *ffuf.Config, arg string) (ffuf.OutputProvider, error) {
+			return NewStdoutput(conf), nil
+		},
+		"jsonl":   newJSONLOutput,
+		"sqlite":  newSQLiteOutput,
+		"webhook": newWebhookOutput,
+	}
+)
+
+// RegisterOutputProvider adds an output provider to be selected by its name with -output-provider
+func RegisterOutputProvider(name string, factory ProviderFactory) {
+	providersMutex.Lock()
+	defer providersMutex.Unlock()
+	providers[name] = factory
+}
+
+// OutputProviderNames returns the names of the registered output providers
+func OutputProviderNames() []string {
+	providersMutex.RLock()
+	defer providersMutex.RUnlock()
+	names := make([]string, 0, len(providers))
+	for name := range providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NewOutputProviderByName creates an output provider from a value of -output-provider, eg. "stdout" or
+// "jsonl:results.jsonl"
+func NewOutputProviderByName(name string, conf *ffuf.Config) (ffuf.OutputProvider, error) {
+	name, arg, _ := strings.Cut(name, ":")
+	providersMutex.RLock()
+	factory, ok := providers[name]
+	providersMutex.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("Unknown output provider (-output-provider): %s. Available providers: %s", name, strings.Join(OutputProviderNames(), ", "))
+	}
+	return factory(conf, arg)
+}
+
+// NewOutputProvider creates the output providers selected in the configuration. The terminal output is used if none
+// are selected, and multiple providers are combined to a MultiOutput.
+func NewOutputProvider(conf *ffuf.Config) (ffuf.OutputProvider, error) {
+	names := conf.OutputProviders
+	if len(names) == 0 {
+		names = []string{"stdout"}
+	}
+	errs := ffuf.NewMultierror()
+	outputs := make([]ffuf.OutputProvider, 0, len(names))
+	for _, name := range names {
+		op, err := NewOutputProviderByName(name, conf)
+		if err != nil {
+			errs.Add(err)
+			continue
+		}
+		outputs = append(outputs, op)
+	}
+	if err := errs.ErrorOrNil(); err != nil {
+		for _, op := range outputs {
+			if c, ok := op.(io.Closer); ok {
+				c.Close()
+			}
+		}
+		return nil, err
+	}
+	if len(outputs) == 1 {
+		return outputs[0], nil
+	}
+	return NewMultiOutput(outputs...), nil
 }
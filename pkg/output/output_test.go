@@ -0,0 +1,159 @@
+package output
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ffuf/ffuf/v2/pkg/ffuf"
+)
+
+func testResponse(input string, status int64) ffuf.Response {
+	req := &ffuf.Request{Input: map[string][]byte{"FUZZ": []byte(input)}, Url: "http://example.com/" + input}
+	return ffuf.Response{StatusCode: status, Request: req, Headers: map[string][]string{}, ScraperData: map[string][]string{}}
+}
+
+func TestNewOutputProvider(t *testing.T) {
+	conf := ffuf.NewConfig(context.Background(), func() {})
+	op, err := NewOutputProvider(&conf)
+	if err != nil {
+		t.Fatalf("Failed to create the default output provider: %v", err)
+	}
+	if _, ok := op.(*Stdoutput); !ok {
+		t.Errorf("Expected stdout to be the default output provider, got %T", op)
+	}
+
+	conf.OutputProviders = []string{"stdout", "jsonl:" + filepath.Join(t.TempDir(), "results.jsonl")}
+	op, err = NewOutputProvider(&conf)
+	if err != nil {
+		t.Fatalf("Failed to create the output providers: %v", err)
+	}
+	if m, ok := op.(*MultiOutput); !ok || len(m.outputs) != 2 {
+		t.Errorf("Expected a MultiOutput with two providers, got %T", op)
+	}
+
+	conf.OutputProviders = []string{"nonexistent"}
+	if _, err := NewOutputProvider(&conf); err == nil {
+		t.Errorf("Expected an error for an unknown output provider")
+	}
+}
+
+type countingOutput struct {
+	streamOutput
+	results int
+}
+
+func (o *countingOutput) Result(resp ffuf.Response) {
+	o.results++
+}
+
+func (o *countingOutput) Finalize() error { return nil }
+
+func TestRegisterOutputProvider(t *testing.T) {
+	counter := &countingOutput{}
+	RegisterOutputProvider("counter", func(conf *ffuf.Config, arg string) (ffuf.OutputProvider, error) {
+		return counter, nil
+	})
+	conf := ffuf.NewConfig(context.Background(), func() {})
+	conf.Quiet = true
+	conf.OutputProviders = []string{"stdout", "counter"}
+	op, err := NewOutputProvider(&conf)
+	if err != nil {
+		t.Fatalf("Failed to create the output providers: %v", err)
+	}
+	op.Result(testResponse("a", 200))
+	op.Result(testResponse("b", 200))
+	if counter.results != 2 {
+		t.Errorf("Expected the registered provider to get 2 results, got %d", counter.results)
+	}
+	if len(op.GetCurrentResults()) != 2 {
+		t.Errorf("Expected the current results to be read from stdout, got %d", len(op.GetCurrentResults()))
+	}
+}
+
+func TestJSONLOutput(t *testing.T) {
+	conf := ffuf.NewConfig(context.Background(), func() {})
+	path := filepath.Join(t.TempDir(), "results.jsonl")
+	op, err := NewOutputProviderByName("jsonl:"+path, &conf)
+	if err != nil {
+		t.Fatalf("Failed to create the jsonl output provider: %v", err)
+	}
+	op.Result(testResponse("a", 200))
+	op.Result(testResponse("b", 301))
+	// The results are on disk before the job is done, in the same format as the jsonl output file
+	if types := readJSONLTypes(t, path); strings.Join(types, ",") != "config,result,result" {
+		t.Fatalf("Expected the header and two results before the job is done, got %v", types)
+	}
+	data, _ := os.ReadFile(path)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if !strings.Contains(lines[2], `"input":{"FUZZ":"b"}`) || !strings.Contains(lines[2], `"status":301`) {
+		t.Errorf("Expected the second result with the input as a string, got %s", lines[2])
+	}
+	op.Progress(ffuf.Progress{ErrorCount: 2})
+	if err := op.Finalize(); err != nil {
+		t.Errorf("Finalize failed: %v", err)
+	}
+	if types := readJSONLTypes(t, path); strings.Join(types, ",") != "config,result,result,stats" {
+		t.Errorf("Expected the header, two results and the trailer, got %v", types)
+	}
+	data, _ = os.ReadFile(path)
+	if !strings.Contains(string(data), `"errors":2`) {
+		t.Errorf("Expected the trailer to include the stats, got %s", data)
+	}
+}
+
+func TestWebhookOutput(t *testing.T) {
+	var mutex sync.Mutex
+	received := make([]string, 0)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(404)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		if !json.Valid(body) {
+			w.WriteHeader(400)
+			return
+		}
+		mutex.Lock()
+		received = append(received, string(body))
+		mutex.Unlock()
+	}))
+	defer srv.Close()
+
+	conf := ffuf.NewConfig(context.Background(), func() {})
+	op, err := NewOutputProviderByName("webhook:"+srv.URL, &conf)
+	if err != nil {
+		t.Fatalf("Failed to create the webhook output provider: %v", err)
+	}
+	for _, input := range []string{"a", "b", "c"} {
+		op.Result(testResponse(input, 200))
+	}
+	if err := op.Finalize(); err != nil {
+		t.Errorf("Finalize failed: %v", err)
+	}
+	if len(received) != 3 {
+		t.Errorf("Expected the webhook to receive 3 results, got %d", len(received))
+	}
+	for _, body := range received {
+		if !strings.Contains(body, `"input":{"FUZZ":"`) {
+			t.Errorf("Expected the webhook body to have the input as a string, got %s", body)
+		}
+	}
+
+	op, _ = NewOutputProviderByName("webhook:"+srv.URL+"/missing", &conf)
+	op.Result(testResponse("a", 200))
+	if err := op.Finalize(); err == nil {
+		t.Errorf("Expected an error when the webhook does not accept the results")
+	}
+	if _, err := NewOutputProviderByName("webhook:example.org", &conf); err == nil {
+		t.Errorf("Expected an error for a webhook without an http(s) URL")
+	}
+}
@@ -0,0 +1,329 @@
+package output
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ffuf/ffuf/v2/pkg/ffuf"
+
+	_ "modernc.org/sqlite"
+)
+
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS runs (
+	id INTEGER PRIMARY KEY,
+	started TEXT NOT NULL,
+	finished TEXT,
+	commandline TEXT NOT NULL,
+	url TEXT NOT NULL,
+	config TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS results (
+	id INTEGER PRIMARY KEY,
+	run_id INTEGER NOT NULL REFERENCES runs(id),
+	time TEXT NOT NULL,
+	url TEXT NOT NULL,
+	host TEXT NOT NULL,
+	position INTEGER NOT NULL,
+	status INTEGER NOT NULL,
+	size INTEGER NOT NULL,
+	words INTEGER NOT NULL,
+	lines INTEGER NOT NULL,
+	content_type TEXT NOT NULL,
+	redirect_location TEXT NOT NULL,
+	duration_ms INTEGER NOT NULL,
+	ffufhash TEXT NOT NULL,
+	input TEXT NOT NULL,
+	scraper TEXT NOT NULL,
+	result_file TEXT NOT NULL,
+	request BLOB,
+	response BLOB
+);
+CREATE INDEX IF NOT EXISTS results_host ON results(host);
+CREATE INDEX IF NOT EXISTS results_run ON results(run_id);
+`
+
+// SQLiteOutput stores the results to an sqlite database, along with the configuration of the run. The database can
+// hold the results of any number of runs, to be compared with ffuf query.
+type SQLiteOutput struct {
+	streamOutput
+	mutex  sync.Mutex
+	db     *sql.DB
+	runID  int64
+	bodies bool
+	err    error
+}
+
+// newSQLiteOutput creates the sqlite output provider. The argument is the database file.
+func newSQLiteOutput(conf *ffuf.Config, arg string) (ffuf.OutputProvider, error) {
+	if arg == "" {
+		return nil, fmt.Errorf("The sqlite output provider needs a database file, eg. sqlite:results.db")
+	}
+	return NewSQLiteOutput(arg, conf)
+}
+
+func NewSQLiteOutput(filename string, conf *ffuf.Config) (*SQLiteOutput, error) {
+	db, err := openSQLite(filename)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("Could not create the sqlite database: %s", err)
+	}
+	confJSON, err := json.Marshal(conf)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	res, err := db.Exec("INSERT INTO runs (started, commandline, url, config) VALUES (?, ?, ?, ?)",
+		time.Now().Format(time.RFC3339), conf.CommandLine, conf.Url, string(confJSON))
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("Could not write to the sqlite database: %s", err)
+	}
+	runID, err := res.LastInsertId()
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &SQLiteOutput{db: db, runID: runID, bodies: conf.SqliteBodies}, nil
+}
+
+func openSQLite(filename string) (*sql.DB, error) {
+	db, err := sql.Open("sqlite", filename+"?_pragma=journal_mode(WAL)&_pragma=synchronous(NORMAL)&_pragma=busy_timeout(5000)")
+	if err != nil {
+		return nil, err
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("Could not open the sqlite database %s: %s", filename, err)
+	}
+	// The writes are serialized anyway, and a single connection avoids locking errors
+	db.SetMaxOpenConns(1)
+	return db, nil
+}
+
+func (o *SQLiteOutput) Result(resp ffuf.Response) {
+	res := ffuf.NewResult(&resp)
+	inputs := make(map[string]string)
+	for k, v := range res.Input {
+		inputs[k] = string(v)
+	}
+	inputJSON, _ := json.Marshal(inputs)
+	scraperJSON, _ := json.Marshal(res.ScraperData)
+	host := res.Host
+	if u, err := url.Parse(res.Url); host == "" && err == nil {
+		host = u.Host
+	}
+	var request, response []byte
+	if o.bodies {
+		request = []byte(resp.Request.Raw)
+		response = []byte(resp.Raw)
+		if len(response) == 0 {
+			response = resp.Data
+		}
+	}
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	_, err := o.db.Exec(`INSERT INTO results (run_id, time, url, host, position, status, size, words, lines, content_type,
+		redirect_location, duration_ms, ffufhash, input, scraper, result_file, request, response)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		o.runID, time.Now().Format(time.RFC3339), res.Url, host, res.Position, res.StatusCode, res.ContentLength,
+		res.ContentWords, res.ContentLines, res.ContentType, res.RedirectLocation, res.Duration.Milliseconds(),
+		inputs["FFUFHASH"], string(inputJSON), string(scraperJSON), res.ResultFile, request, response)
+	if err != nil && o.err == nil {
+		o.err = err
+	}
+}
+
+// Finalize marks the run finished and closes the database
+func (o *SQLiteOutput) Finalize() error {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	if o.db == nil {
+		return nil
+	}
+	_, err := o.db.Exec("UPDATE runs SET finished = ? WHERE id = ?", time.Now().Format(time.RFC3339), o.runID)
+	if o.err != nil {
+		err = o.err
+	}
+	if cerr := o.db.Close(); err == nil {
+		err = cerr
+	}
+	o.db = nil
+	if err != nil {
+		return fmt.Errorf("Could not write the results to the sqlite database: %s", err)
+	}
+	return nil
+}
+
+// Close closes the database
+func (o *SQLiteOutput) Close() error {
+	return o.Finalize()
+}
+
+// StoredRun is a run of ffuf in an sqlite database
+type StoredRun struct {
+	ID          int64  `json:"id"`
+	Started     string `json:"started"`
+	Finished    string `json:"finished"`
+	CommandLine string `json:"commandline"`
+	Url         string `json:"url"`
+	Results     int    `json:"results"`
+}
+
+// StoredResult is a result in an sqlite database
+type StoredResult struct {
+	ffuf.Result
+	RunID int64  `json:"run"`
+	Time  string `json:"time"`
+}
+
+// QueryOptions selects the results of QuerySQLite. The status, size, words and lines matchers and filters use the
+// same format as the matchers and filters of ffuf, eg. "200-299,403".
+type QueryOptions struct {
+	Run          int64
+	Host         string
+	Url          string
+	MatchStatus  string
+	MatchSize    string
+	MatchWords   string
+	MatchLines   string
+	FilterStatus string
+	FilterSize   string
+	FilterWords  string
+	FilterLines  string
+	Limit        int
+}
+
+// ListSQLiteRuns returns the runs stored in an sqlite database
+func ListSQLiteRuns(filename string) ([]StoredRun, error) {
+	db, err := openSQLite(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+	rows, err := db.Query(`SELECT runs.id, runs.started, COALESCE(runs.finished, ''), runs.commandline, runs.url,
+		(SELECT COUNT(*) FROM results WHERE results.run_id = runs.id) FROM runs ORDER BY runs.id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	runs := make([]StoredRun, 0)
+	for rows.Next() {
+		var r StoredRun
+		if err := rows.Scan(&r.ID, &r.Started, &r.Finished, &r.CommandLine, &r.Url, &r.Results); err != nil {
+			return nil, err
+		}
+		runs = append(runs, r)
+	}
+	return runs, rows.Err()
+}
+
+// QuerySQLite returns the results stored in an sqlite database that match the query
+func QuerySQLite(filename string, q QueryOptions) ([]StoredResult, error) {
+	where := make([]string, 0)
+	args := make([]interface{}, 0)
+	if q.Run > 0 {
+		where = append(where, "run_id = ?")
+		args = append(args, q.Run)
+	}
+	if q.Host != "" {
+		where = append(where, "host = ?")
+		args = append(args, q.Host)
+	}
+	if q.Url != "" {
+		where = append(where, "instr(url, ?) > 0")
+		args = append(args, q.Url)
+	}
+	for _, cond := range []struct {
+		column string
+		value  string
+		filter bool
+	}{
+		{"status", q.MatchStatus, false},
+		{"size", q.MatchSize, false},
+		{"words", q.MatchWords, false},
+		{"lines", q.MatchLines, false},
+		{"status", q.FilterStatus, true},
+		{"size", q.FilterSize, true},
+		{"words", q.FilterWords, true},
+		{"lines", q.FilterLines, true},
+	} {
+		if cond.value == "" || cond.value == "all" {
+			continue
+		}
+		expr, exprArgs, err := rangeCondition(cond.column, cond.value)
+		if err != nil {
+			return nil, err
+		}
+		if cond.filter {
+			expr = "NOT " + expr
+		}
+		where = append(where, expr)
+		args = append(args, exprArgs...)
+	}
+	query := `SELECT run_id, time, url, host, position, status, size, words, lines, content_type, redirect_location,
+		duration_ms, input, scraper, result_file FROM results`
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += " ORDER BY run_id, id"
+	if q.Limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", q.Limit)
+	}
+
+	db, err := openSQLite(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	results := make([]StoredResult, 0)
+	for rows.Next() {
+		var r StoredResult
+		var durationMs int64
+		var inputJSON, scraperJSON string
+		err := rows.Scan(&r.RunID, &r.Time, &r.Url, &r.Host, &r.Position, &r.StatusCode, &r.ContentLength,
+			&r.ContentWords, &r.ContentLines, &r.ContentType, &r.RedirectLocation, &durationMs, &inputJSON,
+			&scraperJSON, &r.ResultFile)
+		if err != nil {
+			return nil, err
+		}
+		r.Duration = time.Duration(durationMs) * time.Millisecond
+		inputs := make(map[string]string)
+		_ = json.Unmarshal([]byte(inputJSON), &inputs)
+		r.Input = make(map[string][]byte)
+		for k, v := range inputs {
+			r.Input[k] = []byte(v)
+		}
+		_ = json.Unmarshal([]byte(scraperJSON), &r.ScraperData)
+		results = append(results, r)
+	}
+	return results, rows.Err()
+}
+
+// rangeCondition creates an SQL condition from a matcher value, eg. "200-299,403"
+func rangeCondition(column string, value string) (string, []interface{}, error) {
+	conds := make([]string, 0)
+	args := make([]interface{}, 0)
+	for _, part := range strings.Split(value, ",") {
+		vr, err := ffuf.ValueRangeFromString(strings.TrimSpace(part))
+		if err != nil {
+			return "", nil, fmt.Errorf("Invalid %s expression %s: %s", column, value, err)
+		}
+		conds = append(conds, column+" BETWEEN ? AND ?")
+		args = append(args, vr.Min, vr.Max)
+	}
+	return "(" + strings.Join(conds, " OR ") + ")", args, nil
+}
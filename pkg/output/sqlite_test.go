@@ -0,0 +1,124 @@
+package output
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/ffuf/ffuf/v2/pkg/ffuf"
+)
+
+func writeSQLiteRun(t *testing.T, filename string, conf *ffuf.Config, responses ...ffuf.Response) {
+	op, err := NewOutputProviderByName("sqlite:"+filename, conf)
+	if err != nil {
+		t.Fatalf("Failed to create the sqlite output provider: %v", err)
+	}
+	for _, resp := range responses {
+		op.Result(resp)
+	}
+	if err := op.Finalize(); err != nil {
+		t.Fatalf("Failed to finalize the sqlite output provider: %v", err)
+	}
+}
+
+func TestSQLiteOutput(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "results.db")
+	conf := ffuf.NewConfig(context.Background(), func() {})
+	conf.CommandLine = "ffuf -u http://example.com/FUZZ"
+	conf.Url = "http://example.com/FUZZ"
+
+	admin := testResponse("admin", 200)
+	admin.ContentLength = 120
+	admin.ContentWords = 12
+	admin.ScraperData["title"] = []string{"Admin"}
+	admin.Request.Input["FFUFHASH"] = []byte("abc123")
+	login := testResponse("login", 302)
+	login.ContentLength = 0
+	writeSQLiteRun(t, filename, &conf, admin, login)
+	writeSQLiteRun(t, filename, &conf, testResponse("backup", 403))
+
+	runs, err := ListSQLiteRuns(filename)
+	if err != nil {
+		t.Fatalf("Failed to list the runs: %v", err)
+	}
+	if len(runs) != 2 || runs[0].Results != 2 || runs[1].Results != 1 {
+		t.Fatalf("Expected two runs with 2 and 1 results, got %+v", runs)
+	}
+	if runs[0].CommandLine != conf.CommandLine || runs[0].Finished == "" {
+		t.Errorf("Unexpected run details: %+v", runs[0])
+	}
+
+	res, err := QuerySQLite(filename, QueryOptions{})
+	if err != nil {
+		t.Fatalf("Failed to query the results: %v", err)
+	}
+	if len(res) != 3 {
+		t.Fatalf("Expected 3 results, got %d", len(res))
+	}
+	if res[0].Url != "http://example.com/admin" || res[0].ContentLength != 120 || res[0].RunID != runs[0].ID {
+		t.Errorf("Unexpected first result: %+v", res[0])
+	}
+	if string(res[0].Input["FFUFHASH"]) != "abc123" || res[0].ScraperData["title"][0] != "Admin" {
+		t.Errorf("Expected the input and scraper data to be stored, got %v and %v", res[0].Input, res[0].ScraperData)
+	}
+
+	queries := []struct {
+		query    QueryOptions
+		expected int
+	}{
+		{QueryOptions{MatchStatus: "200-299"}, 1},
+		{QueryOptions{MatchStatus: "200,302"}, 2},
+		{QueryOptions{FilterStatus: "403"}, 2},
+		{QueryOptions{MatchSize: "1-1000"}, 1},
+		{QueryOptions{FilterSize: "0"}, 1},
+		{QueryOptions{MatchWords: "12"}, 1},
+		{QueryOptions{MatchLines: "0", FilterStatus: "302"}, 2},
+		{QueryOptions{Run: runs[1].ID}, 1},
+		{QueryOptions{Url: "log"}, 1},
+		{QueryOptions{Host: "example.com"}, 3},
+		{QueryOptions{Host: "example.org"}, 0},
+		{QueryOptions{MatchStatus: "all", Limit: 2}, 2},
+	}
+	for _, q := range queries {
+		res, err := QuerySQLite(filename, q.query)
+		if err != nil {
+			t.Errorf("Query %+v failed: %v", q.query, err)
+			continue
+		}
+		if len(res) != q.expected {
+			t.Errorf("Query %+v returned %d results, expected %d", q.query, len(res), q.expected)
+		}
+	}
+
+	if _, err := QuerySQLite(filename, QueryOptions{MatchStatus: "abc"}); err == nil {
+		t.Errorf("Expected an error for an invalid status expression")
+	}
+}
+
+func TestSQLiteOutputBodies(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "results.db")
+	conf := ffuf.NewConfig(context.Background(), func() {})
+	conf.SqliteBodies = true
+	resp := testResponse("admin", 200)
+	resp.Request.Raw = "GET /admin HTTP/1.1\r\nHost: example.com\r\n\r\n"
+	resp.Data = []byte("hello")
+	writeSQLiteRun(t, filename, &conf, resp)
+
+	db, err := sql.Open("sqlite", filename)
+	if err != nil {
+		t.Fatalf("Failed to open the database: %v", err)
+	}
+	defer db.Close()
+	var request, response []byte
+	if err := db.QueryRow("SELECT request, response FROM results").Scan(&request, &response); err != nil {
+		t.Fatalf("Failed to read the bodies: %v", err)
+	}
+	if string(request) != resp.Request.Raw || string(response) != "hello" {
+		t.Errorf("Unexpected request %q and response %q", request, response)
+	}
+
+	if _, err := NewOutputProviderByName("sqlite", &conf); err == nil {
+		t.Errorf("Expected an error for a missing database file")
+	}
+}
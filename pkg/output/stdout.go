@@ -9,6 +9,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/ffuf/ffuf/v2/pkg/ffuf"
@@ -24,6 +25,8 @@ const (
           \/_/    \/_/   \/___/    \/_/       
 `
 	BANNER_SEP = "________________________________________________"
+	// RESULTFILE_SEPARATOR separates the request from the response in the result files of -od
+	RESULTFILE_SEPARATOR = "\n---- ↑ Request ---- Response ↓ ----\n\n"
 )
 
 type Stdoutput struct {
@@ -31,6 +34,14 @@ type Stdoutput struct {
 	fuzzkeywords   []string
 	Results        []ffuf.Result
 	CurrentResults []ffuf.Result
+	resultsMutex   sync.Mutex
+	stats          JsonStats
+	jsonl          *jsonlFile
+	jsonlFilename  string
+	jsonlMutex     sync.Mutex
+	har            *harFile
+	harFilename    string
+	harMutex       sync.Mutex
 }
 
 func NewStdoutput(conf *ffuf.Config) *Stdoutput {
@@ -43,6 +54,18 @@ func NewStdoutput(conf *ffuf.Config) *Stdoutput {
 		outp.fuzzkeywords = append(outp.fuzzkeywords, ip.Keyword)
 	}
 	sort.Strings(outp.fuzzkeywords)
+	// The jsonl and HAR output files are written as the results are found
+	if conf.OutputFile != "" {
+		switch conf.OutputFormat {
+		case "jsonl":
+			outp.jsonlFilename = conf.OutputFile
+		case "har":
+			outp.harFilename = conf.OutputFile
+		case "all":
+			outp.jsonlFilename = conf.OutputFile + ".jsonl"
+			outp.harFilename = conf.OutputFile + ".har"
+		}
+	}
 	return &outp
 }
 
@@ -50,13 +73,23 @@ func (s *Stdoutput) Banner() {
 	version := strings.ReplaceAll(ffuf.Version(), "<3", fmt.Sprintf("%s<3%s", ANSI_RED, ANSI_CLEAR))
 	fmt.Fprintf(os.Stderr, "%s\n       v%s\n%s\n\n", BANNER_HEADER, version, BANNER_SEP)
 	printOption([]byte("Method"), []byte(s.config.Method))
-	printOption([]byte("URL"), []byte(s.config.Url))
+	if len(s.config.Targets) > 1 {
+		printOption([]byte("Targets"), []byte(fmt.Sprintf("%s (%d targets)", s.config.TargetFile, len(s.config.Targets))))
+	} else {
+		printOption([]byte("URL"), []byte(s.config.Url))
+	}
 
 	// Print wordlists
 	for _, provider := range s.config.InputProviders {
 		if provider.Name == "wordlist" {
 			printOption([]byte("Wordlist"), []byte(provider.Keyword+": "+provider.Value))
 		}
+		if provider.Name == "range" {
+			printOption([]byte("Range"), []byte(provider.Keyword+": "+provider.Value))
+		}
+		if provider.Name == "mask" {
+			printOption([]byte("Mask"), []byte(provider.Keyword+": "+provider.Value))
+		}
 	}
 
 	// Print headers
@@ -87,7 +120,7 @@ func (s *Stdoutput) Banner() {
 
 		if s.config.OutputFormat == "all" {
 			// Actually... append all extensions
-			OutputFile += ".{json,ejson,html,md,csv,ecsv}"
+			OutputFile += ".{json,ejson,html,md,csv,ecsv,sarif,junit.xml,burp.xml,har,jsonl}"
 		}
 
 		printOption([]byte("Output file"), []byte(OutputFile))
@@ -142,26 +175,64 @@ func (s *Stdoutput) Banner() {
 
 // Reset resets the result slice
 func (s *Stdoutput) Reset() {
+	s.resultsMutex.Lock()
+	defer s.resultsMutex.Unlock()
 	s.CurrentResults = make([]ffuf.Result, 0)
 }
 
 // Cycle moves the CurrentResults to Results and resets the results slice
 func (s *Stdoutput) Cycle() {
+	s.resultsMutex.Lock()
+	defer s.resultsMutex.Unlock()
 	s.Results = append(s.Results, s.CurrentResults...)
-	s.Reset()
+	s.CurrentResults = make([]ffuf.Result, 0)
+}
+
+// GetResults returns a copy of the results of the finished queue jobs
+func (s *Stdoutput) GetResults() []ffuf.Result {
+	s.resultsMutex.Lock()
+	defer s.resultsMutex.Unlock()
+	return append([]ffuf.Result{}, s.Results...)
 }
 
-// GetResults returns the result slice
+// SetResults sets the results of the finished queue jobs
+func (s *Stdoutput) SetResults(results []ffuf.Result) {
+	s.resultsMutex.Lock()
+	defer s.resultsMutex.Unlock()
+	s.Results = results
+}
+
+// GetCurrentResults returns a copy of the result slice of the current queue job
 func (s *Stdoutput) GetCurrentResults() []ffuf.Result {
-	return s.CurrentResults
+	s.resultsMutex.Lock()
+	defer s.resultsMutex.Unlock()
+	return append([]ffuf.Result{}, s.CurrentResults...)
 }
 
-// SetResults sets the result slice
+// SetCurrentResults sets the result slice of the current queue job
 func (s *Stdoutput) SetCurrentResults(results []ffuf.Result) {
+	s.resultsMutex.Lock()
+	defer s.resultsMutex.Unlock()
 	s.CurrentResults = results
 }
 
+// allResults returns a copy of the results of the finished and the current queue jobs
+func (s *Stdoutput) allResults() []ffuf.Result {
+	s.resultsMutex.Lock()
+	defer s.resultsMutex.Unlock()
+	res := make([]ffuf.Result, 0, len(s.Results)+len(s.CurrentResults))
+	return append(append(res, s.Results...), s.CurrentResults...)
+}
+
 func (s *Stdoutput) Progress(status ffuf.Progress) {
+	s.stats = JsonStats{Errors: status.ErrorCount, Retries: status.RetryCount, Failed: status.FailedCount}
+	s.jsonlMutex.Lock()
+	if s.jsonl != nil {
+		if err := s.jsonl.FlushIfDue(); err != nil {
+			s.Error(err.Error())
+		}
+	}
+	s.jsonlMutex.Unlock()
 	if s.config.Quiet {
 		// No progress for quiet mode
 		return
@@ -183,6 +254,15 @@ func (s *Stdoutput) Progress(status ffuf.Progress) {
 	secs := dur / time.Second
 
 	fmt.Fprintf(os.Stderr, "%s:: Progress: [%d/%d] :: Job [%d/%d] :: %d req/sec :: Duration: [%d:%02d:%02d] :: Errors: %d ::", TERMINAL_CLEAR_LINE, status.ReqCount, status.ReqTotal, status.QueuePos, status.QueueTotal, reqRate, hours, mins, secs, status.ErrorCount)
+	if status.RetryCount > 0 || status.FailedCount > 0 {
+		fmt.Fprintf(os.Stderr, " Retries: %d :: Failed: %d ::", status.RetryCount, status.FailedCount)
+	}
+	// Show the hosts that are currently rate limited by the adaptive rate limiting
+	for _, h := range status.HostRates {
+		if h.Limit > 0 {
+			fmt.Fprintf(os.Stderr, " %s: %d/%d req/sec ::", h.Host, h.ReqSec, h.Limit)
+		}
+	}
 }
 
 func (s *Stdoutput) Info(infostring string) {
@@ -233,13 +313,13 @@ func (s *Stdoutput) writeToAll(filename string, config *ffuf.Config, res []ffuf.
 	// the suffix to each output file.
 
 	s.config.OutputFile = BaseFilename + ".json"
-	err = writeJSON(s.config.OutputFile, s.config, res)
+	err = writeJSON(s.config.OutputFile, s.config, s.stats, res)
 	if err != nil {
 		s.Error(err.Error())
 	}
 
 	s.config.OutputFile = BaseFilename + ".ejson"
-	err = writeEJSON(s.config.OutputFile, s.config, res)
+	err = writeEJSON(s.config.OutputFile, s.config, s.stats, res)
 	if err != nil {
 		s.Error(err.Error())
 	}
@@ -268,6 +348,36 @@ func (s *Stdoutput) writeToAll(filename string, config *ffuf.Config, res []ffuf.
 		s.Error(err.Error())
 	}
 
+	s.config.OutputFile = BaseFilename + ".sarif"
+	err = writeSARIF(s.config.OutputFile, s.config, res)
+	if err != nil {
+		s.Error(err.Error())
+	}
+
+	s.config.OutputFile = BaseFilename + ".junit.xml"
+	err = writeJUnit(s.config.OutputFile, s.config, res)
+	if err != nil {
+		s.Error(err.Error())
+	}
+
+	s.config.OutputFile = BaseFilename + ".burp.xml"
+	err = writeBurp(s.config.OutputFile, s.config, res)
+	if err != nil {
+		s.Error(err.Error())
+	}
+
+	s.config.OutputFile = BaseFilename + ".har"
+	err = s.saveHAR(s.config.OutputFile, res)
+	if err != nil {
+		s.Error(err.Error())
+	}
+
+	s.config.OutputFile = BaseFilename + ".jsonl"
+	err = s.saveJSONL(s.config.OutputFile, res)
+	if err != nil {
+		s.Error(err.Error())
+	}
+
 	return nil
 
 }
@@ -279,25 +389,162 @@ func (s *Stdoutput) SaveFile(filename, format string) error {
 		s.Info("No results and -or defined, output file not written.")
 		return err
 	}
+	res := s.allResults()
+	if len(s.config.Targets) > 1 {
+		res = groupResultsByHost(res)
+	}
 	switch format {
 	case "all":
-		err = s.writeToAll(filename, s.config, append(s.Results, s.CurrentResults...))
+		err = s.writeToAll(filename, s.config, res)
 	case "json":
-		err = writeJSON(filename, s.config, append(s.Results, s.CurrentResults...))
+		err = writeJSON(filename, s.config, s.stats, res)
 	case "ejson":
-		err = writeEJSON(filename, s.config, append(s.Results, s.CurrentResults...))
+		err = writeEJSON(filename, s.config, s.stats, res)
 	case "html":
-		err = writeHTML(filename, s.config, append(s.Results, s.CurrentResults...))
+		err = writeHTML(filename, s.config, res)
 	case "md":
-		err = writeMarkdown(filename, s.config, append(s.Results, s.CurrentResults...))
+		err = writeMarkdown(filename, s.config, res)
 	case "csv":
-		err = writeCSV(filename, s.config, append(s.Results, s.CurrentResults...), false)
+		err = writeCSV(filename, s.config, res, false)
 	case "ecsv":
-		err = writeCSV(filename, s.config, append(s.Results, s.CurrentResults...), true)
+		err = writeCSV(filename, s.config, res, true)
+	case "jsonl":
+		err = s.saveJSONL(filename, res)
+	case "sarif":
+		err = writeSARIF(filename, s.config, res)
+	case "junit":
+		err = writeJUnit(filename, s.config, res)
+	case "burp":
+		err = writeBurp(filename, s.config, res)
+	case "har":
+		err = s.saveHAR(filename, res)
 	}
 	return err
 }
 
+// saveJSONL finishes the jsonl output file written during the job, or writes a new one
+func (s *Stdoutput) saveJSONL(filename string, res []ffuf.Result) error {
+	s.jsonlMutex.Lock()
+	defer s.jsonlMutex.Unlock()
+	if s.jsonl != nil && s.jsonl.filename == filename {
+		err := s.jsonl.Close(s.stats)
+		s.jsonl = nil
+		s.jsonlFilename = ""
+		return err
+	}
+	return writeJSONL(filename, s.config, s.stats, res)
+}
+
+// saveHAR finishes the HAR output file written during the job, or writes a new one
+func (s *Stdoutput) saveHAR(filename string, res []ffuf.Result) error {
+	s.harMutex.Lock()
+	defer s.harMutex.Unlock()
+	if s.har != nil && s.har.filename == filename {
+		err := s.har.Close()
+		s.har = nil
+		s.harFilename = ""
+		return err
+	}
+	return writeHAR(filename, s.config, res)
+}
+
+// streamHAR appends a response to the HAR output file. The file is created with the first response, along with the
+// results restored from a resume file.
+func (s *Stdoutput) streamHAR(resp ffuf.Response, matched bool) {
+	s.harMutex.Lock()
+	defer s.harMutex.Unlock()
+	if s.harFilename == "" {
+		return
+	}
+	var err error
+	if s.har == nil {
+		s.har, err = newHARFile(s.harFilename, s.config)
+		if err != nil {
+			s.Error(err.Error())
+			s.harFilename = ""
+			return
+		}
+		previous := s.allResults()
+		if matched && len(previous) > 0 {
+			// The current result is written from the response
+			previous = previous[:len(previous)-1]
+		}
+		for _, r := range previous {
+			if err = s.har.WriteEntry(resultToHAR(r, s.config)); err != nil {
+				s.Error(err.Error())
+				return
+			}
+		}
+	}
+	if err = s.har.WriteEntry(responseToHAR(resp, matched)); err != nil {
+		s.Error(err.Error())
+	}
+}
+
+// Record adds a response that is not a result to the HAR output file, when recording all of the requests
+func (s *Stdoutput) Record(resp ffuf.Response) {
+	if s.config.HarAll {
+		s.streamHAR(resp, false)
+	}
+}
+
+// closeStreams closes the output files written during the job that were not finished by SaveFile, eg. because of -or
+func (s *Stdoutput) closeStreams() {
+	s.jsonlMutex.Lock()
+	if s.jsonl != nil {
+		if err := s.jsonl.Close(s.stats); err != nil {
+			s.Error(err.Error())
+		}
+		s.jsonl = nil
+	}
+	s.jsonlMutex.Unlock()
+	s.harMutex.Lock()
+	if s.har != nil {
+		if err := s.har.Close(); err != nil {
+			s.Error(err.Error())
+		}
+		s.har = nil
+	}
+	s.harMutex.Unlock()
+}
+
+// streamResult appends a result to the jsonl output file. The file is created with the first result, along with the
+// results restored from a resume file.
+func (s *Stdoutput) streamResult(res ffuf.Result) {
+	s.jsonlMutex.Lock()
+	defer s.jsonlMutex.Unlock()
+	if s.jsonlFilename == "" {
+		return
+	}
+	var err error
+	if s.jsonl == nil {
+		s.jsonl, err = newJSONLFile(s.jsonlFilename, s.config)
+		if err != nil {
+			s.Error(err.Error())
+			s.jsonlFilename = ""
+			return
+		}
+		for _, r := range s.allResults() {
+			if err = s.jsonl.WriteResult(r); err != nil {
+				break
+			}
+		}
+	} else {
+		err = s.jsonl.WriteResult(res)
+	}
+	if err != nil {
+		s.Error(err.Error())
+	}
+}
+
+// groupResultsByHost sorts the results of a multi-target job by host, keeping the order of the results of a host
+func groupResultsByHost(res []ffuf.Result) []ffuf.Result {
+	grouped := make([]ffuf.Result, len(res))
+	copy(grouped, res)
+	sort.SliceStable(grouped, func(i, j int) bool { return grouped[i].Host < grouped[j].Host })
+	return grouped
+}
+
 // Finalize gets run after all the ffuf jobs are completed
 func (s *Stdoutput) Finalize() error {
 	var err error
@@ -307,6 +554,7 @@ func (s *Stdoutput) Finalize() error {
 			s.Error(err.Error())
 		}
 	}
+	s.closeStreams()
 	if !s.config.Quiet {
 		fmt.Fprintf(os.Stderr, "\n")
 	}
@@ -319,26 +567,12 @@ func (s *Stdoutput) Result(resp ffuf.Response) {
 		resp.ResultFile = s.writeResultToFile(resp)
 	}
 
-	inputs := make(map[string][]byte, len(resp.Request.Input))
-	for k, v := range resp.Request.Input {
-		inputs[k] = v
-	}
-	sResult := ffuf.Result{
-		Input:            inputs,
-		Position:         resp.Request.Position,
-		StatusCode:       resp.StatusCode,
-		ContentLength:    resp.ContentLength,
-		ContentWords:     resp.ContentWords,
-		ContentLines:     resp.ContentLines,
-		ContentType:      resp.ContentType,
-		RedirectLocation: resp.GetRedirectLocation(false),
-		ScraperData:      resp.ScraperData,
-		Url:              resp.Request.Url,
-		Duration:         resp.Time,
-		ResultFile:       resp.ResultFile,
-		Host:             resp.Request.Host,
-	}
+	sResult := ffuf.NewResult(&resp)
+	s.resultsMutex.Lock()
 	s.CurrentResults = append(s.CurrentResults, sResult)
+	s.resultsMutex.Unlock()
+	s.streamResult(sResult)
+	s.streamHAR(resp, true)
 	// Output the result
 	s.PrintResult(sResult)
 }
@@ -355,7 +589,7 @@ func (s *Stdoutput) writeResultToFile(resp ffuf.Response) string {
 			}
 		}
 	}
-	fileContent = fmt.Sprintf("%s\n---- ↑ Request ---- Response ↓ ----\n\n%s", resp.Request.Raw, resp.Raw)
+	fileContent = fmt.Sprintf("%s%s%s", resp.Request.Raw, RESULTFILE_SEPARATOR, resp.Raw)
 
 	// Create file name
 	fileName = fmt.Sprintf("%x", md5.Sum([]byte(fileContent)))
@@ -0,0 +1,186 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ffuf/ffuf/v2/pkg/ffuf"
+)
+
+const (
+	WEBHOOK_QUEUE_SIZE = 1000
+	WEBHOOK_TIMEOUT    = 10 * time.Second
+)
+
+// streamOutput is the base of the output providers that pass on each result as it is found, and ignore the rest of
+// the output
+type streamOutput struct{}
+
+func (s *streamOutput) Banner()                                 {}
+func (s *streamOutput) Progress(status ffuf.Progress)           {}
+func (s *streamOutput) Info(infostring string)                  {}
+func (s *streamOutput) Error(errstring string)                  {}
+func (s *streamOutput) Raw(output string)                       {}
+func (s *streamOutput) Warning(warnstring string)               {}
+func (s *streamOutput) PrintResult(res ffuf.Result)             {}
+func (s *streamOutput) SaveFile(filename, format string) error  { return nil }
+func (s *streamOutput) GetResults() []ffuf.Result               { return nil }
+func (s *streamOutput) SetResults(results []ffuf.Result)        {}
+func (s *streamOutput) GetCurrentResults() []ffuf.Result        { return nil }
+func (s *streamOutput) SetCurrentResults(results []ffuf.Result) {}
+func (s *streamOutput) Reset()                                  {}
+func (s *streamOutput) Cycle()                                  {}
+
+// JSONLOutput writes the results to a file, or to stdout, in the same jsonl format as the jsonl output file
+// (-of jsonl): a header line with the configuration, a line for each result as soon as it is found and a trailer
+// line with the request counters.
+type JSONLOutput struct {
+	streamOutput
+	mutex sync.Mutex
+	jsonl *jsonlFile
+	stats JsonStats
+}
+
+// newJSONLOutput creates the jsonl output provider. The argument is the file to write the results to, or "-" or
+// nothing for stdout.
+func newJSONLOutput(conf *ffuf.Config, arg string) (ffuf.OutputProvider, error) {
+	if arg == "" || arg == "-" {
+		return NewJSONLOutput(os.Stdout, conf)
+	}
+	j, err := newJSONLFile(arg, conf)
+	if err != nil {
+		return nil, fmt.Errorf("Could not open the jsonl output file: %s", err)
+	}
+	return &JSONLOutput{jsonl: j}, nil
+}
+
+// NewJSONLOutput returns a jsonl output provider writing to w, after writing the header line to it
+func NewJSONLOutput(w io.Writer, conf *ffuf.Config) (*JSONLOutput, error) {
+	j, err := newJSONLWriter(w, conf)
+	if err != nil {
+		return nil, err
+	}
+	return &JSONLOutput{jsonl: j}, nil
+}
+
+func (o *JSONLOutput) Result(resp ffuf.Response) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	if o.jsonl == nil {
+		return
+	}
+	// Each line is passed on right away, for the tools reading the results as they come
+	if err := o.jsonl.WriteResult(ffuf.NewResult(&resp)); err == nil {
+		_ = o.jsonl.Flush()
+	}
+}
+
+func (o *JSONLOutput) Progress(status ffuf.Progress) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	o.stats = JsonStats{Errors: status.ErrorCount, Retries: status.RetryCount, Failed: status.FailedCount}
+}
+
+func (o *JSONLOutput) Finalize() error {
+	return o.Close()
+}
+
+// Close writes the trailer line, and closes the output file
+func (o *JSONLOutput) Close() error {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	if o.jsonl == nil {
+		return nil
+	}
+	err := o.jsonl.Close(o.stats)
+	o.jsonl = nil
+	return err
+}
+
+// WebhookOutput sends each result as JSON in a POST request to a URL. The requests are sent in the background, so
+// a slow receiver does not hold up the job until the queue fills up.
+type WebhookOutput struct {
+	streamOutput
+	url     string
+	client  *http.Client
+	queue   chan []byte
+	done    chan struct{}
+	once    sync.Once
+	mutex   sync.Mutex
+	failed  int
+	lastErr error
+}
+
+// newWebhookOutput creates the webhook output provider. The argument is the URL to send the results to.
+func newWebhookOutput(conf *ffuf.Config, arg string) (ffuf.OutputProvider, error) {
+	if !strings.HasPrefix(arg, "http://") && !strings.HasPrefix(arg, "https://") {
+		return nil, fmt.Errorf("The webhook output provider needs an http(s) URL, eg. webhook:https://example.org/hook")
+	}
+	return NewWebhookOutput(arg), nil
+}
+
+func NewWebhookOutput(url string) *WebhookOutput {
+	o := &WebhookOutput{
+		url:    url,
+		client: &http.Client{Timeout: WEBHOOK_TIMEOUT},
+		queue:  make(chan []byte, WEBHOOK_QUEUE_SIZE),
+		done:   make(chan struct{}),
+	}
+	go o.send()
+	return o
+}
+
+func (o *WebhookOutput) send() {
+	defer close(o.done)
+	for body := range o.queue {
+		resp, err := o.client.Post(o.url, "application/json", bytes.NewReader(body))
+		if err == nil {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+			if resp.StatusCode >= 300 {
+				err = fmt.Errorf("status %d", resp.StatusCode)
+			}
+		}
+		if err != nil {
+			o.mutex.Lock()
+			o.failed++
+			o.lastErr = err
+			o.mutex.Unlock()
+		}
+	}
+}
+
+func (o *WebhookOutput) Result(resp ffuf.Response) {
+	body, err := json.Marshal(toJsonResult(ffuf.NewResult(&resp)))
+	if err != nil {
+		return
+	}
+	o.queue <- body
+}
+
+// Finalize waits for the queued results to be sent
+func (o *WebhookOutput) Finalize() error {
+	o.Close()
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	if o.failed > 0 {
+		return fmt.Errorf("Could not send %d results to the webhook %s: %s", o.failed, o.url, o.lastErr)
+	}
+	return nil
+}
+
+// Close stops accepting results, and waits for the queued results to be sent
+func (o *WebhookOutput) Close() error {
+	o.once.Do(func() {
+		close(o.queue)
+	})
+	<-o.done
+	return nil
+}
@@ -0,0 +1,88 @@
+package runner
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/ffuf/ffuf/v2/pkg/ffuf"
+	"github.com/ffuf/ffuf/v2/pkg/scraper"
+)
+
+// CSRFRunner fetches a fresh anti-CSRF token before each request, and replaces the CSRF keyword with it.
+// Every request gets its own token and the cookies set along with it, so concurrent threads never share a token.
+// The token is replaced in a copy of the request, so a retried request fetches a new token too.
+type CSRFRunner struct {
+	ffuf.RunnerProvider
+	config *ffuf.Config
+	rule   *scraper.ScraperRule
+}
+
+// NewCSRFRunner wraps a runner to fetch the token from -csrf-url using the -csrf-rule extraction rule
+func NewCSRFRunner(conf *ffuf.Config, runner ffuf.RunnerProvider) (ffuf.RunnerProvider, error) {
+	ruletype, rule, _ := strings.Cut(conf.CSRFRule, ":")
+	r, err := scraper.NewRule(ruletype, rule)
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSRF token rule (-csrf-rule): %s", err)
+	}
+	return &CSRFRunner{RunnerProvider: runner, config: conf, rule: r}, nil
+}
+
+func (r *CSRFRunner) Execute(req *ffuf.Request) (ffuf.Response, error) {
+	if !requestContains(req, r.config.CSRFKeyword) {
+		return r.RunnerProvider.Execute(req)
+	}
+	tokenreq := ffuf.Request{
+		Method:  "GET",
+		Url:     r.config.CSRFUrl,
+		Headers: make(map[string]string),
+	}
+	for k, v := range req.Headers {
+		switch strings.ToLower(k) {
+		case "host", "content-type", "content-length":
+		default:
+			tokenreq.Headers[k] = v
+		}
+	}
+	tokenresp, err := r.RunnerProvider.Execute(&tokenreq)
+	if err != nil {
+		return ffuf.Response{}, fmt.Errorf("CSRF token request failed: %s", err)
+	}
+	token, ok := r.rule.Value(&tokenresp)
+	if !ok {
+		return ffuf.Response{}, fmt.Errorf("could not extract the CSRF token from %s (status %d)", r.config.CSRFUrl, tokenresp.StatusCode)
+	}
+
+	// The token is usually tied to a cookie set by the same response
+	cookies := make(map[string]string)
+	for _, sc := range tokenresp.Headers["Set-Cookie"] {
+		c, err := http.ParseSetCookie(sc)
+		if err == nil {
+			cookies[c.Name] = c.Value
+		}
+	}
+	tokenized := ffuf.CopyRequest(req)
+	tokenized.Url = strings.ReplaceAll(req.Url, r.config.CSRFKeyword, token)
+	tokenized.Data = []byte(strings.ReplaceAll(string(req.Data), r.config.CSRFKeyword, token))
+	tokenized.Headers = make(map[string]string, len(req.Headers))
+	for h, v := range req.Headers {
+		tokenized.Headers[strings.ReplaceAll(h, r.config.CSRFKeyword, token)] = strings.ReplaceAll(v, r.config.CSRFKeyword, token)
+	}
+	if len(cookies) > 0 {
+		tokenized.Headers["Cookie"] = ffuf.MergeCookies(tokenized.Headers["Cookie"], cookies)
+	}
+	return r.RunnerProvider.Execute(&tokenized)
+}
+
+// requestContains checks if the keyword is found in the url, headers or body of the request
+func requestContains(req *ffuf.Request, keyword string) bool {
+	if strings.Contains(req.Url, keyword) || strings.Contains(string(req.Data), keyword) {
+		return true
+	}
+	for k, v := range req.Headers {
+		if strings.Contains(k, keyword) || strings.Contains(v, keyword) {
+			return true
+		}
+	}
+	return false
+}
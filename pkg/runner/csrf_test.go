@@ -0,0 +1,111 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ffuf/ffuf/v2/pkg/ffuf"
+)
+
+// startCSRFServer hands out a new token bound to a cookie on each GET, and accepts each token only once
+func startCSRFServer(t *testing.T) *httptest.Server {
+	var counter int64
+	var mutex sync.Mutex
+	tokens := make(map[string]string)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			n := atomic.AddInt64(&counter, 1)
+			mutex.Lock()
+			tokens[fmt.Sprintf("sid%d", n)] = fmt.Sprintf("token%d", n)
+			mutex.Unlock()
+			http.SetCookie(w, &http.Cookie{Name: "sid", Value: fmt.Sprintf("sid%d", n)})
+			fmt.Fprintf(w, `<form><input type="hidden" name="csrf" value="token%d"></form>`, n)
+			return
+		}
+		sid, err := r.Cookie("sid")
+		_ = r.ParseForm()
+		mutex.Lock()
+		defer mutex.Unlock()
+		if err != nil || tokens[sid.Value] == "" || tokens[sid.Value] != r.PostForm.Get("csrf") {
+			w.WriteHeader(403)
+			return
+		}
+		delete(tokens, sid.Value)
+		fmt.Fprint(w, "ok")
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestCSRFRunner(t *testing.T) {
+	srv := startCSRFServer(t)
+	for _, rule := range []string{`regexp:name="csrf" value="([^"]+)"`, "query:input[name=csrf]"} {
+		conf := ffuf.NewConfig(context.Background(), func() {})
+		conf.CSRFUrl = srv.URL + "/form"
+		conf.CSRFRule = rule
+		conf.Timeout = 5
+		r, _ := NewRunnerByName("http", &conf, false)
+		if _, ok := r.(*CSRFRunner); !ok {
+			t.Fatalf("Expected CSRFRunner when -csrf-url is set, got %T", r)
+		}
+
+		var wg sync.WaitGroup
+		var forbidden int64
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				basereq := ffuf.Request{
+					Method:  "POST",
+					Url:     srv.URL + "/submit",
+					Headers: map[string]string{"Content-Type": "application/x-www-form-urlencoded"},
+					Data:    []byte("user=FUZZ&csrf=CSRF"),
+				}
+				req, _ := r.Prepare(map[string][]byte{"FUZZ": []byte(fmt.Sprintf("user%d", i))}, &basereq)
+				resp, err := r.Execute(&req)
+				if err != nil || resp.StatusCode != 200 {
+					atomic.AddInt64(&forbidden, 1)
+				}
+				if err == nil && strings.Contains(string(resp.Request.Data), "CSRF") {
+					t.Errorf("Expected the CSRF keyword to be replaced, got %s", resp.Request.Data)
+				}
+				if !strings.Contains(string(req.Data), "CSRF") {
+					t.Errorf("Expected the request of the caller to be left unchanged, got %s", req.Data)
+				}
+			}(i)
+		}
+		wg.Wait()
+		if forbidden > 0 {
+			t.Errorf("Rule %s: expected every request to have its own valid token, %d were rejected", rule, forbidden)
+		}
+	}
+
+	// Sending the same request again, like a retry does, uses a new token
+	conf := ffuf.NewConfig(context.Background(), func() {})
+	conf.CSRFUrl = srv.URL + "/form"
+	conf.CSRFRule = "query:input[name=csrf]"
+	r, _ := NewRunnerByName("http", &conf, false)
+	req := ffuf.Request{Method: "POST", Url: srv.URL + "/submit", Headers: map[string]string{"Content-Type": "application/x-www-form-urlencoded"}, Data: []byte("csrf=CSRF")}
+	for i := 0; i < 2; i++ {
+		if resp, err := r.Execute(&req); err != nil || resp.StatusCode != 200 {
+			t.Errorf("Expected the resent request to get a fresh token, got status %d and error %v", resp.StatusCode, err)
+		}
+	}
+
+	conf.CSRFRule = "regexp:nomatch=([a-z]+)"
+	r, _ = NewRunnerByName("http", &conf, false)
+	if _, err := r.Execute(&req); err == nil {
+		t.Errorf("Expected an error when the token is not found")
+	}
+
+	conf.CSRFRule = "regexp:([a-z]+"
+	if _, err := NewRunnerByName("http", &conf, false); err == nil {
+		t.Errorf("Expected an error for an invalid token rule")
+	}
+}
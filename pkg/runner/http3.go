@@ -0,0 +1,49 @@
+package runner
+
+import (
+	"crypto/tls"
+	"net/http"
+	"time"
+
+	"github.com/ffuf/ffuf/v2/pkg/ffuf"
+
+	"github.com/quic-go/quic-go"
+	"github.com/quic-go/quic-go/http3"
+)
+
+// HTTP3Runner executes the requests over HTTP/3 (QUIC). Request preparation, timing, response
+// decompression and raw dumps are shared with SimpleRunner, only the transport differs.
+type HTTP3Runner struct {
+	SimpleRunner
+}
+
+func NewHTTP3Runner(conf *ffuf.Config) ffuf.RunnerProvider {
+	var h3runner HTTP3Runner
+	cert := []tls.Certificate{}
+
+	if conf.ClientCert != "" && conf.ClientKey != "" {
+		tmp, _ := tls.LoadX509KeyPair(conf.ClientCert, conf.ClientKey)
+		cert = []tls.Certificate{tmp}
+	}
+
+	h3runner.config = conf
+	h3runner.client = &http.Client{
+		CheckRedirect: func(req *http.Request, via []*http.Request) error { return http.ErrUseLastResponse },
+		Timeout:       time.Duration(time.Duration(conf.Timeout) * time.Second),
+		Transport: &http3.Transport{
+			TLSClientConfig: &tls.Config{
+				InsecureSkipVerify: true,
+				ServerName:         conf.SNI,
+				Certificates:       cert,
+			},
+			QUICConfig: &quic.Config{
+				HandshakeIdleTimeout: time.Duration(time.Duration(conf.Timeout) * time.Second),
+			},
+		}}
+
+	if conf.FollowRedirects {
+		h3runner.client.CheckRedirect = nil
+	}
+	h3runner.setupCookieJar()
+	return &h3runner
+}
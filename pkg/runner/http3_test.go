@@ -0,0 +1,124 @@
+package runner
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ffuf/ffuf/v2/pkg/ffuf"
+
+	"github.com/quic-go/quic-go/http3"
+)
+
+func testCertificate(t *testing.T) tls.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	template := x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Failed to create certificate: %v", err)
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
+// startHTTP3Server starts a local HTTP/3 test server and returns its base url
+func startHTTP3Server(t *testing.T, handler http.Handler) string {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen on UDP: %v", err)
+	}
+	server := &http3.Server{
+		Handler:   handler,
+		TLSConfig: http3.ConfigureTLSConfig(&tls.Config{Certificates: []tls.Certificate{testCertificate(t)}}),
+	}
+	go func() {
+		_ = server.Serve(conn)
+	}()
+	t.Cleanup(func() {
+		server.Close()
+		conn.Close()
+	})
+	return "https://" + conn.LocalAddr().String()
+}
+
+func TestHTTP3RunnerExecute(t *testing.T) {
+	baseurl := startHTTP3Server(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Proto", r.Proto)
+		if r.URL.Path == "/gzip" {
+			w.Header().Set("Content-Encoding", "gzip")
+			gz := gzip.NewWriter(w)
+			_, _ = gz.Write([]byte("compressed response\nbody"))
+			gz.Close()
+			return
+		}
+		w.WriteHeader(http.StatusTeapot)
+		_, _ = w.Write([]byte("path " + r.URL.Path + " agent " + r.UserAgent()))
+	}))
+
+	conf := ffuf.NewConfig(context.Background(), func() {})
+	conf.Http3 = true
+	conf.OutputDirectory = t.TempDir()
+	r, _ := NewRunnerByName("http", &conf, false)
+	if _, ok := r.(*HTTP3Runner); !ok {
+		t.Fatalf("Expected HTTP3Runner when HTTP/3 is enabled, got %T", r)
+	}
+
+	basereq := ffuf.Request{Method: "GET", Url: baseurl + "/FUZZ", Headers: map[string]string{}}
+	req, err := r.Prepare(map[string][]byte{"FUZZ": []byte("admin")}, &basereq)
+	if err != nil {
+		t.Fatalf("Failed to prepare request: %v", err)
+	}
+	resp, err := r.Execute(&req)
+	if err != nil {
+		t.Fatalf("Failed to execute request: %v", err)
+	}
+	if resp.StatusCode != http.StatusTeapot {
+		t.Errorf("Expected status code %d, got %d", http.StatusTeapot, resp.StatusCode)
+	}
+	if proto := resp.Headers["X-Proto"]; len(proto) != 1 || proto[0] != "HTTP/3.0" {
+		t.Errorf("Expected the request to be sent over HTTP/3, server saw %v", proto)
+	}
+	if !strings.HasPrefix(string(resp.Data), "path /admin agent Fuzz Faster U Fool") {
+		t.Errorf("Unexpected response body: %q", resp.Data)
+	}
+	if resp.ContentWords != int64(len(strings.Split(string(resp.Data), " "))) || resp.ContentLength != int64(len(resp.Data)) {
+		t.Errorf("Unexpected response size: %d words, %d bytes", resp.ContentWords, resp.ContentLength)
+	}
+	if resp.Time <= 0 {
+		t.Errorf("Expected time to first byte to be recorded, got %s", resp.Time)
+	}
+	if !strings.Contains(resp.Raw, "HTTP/3.0 418") || !strings.HasPrefix(resp.Request.Raw, "GET /admin") {
+		t.Errorf("Expected raw request and response dumps, got %q and %q", resp.Request.Raw, resp.Raw)
+	}
+
+	basereq.Headers["Accept-Encoding"] = "gzip"
+	req, _ = r.Prepare(map[string][]byte{"FUZZ": []byte("gzip")}, &basereq)
+	resp, err = r.Execute(&req)
+	if err != nil {
+		t.Fatalf("Failed to execute request: %v", err)
+	}
+	if !bytes.Equal(resp.Data, []byte("compressed response\nbody")) || resp.ContentLines != 2 {
+		t.Errorf("Expected decompressed response body, got %q", resp.Data)
+	}
+}
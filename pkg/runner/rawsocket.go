@@ -0,0 +1,291 @@
+package runner
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"net/textproto"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ffuf/ffuf/v2/pkg/ffuf"
+)
+
+// RawSocketRunner writes the request template from the request file (-request) to a TCP or TLS socket
+// byte-for-byte, only replacing the keywords. Header casing, ordering, duplicates, line endings and
+// Content-Length are left untouched, and the response is parsed without net/http.
+type RawSocketRunner struct {
+	config  *ffuf.Config
+	dialer  *net.Dialer
+	tlsconf *tls.Config
+}
+
+func NewRawSocketRunner(conf *ffuf.Config) ffuf.RunnerProvider {
+	var rawrunner RawSocketRunner
+	cert := []tls.Certificate{}
+
+	if conf.ClientCert != "" && conf.ClientKey != "" {
+		tmp, _ := tls.LoadX509KeyPair(conf.ClientCert, conf.ClientKey)
+		cert = []tls.Certificate{tmp}
+	}
+
+	rawrunner.config = conf
+	rawrunner.dialer = &net.Dialer{
+		Timeout: time.Duration(time.Duration(conf.Timeout) * time.Second),
+	}
+	rawrunner.tlsconf = &tls.Config{
+		InsecureSkipVerify: true,
+		MinVersion:         tls.VersionTLS10,
+		Renegotiation:      tls.RenegotiateOnceAsClient,
+		ServerName:         conf.SNI,
+		Certificates:       cert,
+	}
+	return &rawrunner
+}
+
+func (r *RawSocketRunner) Prepare(input map[string][]byte, basereq *ffuf.Request) (ffuf.Request, error) {
+	req := ffuf.CopyRequest(basereq)
+
+	// The fields are populated for output and matching, the template is what gets sent
+	for keyword, inputitem := range input {
+		req.Method = strings.ReplaceAll(req.Method, keyword, string(inputitem))
+		headers := make(map[string]string, len(req.Headers))
+		for h, v := range req.Headers {
+			headers[strings.ReplaceAll(h, keyword, string(inputitem))] = strings.ReplaceAll(v, keyword, string(inputitem))
+		}
+		req.Headers = headers
+		req.Url = strings.ReplaceAll(req.Url, keyword, string(inputitem))
+		req.Data = []byte(strings.ReplaceAll(string(req.Data), keyword, string(inputitem)))
+	}
+
+	req.Input = input
+	return req, nil
+}
+
+// payload returns the request template with the keywords replaced with the request inputs
+func (r *RawSocketRunner) payload(req *ffuf.Request) []byte {
+	payload := r.config.RequestTemplate
+	for keyword, inputitem := range req.Input {
+		payload = bytes.ReplaceAll(payload, []byte(keyword), inputitem)
+	}
+	return payload
+}
+
+// dial opens a connection to the host of the request url, using TLS for https
+func (r *RawSocketRunner) dial(req *ffuf.Request) (net.Conn, error) {
+	u, err := url.Parse(req.Url)
+	if err != nil {
+		return nil, err
+	}
+	addr := u.Host
+	if u.Port() == "" {
+		if u.Scheme == "https" {
+			addr = net.JoinHostPort(u.Hostname(), "443")
+		} else {
+			addr = net.JoinHostPort(u.Hostname(), "80")
+		}
+	}
+	req.Host = u.Host
+	if u.Scheme != "https" {
+		return r.dialer.DialContext(r.config.Context, "tcp", addr)
+	}
+	tlsconf := r.tlsconf.Clone()
+	if tlsconf.ServerName == "" {
+		tlsconf.ServerName = u.Hostname()
+	}
+	tlsdialer := &tls.Dialer{NetDialer: r.dialer, Config: tlsconf}
+	return tlsdialer.DialContext(r.config.Context, "tcp", addr)
+}
+
+func (r *RawSocketRunner) Execute(req *ffuf.Request) (ffuf.Response, error) {
+	payload := r.payload(req)
+	conn, err := r.dial(req)
+	if err != nil {
+		return ffuf.Response{}, err
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(time.Duration(r.config.Timeout) * time.Second))
+
+	if _, err := conn.Write(payload); err != nil {
+		return ffuf.Response{}, err
+	}
+	// begin the timer after the request is fully written
+	start := time.Now()
+
+	reader := bufio.NewReader(conn)
+	if _, err := reader.Peek(1); err != nil {
+		return ffuf.Response{}, err
+	}
+	firstByteTime := time.Since(start)
+
+	var rawresp bytes.Buffer
+	resp, err := readRawResponse(io.TeeReader(reader, &rawresp), req, r.config.IgnoreBody)
+	if err != nil {
+		return ffuf.Response{}, err
+	}
+	if len(r.config.OutputDirectory) > 0 || r.config.SqliteBodies {
+		resp.Request.Raw = string(payload)
+		resp.Raw = rawresp.String()
+	}
+	resp.Time = firstByteTime
+	return resp, nil
+}
+
+func (r *RawSocketRunner) Dump(req *ffuf.Request) ([]byte, error) {
+	return r.payload(req), nil
+}
+
+// readRawResponse parses a HTTP/1.x response from the reader
+func readRawResponse(src io.Reader, req *ffuf.Request, ignoreBody bool) (ffuf.Response, error) {
+	var resp ffuf.Response
+	reader := bufio.NewReader(src)
+	resp.Request = req
+	resp.ScraperData = make(map[string][]string)
+	for {
+		statusline, err := readRawLine(reader)
+		if err != nil {
+			return resp, fmt.Errorf("could not read response status line: %s", err)
+		}
+		parts := strings.SplitN(statusline, " ", 3)
+		if len(parts) < 2 || !strings.HasPrefix(parts[0], "HTTP/") {
+			return resp, fmt.Errorf("malformed response status line: %q", statusline)
+		}
+		status, err := strconv.Atoi(parts[1])
+		if err != nil {
+			return resp, fmt.Errorf("malformed response status code: %q", statusline)
+		}
+		resp.StatusCode = int64(status)
+		resp.Headers, err = readRawHeaders(reader)
+		if err != nil {
+			return resp, err
+		}
+		// Skip informational responses, like 100 Continue, the final response follows them
+		if status < 100 || status >= 200 || status == 101 {
+			break
+		}
+	}
+	resp.ContentType = headerValue(resp.Headers, "Content-Type")
+
+	var body io.Reader
+	size, sizeErr := strconv.Atoi(headerValue(resp.Headers, "Content-Length"))
+	if strings.EqualFold(req.Method, "HEAD") || resp.StatusCode == 204 || resp.StatusCode == 304 || resp.StatusCode == 101 {
+		body = bytes.NewReader([]byte{})
+	} else if strings.Contains(strings.ToLower(headerValue(resp.Headers, "Transfer-Encoding")), "chunked") {
+		body = &chunkedReader{r: reader}
+	} else if sizeErr == nil {
+		resp.ContentLength = int64(size)
+		if ignoreBody || size > MAX_DOWNLOAD_SIZE {
+			resp.Cancelled = true
+			return resp, nil
+		}
+		body = io.LimitReader(reader, int64(size))
+	} else {
+		// Read until the server closes the connection
+		body = reader
+	}
+
+	bodyReader := decompressBody(headerValue(resp.Headers, "Content-Encoding"), io.NopCloser(io.LimitReader(body, MAX_DOWNLOAD_SIZE)))
+	if respbody, err := io.ReadAll(bodyReader); err == nil || len(respbody) > 0 {
+		resp.ContentLength = int64(len(string(respbody)))
+		resp.Data = respbody
+	}
+
+	wordsSize := len(strings.Split(string(resp.Data), " "))
+	linesSize := len(strings.Split(string(resp.Data), "\n"))
+	resp.ContentWords = int64(wordsSize)
+	resp.ContentLines = int64(linesSize)
+	return resp, nil
+}
+
+// readRawHeaders reads the header lines until an empty line. Folded header lines are joined to the previous value.
+func readRawHeaders(reader *bufio.Reader) (map[string][]string, error) {
+	headers := make(map[string][]string)
+	lastKey := ""
+	for {
+		line, err := readRawLine(reader)
+		if err != nil {
+			return headers, fmt.Errorf("could not read response headers: %s", err)
+		}
+		if line == "" {
+			return headers, nil
+		}
+		if (line[0] == ' ' || line[0] == '\t') && lastKey != "" {
+			values := headers[lastKey]
+			values[len(values)-1] += " " + strings.TrimSpace(line)
+			continue
+		}
+		p := strings.SplitN(line, ":", 2)
+		if len(p) != 2 {
+			continue
+		}
+		lastKey = textproto.CanonicalMIMEHeaderKey(strings.TrimSpace(p[0]))
+		headers[lastKey] = append(headers[lastKey], strings.TrimSpace(p[1]))
+	}
+}
+
+// readRawLine reads a single line, accepting both CRLF and bare LF line endings
+func readRawLine(reader *bufio.Reader) (string, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		return "", err
+	}
+	line = strings.TrimSuffix(line, "\n")
+	return strings.TrimSuffix(line, "\r"), nil
+}
+
+func headerValue(headers map[string][]string, key string) string {
+	if values, ok := headers[key]; ok && len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// chunkedReader decodes a body using chunked transfer encoding
+type chunkedReader struct {
+	r         *bufio.Reader
+	remaining int64
+	done      bool
+}
+
+func (c *chunkedReader) Read(p []byte) (int, error) {
+	for c.remaining == 0 {
+		if c.done {
+			return 0, io.EOF
+		}
+		line, err := readRawLine(c.r)
+		if err != nil {
+			return 0, err
+		}
+		// Ignore chunk extensions
+		line = strings.TrimSpace(strings.SplitN(line, ";", 2)[0])
+		size, err := strconv.ParseInt(line, 16, 64)
+		if err != nil || size < 0 {
+			return 0, fmt.Errorf("malformed chunk size: %q", line)
+		}
+		if size == 0 {
+			c.done = true
+			// Consume the trailers
+			_, _ = readRawHeaders(c.r)
+			return 0, io.EOF
+		}
+		c.remaining = size
+	}
+	if int64(len(p)) > c.remaining {
+		p = p[:c.remaining]
+	}
+	n, err := c.r.Read(p)
+	c.remaining -= int64(n)
+	if c.remaining == 0 && err == nil {
+		// Consume the CRLF following the chunk data
+		_, err = readRawLine(c.r)
+	}
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	return n, err
+}
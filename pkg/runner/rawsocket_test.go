@@ -0,0 +1,114 @@
+package runner
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/ffuf/ffuf/v2/pkg/ffuf"
+)
+
+// startRawServer accepts a single connection, records the bytes received until the end of
+// the request template and answers with the given raw response
+func startRawServer(t *testing.T, terminator string, response string) (string, <-chan string) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen on TCP: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	received := make(chan string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		reader := bufio.NewReader(conn)
+		data := ""
+		for !strings.HasSuffix(data, terminator) {
+			b, err := reader.ReadByte()
+			if err != nil {
+				break
+			}
+			data += string(b)
+		}
+		received <- data
+		_, _ = conn.Write([]byte(response))
+	}()
+	return ln.Addr().String(), received
+}
+
+func TestRawSocketRunnerSendsVerbatim(t *testing.T) {
+	template := "GET /FUZZ HTTP/1.1\r\nhost: HOST\nX-Dup: 1\r\nX-Dup: 2\r\nX-Folded: a\r\n b\r\nContent-Length: 999\r\n\r\nEND"
+	response := "HTTP/1.1 100 Continue\r\n\r\n" +
+		"HTTP/1.1 200 OK\r\nContent-Type: text/plain\r\nX-Folded: first\r\n\tsecond\r\nset-cookie: a=1\r\nSet-Cookie: b=2\r\nTransfer-Encoding: chunked\r\n\r\n" +
+		"6\r\nhello \r\n10;ext=1\r\nchunked\nresponse\r\n0\r\n\r\n"
+	addr, received := startRawServer(t, "END", response)
+
+	conf := ffuf.NewConfig(context.Background(), func() {})
+	conf.RawSocket = true
+	conf.RequestTemplate = []byte(strings.ReplaceAll(template, "HOST", addr))
+	conf.OutputDirectory = t.TempDir()
+	r, _ := NewRunnerByName("http", &conf, false)
+	if _, ok := r.(*RawSocketRunner); !ok {
+		t.Fatalf("Expected RawSocketRunner when raw socket mode is enabled, got %T", r)
+	}
+
+	basereq := ffuf.Request{Method: "GET", Url: "http://" + addr + "/FUZZ", Headers: map[string]string{"Host": addr}}
+	req, _ := r.Prepare(map[string][]byte{"FUZZ": []byte("admin")}, &basereq)
+	resp, err := r.Execute(&req)
+	if err != nil {
+		t.Fatalf("Failed to execute request: %v", err)
+	}
+
+	expected := strings.ReplaceAll(strings.ReplaceAll(template, "HOST", addr), "FUZZ", "admin")
+	if sent := <-received; sent != expected {
+		t.Errorf("Expected the request to be sent verbatim as %q, got %q", expected, sent)
+	}
+	if req.Url != "http://"+addr+"/admin" {
+		t.Errorf("Expected keywords to be replaced in request url, got %s", req.Url)
+	}
+	if resp.StatusCode != 200 || resp.ContentType != "text/plain" {
+		t.Errorf("Expected status 200 with text/plain content, got %d %s", resp.StatusCode, resp.ContentType)
+	}
+	if len(resp.Headers["Set-Cookie"]) != 2 || resp.Headers["X-Folded"][0] != "first second" {
+		t.Errorf("Response headers were not parsed correctly: %v", resp.Headers)
+	}
+	if string(resp.Data) != "hello chunked\nresponse" {
+		t.Errorf("Expected chunked body to be decoded, got %q", resp.Data)
+	}
+	if resp.ContentLength != 22 || resp.ContentWords != 2 || resp.ContentLines != 2 {
+		t.Errorf("Unexpected response size: %d bytes, %d words, %d lines", resp.ContentLength, resp.ContentWords, resp.ContentLines)
+	}
+	if resp.Request.Raw != expected || !strings.HasPrefix(resp.Raw, "HTTP/1.1 100 Continue") {
+		t.Errorf("Expected raw request and response dumps, got %q and %q", resp.Request.Raw, resp.Raw)
+	}
+}
+
+func TestReadRawResponse(t *testing.T) {
+	req := ffuf.Request{Method: "GET"}
+	resp, err := readRawResponse(strings.NewReader("HTTP/1.0 404 Not Found\nContent-Length: 5\n\nshort and more"), &req, false)
+	if err != nil {
+		t.Fatalf("Failed to read response: %v", err)
+	}
+	if resp.StatusCode != 404 || string(resp.Data) != "short" {
+		t.Errorf("Expected 404 with Content-Length limited body, got %d %q", resp.StatusCode, resp.Data)
+	}
+
+	resp, err = readRawResponse(strings.NewReader("HTTP/1.1 200 OK\r\n\r\nread until close"), &req, false)
+	if err != nil || string(resp.Data) != "read until close" {
+		t.Errorf("Expected body to be read until connection close, got %q (%v)", resp.Data, err)
+	}
+
+	resp, err = readRawResponse(strings.NewReader("HTTP/1.1 200 OK\r\nContent-Length: 10\r\n\r\n0123456789"), &req, true)
+	if err != nil || !resp.Cancelled || resp.ContentLength != 10 {
+		t.Errorf("Expected body download to be cancelled with -ignore-body, got %v (%v)", resp, err)
+	}
+
+	_, err = readRawResponse(strings.NewReader("garbage\r\n\r\n"), &req, false)
+	if err == nil {
+		t.Errorf("Expected an error for a malformed status line")
+	}
+}
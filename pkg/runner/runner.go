@@ -4,7 +4,18 @@ import (
 	"github.com/ffuf/ffuf/v2/pkg/ffuf"
 )
 
-func NewRunnerByName(name string, conf *ffuf.Config, replay bool) ffuf.RunnerProvider {
-	// We have only one Runner at the moment
-	return NewSimpleRunner(conf, replay)
+func NewRunnerByName(name string, conf *ffuf.Config, replay bool) (ffuf.RunnerProvider, error) {
+	var runner ffuf.RunnerProvider
+	// Replayed requests are sent through a HTTP proxy, which HTTP/3 can not be used with
+	if conf.Http3 && !replay {
+		runner = NewHTTP3Runner(conf)
+	} else if conf.RawSocket && !replay {
+		runner = NewRawSocketRunner(conf)
+	} else {
+		runner = NewSimpleRunner(conf, replay)
+	}
+	if conf.CSRFUrl != "" {
+		return NewCSRFRunner(conf, runner)
+	}
+	return runner, nil
 }
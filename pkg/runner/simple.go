@@ -9,6 +9,7 @@ import (
 	"io"
 	"net"
 	"net/http"
+	"net/http/cookiejar"
 	"net/http/httptrace"
 	"net/http/httputil"
 	"net/textproto"
@@ -26,8 +27,9 @@ import (
 const MAX_DOWNLOAD_SIZE = 5242880
 
 type SimpleRunner struct {
-	config *ffuf.Config
-	client *http.Client
+	config  *ffuf.Config
+	client  *http.Client
+	clients chan *http.Client
 }
 
 func NewSimpleRunner(conf *ffuf.Config, replay bool) ffuf.RunnerProvider {
@@ -79,9 +81,56 @@ func NewSimpleRunner(conf *ffuf.Config, replay bool) ffuf.RunnerProvider {
 	if conf.FollowRedirects {
 		simplerunner.client.CheckRedirect = nil
 	}
+	simplerunner.setupCookieJar()
 	return &simplerunner
 }
 
+// setupCookieJar adds a cookie jar to the client when -cookie-jar is in use. In per-thread mode each concurrent
+// request takes a client with its own jar from a pool of -t clients sharing the same transport.
+func (r *SimpleRunner) setupCookieJar() {
+	if r.config.CookieJar == "" {
+		return
+	}
+	seed := make([]*http.Cookie, 0)
+	if r.config.CookieJarFile != "" {
+		// The file has been validated when parsing the configuration
+		seed, _ = ffuf.ReadCookieFile(r.config.CookieJarFile)
+	}
+	if r.config.CookieJar == "shared" {
+		r.client.Jar = newCookieJar(seed)
+		return
+	}
+	threads := r.config.Threads
+	if threads < 1 {
+		threads = 1
+	}
+	r.clients = make(chan *http.Client, threads)
+	for i := 0; i < threads; i++ {
+		client := *r.client
+		client.Jar = newCookieJar(seed)
+		r.clients <- &client
+	}
+}
+
+// newCookieJar creates a cookie jar containing the cookies read from a cookies.txt file
+func newCookieJar(seed []*http.Cookie) http.CookieJar {
+	jar, _ := cookiejar.New(nil)
+	for _, c := range seed {
+		cookie := *c
+		scheme := "http"
+		if cookie.Secure {
+			scheme = "https"
+		}
+		host := strings.TrimPrefix(cookie.Domain, ".")
+		if !strings.HasPrefix(cookie.Domain, ".") {
+			// host-only cookie
+			cookie.Domain = ""
+		}
+		jar.SetCookies(&url.URL{Scheme: scheme, Host: host, Path: cookie.Path}, []*http.Cookie{&cookie})
+	}
+	return jar
+}
+
 func (r *SimpleRunner) Prepare(input map[string][]byte, basereq *ffuf.Request) (ffuf.Request, error) {
 	req := ffuf.CopyRequest(basereq)
 
@@ -146,11 +195,17 @@ func (r *SimpleRunner) Execute(req *ffuf.Request) (ffuf.Response, error) {
 		httpreq.Header.Set(k, v)
 	}
 
-	if len(r.config.OutputDirectory) > 0 {
-		rawreq, _ = httputil.DumpRequestOut(httpreq, true)
+	client := r.client
+	if r.clients != nil {
+		client = <-r.clients
+		defer func() { r.clients <- client }()
 	}
 
-	httpresp, err := r.client.Do(httpreq)
+	if len(r.config.OutputDirectory) > 0 || r.config.SqliteBodies {
+		rawreq, _ = dumpRequest(httpreq, client.Jar)
+	}
+
+	httpresp, err := client.Do(httpreq)
 	if err != nil {
 		return ffuf.Response{}, err
 	}
@@ -168,33 +223,12 @@ func (r *SimpleRunner) Execute(req *ffuf.Request) (ffuf.Response, error) {
 		}
 	}
 
-	if len(r.config.OutputDirectory) > 0 {
+	if len(r.config.OutputDirectory) > 0 || r.config.SqliteBodies {
 		rawresp, _ := httputil.DumpResponse(httpresp, true)
 		resp.Request.Raw = string(rawreq)
 		resp.Raw = string(rawresp)
 	}
-	var bodyReader io.ReadCloser
-	if httpresp.Header.Get("Content-Encoding") == "gzip" {
-		bodyReader, err = gzip.NewReader(httpresp.Body)
-		if err != nil {
-			// fallback to raw data
-			bodyReader = httpresp.Body
-		}
-	} else if httpresp.Header.Get("Content-Encoding") == "br" {
-		bodyReader = io.NopCloser(brotli.NewReader(httpresp.Body))
-		if err != nil {
-			// fallback to raw data
-			bodyReader = httpresp.Body
-		}
-	} else if httpresp.Header.Get("Content-Encoding") == "deflate" {
-		bodyReader = flate.NewReader(httpresp.Body)
-		if err != nil {
-			// fallback to raw data
-			bodyReader = httpresp.Body
-		}
-	} else {
-		bodyReader = httpresp.Body
-	}
+	bodyReader := decompressBody(httpresp.Header.Get("Content-Encoding"), httpresp.Body)
 
 	if respbody, err := io.ReadAll(bodyReader); err == nil {
 		resp.ContentLength = int64(len(string(respbody)))
@@ -209,6 +243,39 @@ func (r *SimpleRunner) Execute(req *ffuf.Request) (ffuf.Response, error) {
 	return resp, nil
 }
 
+// dumpRequest dumps the request as it is going to be sent, including the cookies the client adds from its cookie jar
+func dumpRequest(httpreq *http.Request, jar http.CookieJar) ([]byte, error) {
+	if jar == nil || httpreq.GetBody == nil {
+		return httputil.DumpRequestOut(httpreq, true)
+	}
+	dumpreq := httpreq.Clone(httpreq.Context())
+	dumpreq.Body, _ = httpreq.GetBody()
+	for _, c := range jar.Cookies(httpreq.URL) {
+		dumpreq.AddCookie(c)
+	}
+	return httputil.DumpRequestOut(dumpreq, true)
+}
+
+// decompressBody returns a reader for the response body decoded according to its Content-Encoding
+func decompressBody(encoding string, body io.ReadCloser) io.ReadCloser {
+	var bodyReader io.ReadCloser
+	var err error
+	if encoding == "gzip" {
+		bodyReader, err = gzip.NewReader(body)
+		if err != nil {
+			// fallback to raw data
+			bodyReader = body
+		}
+	} else if encoding == "br" {
+		bodyReader = io.NopCloser(brotli.NewReader(body))
+	} else if encoding == "deflate" {
+		bodyReader = flate.NewReader(body)
+	} else {
+		bodyReader = body
+	}
+	return bodyReader
+}
+
 func (r *SimpleRunner) Dump(req *ffuf.Request) ([]byte, error) {
 	var httpreq *http.Request
 	var err error
@@ -0,0 +1,84 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ffuf/ffuf/v2/pkg/ffuf"
+)
+
+// startCookieServer sets a cookie on the first request of a client and echoes the received cookies
+func startCookieServer(t *testing.T) *httptest.Server {
+	counter := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, err := r.Cookie("visit"); err != nil {
+			counter++
+			http.SetCookie(w, &http.Cookie{Name: "visit", Value: fmt.Sprintf("v%d", counter), Path: "/"})
+		}
+		fmt.Fprint(w, r.Header.Get("Cookie"))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func executeCookieRequest(t *testing.T, r ffuf.RunnerProvider, url string) ffuf.Response {
+	basereq := ffuf.Request{Method: "GET", Url: url, Headers: map[string]string{}}
+	req, _ := r.Prepare(map[string][]byte{}, &basereq)
+	resp, err := r.Execute(&req)
+	if err != nil {
+		t.Fatalf("Failed to execute request: %v", err)
+	}
+	return resp
+}
+
+func TestCookieJar(t *testing.T) {
+	srv := startCookieServer(t)
+	conf := ffuf.NewConfig(context.Background(), func() {})
+	conf.Timeout = 5
+	conf.OutputDirectory = t.TempDir()
+
+	r, _ := NewRunnerByName("http", &conf, false)
+	executeCookieRequest(t, r, srv.URL+"/")
+	if resp := executeCookieRequest(t, r, srv.URL+"/"); string(resp.Data) != "" {
+		t.Errorf("Expected no cookies to be stored without a cookie jar, got %s", resp.Data)
+	}
+
+	conf.CookieJar = "shared"
+	r, _ = NewRunnerByName("http", &conf, false)
+	executeCookieRequest(t, r, srv.URL+"/")
+	resp := executeCookieRequest(t, r, srv.URL+"/")
+	if string(resp.Data) != "visit=v3" {
+		t.Errorf("Expected the cookie to be sent from the jar, got %s", resp.Data)
+	}
+	if !strings.Contains(resp.Request.Raw, "Cookie: visit=v3") {
+		t.Errorf("Expected the cookies from the jar in the raw request dump, got %s", resp.Request.Raw)
+	}
+
+	// Each thread gets its own cookie
+	conf.CookieJar = "thread"
+	conf.Threads = 2
+	r, _ = NewRunnerByName("http", &conf, false)
+	seen := make(map[string]bool)
+	for i := 0; i < 4; i++ {
+		seen[string(executeCookieRequest(t, r, srv.URL+"/").Data)] = true
+	}
+	if !seen["visit=v4"] || !seen["visit=v5"] || len(seen) != 3 {
+		t.Errorf("Expected a cookie for each of the two threads, got %v", seen)
+	}
+
+	cookiefile := filepath.Join(t.TempDir(), "cookies.txt")
+	host := strings.Split(strings.TrimPrefix(srv.URL, "http://"), ":")[0]
+	_ = os.WriteFile(cookiefile, []byte(host+"\tFALSE\t/\tFALSE\t0\tvisit\tseeded\n"), 0644)
+	conf.CookieJar = "shared"
+	conf.CookieJarFile = cookiefile
+	r, _ = NewRunnerByName("http", &conf, false)
+	if resp := executeCookieRequest(t, r, srv.URL+"/"); string(resp.Data) != "visit=seeded" {
+		t.Errorf("Expected the cookie from the cookie file, got %s", resp.Data)
+	}
+}
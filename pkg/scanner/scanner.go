@@ -0,0 +1,457 @@
+// Package scanner runs ffuf jobs from Go programs.
+//
+// A Scanner is set up with functional options, and it reports the results to a callback and returns them when the
+// job is done:
+//
+//	s, err := scanner.New(
+//		scanner.WithURL("https://example.org/FUZZ"),
+//		scanner.WithWordlist("/path/to/wordlist.txt", "FUZZ"),
+//		scanner.WithMatcher("mc", "200,301"),
+//		scanner.WithResultHandler(func(res ffuf.Result) {
+//			fmt.Println(res.Url)
+//		}),
+//	)
+//	if err != nil {
+//		return err
+//	}
+//	results, err := s.Run(ctx)
+//
+// Unlike the ffuf command, a Scanner does not print anything, handle signals, write the ffuf history or read input
+// from the terminal, unless asked to with WithStdout, WithSignalHandling, WithHistory and WithInteractive. The internal
+// logging of the job is discarded unless a logger is set with WithLogger. Cancelling the context stops the job.
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"sync"
+
+	"github.com/ffuf/ffuf/v2/pkg/ffuf"
+	"github.com/ffuf/ffuf/v2/pkg/interactive"
+)
+
+// Option configures a Scanner
+type Option func(s *Scanner) error
+
+// Scanner runs an ffuf job with the options it was created with
+type Scanner struct {
+	options     *ffuf.ConfigOptions
+	set         map[string]bool
+	onResult    func(ffuf.Result)
+	onMessage   func(level string, message string)
+	stdout      bool
+	signals     bool
+	history     bool
+	interactive bool
+	logger      *log.Logger
+}
+
+// New returns a Scanner with the default options of the ffuf command, changed by the options given
+func New(opts ...Option) (*Scanner, error) {
+	s := &Scanner{options: ffuf.NewConfigOptions(), set: make(map[string]bool)}
+	// Report everything to the caller instead of the terminal by default
+	s.options.General.Noninteractive = true
+	s.options.General.Quiet = true
+	for _, opt := range opts {
+		if err := opt(s); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// WithConfigOptions changes any of the options, using the same fields as the ffuf configuration file
+func WithConfigOptions(fn func(opts *ffuf.ConfigOptions)) Option {
+	return func(s *Scanner) error {
+		fn(s.options)
+		return nil
+	}
+}
+
+// WithURL sets the target URL (-u)
+func WithURL(url string) Option {
+	return func(s *Scanner) error {
+		s.options.HTTP.URL = url
+		return nil
+	}
+}
+
+// WithWordlist adds a wordlist file (-w) for the keyword
+func WithWordlist(path string, keyword string) Option {
+	return func(s *Scanner) error {
+		s.options.Input.Wordlists = append(s.options.Input.Wordlists, path+":"+keyword)
+		return nil
+	}
+}
+
+// WithMethod sets the HTTP method (-X)
+func WithMethod(method string) Option {
+	return func(s *Scanner) error {
+		s.options.HTTP.Method = method
+		return nil
+	}
+}
+
+// WithHeader adds a request header (-H)
+func WithHeader(name string, value string) Option {
+	return func(s *Scanner) error {
+		s.options.HTTP.Headers = append(s.options.HTTP.Headers, name+": "+value)
+		return nil
+	}
+}
+
+// WithData sets the request body (-d)
+func WithData(data string) Option {
+	return func(s *Scanner) error {
+		s.options.HTTP.Data = data
+		return nil
+	}
+}
+
+// WithThreads sets the number of concurrent requests (-t)
+func WithThreads(threads int) Option {
+	return func(s *Scanner) error {
+		if threads < 1 {
+			return fmt.Errorf("number of threads must be at least 1, got %d", threads)
+		}
+		s.options.General.Threads = threads
+		return nil
+	}
+}
+
+// WithRate sets the maximum rate of requests per second (-rate)
+func WithRate(rate int) Option {
+	return func(s *Scanner) error {
+		s.options.General.Rate = rate
+		return nil
+	}
+}
+
+// WithAutoCalibration enables autocalibration (-ac)
+func WithAutoCalibration() Option {
+	return func(s *Scanner) error {
+		s.options.General.AutoCalibration = true
+		return nil
+	}
+}
+
+// WithMatcher sets a matcher by its flag name: mc, ml, mr, ms, mt or mw. Like with the ffuf command, the default
+// status code matcher is not used when other matchers are set, unless mc is set too.
+func WithMatcher(name string, value string) Option {
+	return func(s *Scanner) error {
+		switch name {
+		case "mc":
+			s.options.Matcher.Status = value
+		case "ml":
+			s.options.Matcher.Lines = value
+		case "mr":
+			s.options.Matcher.Regexp = value
+		case "ms":
+			s.options.Matcher.Size = value
+		case "mt":
+			s.options.Matcher.Time = value
+		case "mw":
+			s.options.Matcher.Words = value
+		default:
+			return fmt.Errorf("unknown matcher %s, expected one of: mc, ml, mr, ms, mt, mw", name)
+		}
+		s.set[name] = true
+		return nil
+	}
+}
+
+// WithFilter sets a filter by its flag name: fc, fl, fr, fs, fsim, ft or fw
+func WithFilter(name string, value string) Option {
+	return func(s *Scanner) error {
+		switch name {
+		case "fc":
+			s.options.Filter.Status = value
+		case "fl":
+			s.options.Filter.Lines = value
+		case "fr":
+			s.options.Filter.Regexp = value
+		case "fs":
+			s.options.Filter.Size = value
+		case "fsim":
+			s.options.Filter.Similarity = value
+		case "ft":
+			s.options.Filter.Time = value
+		case "fw":
+			s.options.Filter.Words = value
+		default:
+			return fmt.Errorf("unknown filter %s, expected one of: fc, fl, fr, fs, fsim, ft, fw", name)
+		}
+		return nil
+	}
+}
+
+// WithResultHandler sets a function called with each result as soon as it is found. The function is called from
+// multiple goroutines.
+func WithResultHandler(fn func(res ffuf.Result)) Option {
+	return func(s *Scanner) error {
+		s.onResult = fn
+		return nil
+	}
+}
+
+// WithMessageHandler sets a function called with the info, warning and error messages of the job. The level is
+// one of: info, warning, error.
+func WithMessageHandler(fn func(level string, message string)) Option {
+	return func(s *Scanner) error {
+		s.onMessage = fn
+		return nil
+	}
+}
+
+// WithStdout prints the banner, progress and results to the terminal, and writes the output files (-o, -od), like
+// the ffuf command. The output providers set in the options (-output-provider) are used without it too.
+func WithStdout() Option {
+	return func(s *Scanner) error {
+		s.stdout = true
+		s.options.General.Quiet = false
+		return nil
+	}
+}
+
+// WithSignalHandling stops the job on SIGINT and SIGTERM
+func WithSignalHandling() Option {
+	return func(s *Scanner) error {
+		s.signals = true
+		return nil
+	}
+}
+
+// WithHistory writes the jobs to the ffuf history, for looking up FFUFHASH values with ffuf -search
+func WithHistory() Option {
+	return func(s *Scanner) error {
+		s.history = true
+		return nil
+	}
+}
+
+// WithInteractive enables the interactive console of the ffuf command on the terminal
+func WithInteractive() Option {
+	return func(s *Scanner) error {
+		s.interactive = true
+		s.options.General.Noninteractive = false
+		return nil
+	}
+}
+
+// WithLogger writes the internal logging of the job, like the errors of each failed request, to the logger. The
+// ffuf command writes it to the -debug-log file.
+func WithLogger(logger *log.Logger) Option {
+	return func(s *Scanner) error {
+		s.logger = logger
+		return nil
+	}
+}
+
+// Options returns the options the job is run with
+func (s *Scanner) Options() *ffuf.ConfigOptions {
+	return s.options
+}
+
+// Run runs the job until it is done or the context is cancelled, and returns the results
+func (s *Scanner) Run(ctx context.Context) ([]ffuf.Result, error) {
+	jobctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	conf, err := ffuf.ConfigFromOptions(s.options, jobctx, cancel)
+	if err != nil {
+		return nil, err
+	}
+	job, err := PrepareJob(conf)
+	if err != nil {
+		return nil, err
+	}
+	if err := SetupFilters(s.options, conf, func(name string) bool { return s.set[name] }); err != nil {
+		return nil, err
+	}
+	job.HandleSignals = s.signals
+	job.WriteHistory = s.history
+	job.Logger = s.logger
+	if job.Logger == nil {
+		job.Logger = log.New(io.Discard, "", 0)
+	}
+	out := &callbackOutput{onResult: s.onResult, onMessage: s.onMessage, results: make([]ffuf.Result, 0)}
+	if s.stdout || len(conf.OutputProviders) > 0 {
+		out.OutputProvider = job.Output
+	}
+	job.Output = out
+	if s.interactive {
+		go func() {
+			_ = interactive.Handle(job)
+		}()
+	}
+	go func() {
+		<-jobctx.Done()
+		job.Stop()
+	}()
+	if err := job.Run(); err != nil {
+		return nil, err
+	}
+	return out.Results(), ctx.Err()
+}
+
+// callbackOutput reports the results and messages of a job to the callbacks of a Scanner, and passes them on to
+// the terminal output if one is in use
+type callbackOutput struct {
+	ffuf.OutputProvider
+	mutex sync.Mutex
+	// results of all of the queue jobs, the ones of the current queue job start from the index current
+	results   []ffuf.Result
+	current   int
+	onResult  func(ffuf.Result)
+	onMessage func(level string, message string)
+}
+
+// Results returns the results found by the job
+func (o *callbackOutput) Results() []ffuf.Result {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	return append([]ffuf.Result{}, o.results...)
+}
+
+func (o *callbackOutput) Result(resp ffuf.Response) {
+	if o.OutputProvider != nil {
+		o.OutputProvider.Result(resp)
+	}
+	res := ffuf.NewResult(&resp)
+	o.mutex.Lock()
+	o.results = append(o.results, res)
+	o.mutex.Unlock()
+	if o.onResult != nil {
+		o.onResult(res)
+	}
+}
+
+func (o *callbackOutput) Record(resp ffuf.Response) {
+	if rec, ok := o.OutputProvider.(ffuf.ResponseRecorder); ok {
+		rec.Record(resp)
+	}
+}
+
+func (o *callbackOutput) message(level string, message string) {
+	if o.onMessage != nil {
+		o.onMessage(level, strings.TrimSpace(message))
+	}
+}
+
+func (o *callbackOutput) Info(infostring string) {
+	if o.OutputProvider != nil {
+		o.OutputProvider.Info(infostring)
+	}
+	o.message("info", infostring)
+}
+
+func (o *callbackOutput) Warning(warnstring string) {
+	if o.OutputProvider != nil {
+		o.OutputProvider.Warning(warnstring)
+	}
+	o.message("warning", warnstring)
+}
+
+func (o *callbackOutput) Error(errstring string) {
+	if o.OutputProvider != nil {
+		o.OutputProvider.Error(errstring)
+	}
+	o.message("error", errstring)
+}
+
+func (o *callbackOutput) Banner() {
+	if o.OutputProvider != nil {
+		o.OutputProvider.Banner()
+	}
+}
+
+func (o *callbackOutput) Progress(status ffuf.Progress) {
+	if o.OutputProvider != nil {
+		o.OutputProvider.Progress(status)
+	}
+}
+
+func (o *callbackOutput) Finalize() error {
+	if o.OutputProvider != nil {
+		return o.OutputProvider.Finalize()
+	}
+	return nil
+}
+
+func (o *callbackOutput) Raw(output string) {
+	if o.OutputProvider != nil {
+		o.OutputProvider.Raw(output)
+	}
+}
+
+func (o *callbackOutput) PrintResult(res ffuf.Result) {
+	if o.OutputProvider != nil {
+		o.OutputProvider.PrintResult(res)
+	}
+}
+
+func (o *callbackOutput) SaveFile(filename, format string) error {
+	if o.OutputProvider != nil {
+		return o.OutputProvider.SaveFile(filename, format)
+	}
+	return nil
+}
+
+func (o *callbackOutput) GetResults() []ffuf.Result {
+	if o.OutputProvider != nil {
+		return o.OutputProvider.GetResults()
+	}
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	return append([]ffuf.Result{}, o.results[:o.current]...)
+}
+
+// SetResults replaces the results of the finished queue jobs, eg. when a job is resumed
+func (o *callbackOutput) SetResults(results []ffuf.Result) {
+	if o.OutputProvider != nil {
+		o.OutputProvider.SetResults(results)
+	}
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	o.results = append(append([]ffuf.Result{}, results...), o.results[o.current:]...)
+	o.current = len(results)
+}
+
+func (o *callbackOutput) GetCurrentResults() []ffuf.Result {
+	if o.OutputProvider != nil {
+		return o.OutputProvider.GetCurrentResults()
+	}
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	return append([]ffuf.Result{}, o.results[o.current:]...)
+}
+
+// SetCurrentResults replaces the results of the current queue job, eg. when a job is resumed
+func (o *callbackOutput) SetCurrentResults(results []ffuf.Result) {
+	if o.OutputProvider != nil {
+		o.OutputProvider.SetCurrentResults(results)
+	}
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	o.results = append(o.results[:o.current:o.current], results...)
+}
+
+func (o *callbackOutput) Reset() {
+	if o.OutputProvider != nil {
+		o.OutputProvider.Reset()
+	}
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	o.results = o.results[:o.current]
+}
+
+func (o *callbackOutput) Cycle() {
+	if o.OutputProvider != nil {
+		o.OutputProvider.Cycle()
+	}
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	o.current = len(o.results)
+}
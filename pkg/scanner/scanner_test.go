@@ -0,0 +1,177 @@
+package scanner
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ffuf/ffuf/v2/pkg/ffuf"
+)
+
+// newTestServer responds with 200 to the paths ending in 7, and with 404 to the others
+func newTestServer(delay time.Duration) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(delay)
+		if strings.HasSuffix(r.URL.Path, "7") {
+			w.WriteHeader(200)
+			fmt.Fprint(w, "found")
+			return
+		}
+		w.WriteHeader(404)
+	}))
+}
+
+func writeWordlist(t *testing.T, count int) string {
+	words := make([]string, 0, count)
+	for i := 0; i < count; i++ {
+		words = append(words, fmt.Sprint(i))
+	}
+	path := filepath.Join(t.TempDir(), "wordlist.txt")
+	if err := os.WriteFile(path, []byte(strings.Join(words, "\n")), 0644); err != nil {
+		t.Fatalf("Failed to write the wordlist: %v", err)
+	}
+	return path
+}
+
+func TestScannerRun(t *testing.T) {
+	ffuf.HISTORYDIR = t.TempDir()
+	srv := newTestServer(0)
+	defer srv.Close()
+
+	var mutex sync.Mutex
+	found := make(map[string]bool)
+	s, err := New(
+		WithURL(srv.URL+"/FUZZ"),
+		WithWordlist(writeWordlist(t, 100), "FUZZ"),
+		WithThreads(5),
+		WithResultHandler(func(res ffuf.Result) {
+			mutex.Lock()
+			defer mutex.Unlock()
+			found[string(res.Input["FUZZ"])] = true
+		}),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create the scanner: %v", err)
+	}
+	results, err := s.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if len(results) != 10 {
+		t.Errorf("Expected 10 results, got %d", len(results))
+	}
+	if len(found) != 10 || !found["7"] || !found["97"] {
+		t.Errorf("Expected the result handler to be called with the 10 matching inputs, got %v", found)
+	}
+	entries, _ := os.ReadDir(ffuf.HISTORYDIR)
+	if len(entries) != 0 {
+		t.Errorf("Expected no history to be written, got %d entries", len(entries))
+	}
+}
+
+func TestScannerMatcher(t *testing.T) {
+	ffuf.HISTORYDIR = t.TempDir()
+	srv := newTestServer(0)
+	defer srv.Close()
+
+	s, err := New(
+		WithURL(srv.URL+"/FUZZ"),
+		WithWordlist(writeWordlist(t, 20), "FUZZ"),
+		WithMatcher("ms", "0"),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create the scanner: %v", err)
+	}
+	results, err := s.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	// The default status code matcher is replaced by the size matcher
+	if len(results) != 18 {
+		t.Errorf("Expected the 18 empty 404 responses to match, got %d", len(results))
+	}
+	if _, err := New(WithMatcher("foo", "1")); err == nil {
+		t.Errorf("Expected an error for an unknown matcher")
+	}
+}
+
+func TestScannerCancel(t *testing.T) {
+	ffuf.HISTORYDIR = t.TempDir()
+	srv := newTestServer(10 * time.Millisecond)
+	defer srv.Close()
+
+	s, err := New(
+		WithURL(srv.URL+"/FUZZ"),
+		WithWordlist(writeWordlist(t, 10000), "FUZZ"),
+		WithThreads(2),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create the scanner: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err = s.Run(ctx)
+	if err != context.DeadlineExceeded {
+		t.Errorf("Expected the context error, got %v", err)
+	}
+	if time.Since(start) > 5*time.Second {
+		t.Errorf("Expected the job to stop when the context is done, took %s", time.Since(start))
+	}
+}
+
+func TestScannerLogger(t *testing.T) {
+	ffuf.HISTORYDIR = t.TempDir()
+	srv := newTestServer(0)
+	// Every request fails with a connection error
+	srv.Close()
+
+	var buf bytes.Buffer
+	s, err := New(
+		WithURL(srv.URL+"/FUZZ"),
+		WithWordlist(writeWordlist(t, 5), "FUZZ"),
+		WithLogger(log.New(&buf, "", 0)),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create the scanner: %v", err)
+	}
+	if _, err := s.Run(context.Background()); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if strings.Count(buf.String(), "\n") != 5 {
+		t.Errorf("Expected the errors of the 5 failed requests to be logged, got %q", buf.String())
+	}
+}
+
+func TestCallbackOutputResults(t *testing.T) {
+	result := func(pos int) ffuf.Response {
+		return ffuf.Response{StatusCode: 200, Request: &ffuf.Request{Position: pos, Input: map[string][]byte{"FUZZ": []byte(fmt.Sprint(pos))}}}
+	}
+	out := &callbackOutput{results: make([]ffuf.Result, 0)}
+	out.Result(result(1))
+	out.Cycle()
+	out.Result(result(2))
+	if len(out.GetResults()) != 1 || len(out.GetCurrentResults()) != 1 || out.GetCurrentResults()[0].Position != 2 {
+		t.Errorf("Expected the results of the finished and the current queue job to be kept apart, got %v and %v", out.GetResults(), out.GetCurrentResults())
+	}
+
+	// A resumed job restores the results of the previous run
+	out.SetResults([]ffuf.Result{{Position: 3}, {Position: 4}})
+	out.SetCurrentResults([]ffuf.Result{{Position: 5}})
+	positions := make([]int, 0)
+	for _, res := range out.Results() {
+		positions = append(positions, res.Position)
+	}
+	if fmt.Sprint(positions) != "[3 4 5]" || len(out.GetCurrentResults()) != 1 || out.GetCurrentResults()[0].Position != 5 {
+		t.Errorf("Expected the restored results to replace the collected ones, got %v", positions)
+	}
+}
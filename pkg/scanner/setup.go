@@ -0,0 +1,123 @@
+package scanner
+
+import (
+	"github.com/ffuf/ffuf/v2/pkg/ffuf"
+	"github.com/ffuf/ffuf/v2/pkg/filter"
+	"github.com/ffuf/ffuf/v2/pkg/input"
+	"github.com/ffuf/ffuf/v2/pkg/output"
+	"github.com/ffuf/ffuf/v2/pkg/runner"
+	"github.com/ffuf/ffuf/v2/pkg/scraper"
+)
+
+// PrepareJob creates a Job with the input, runner, output and scraper providers for the configuration
+func PrepareJob(conf *ffuf.Config) (*ffuf.Job, error) {
+	var err error
+	job := ffuf.NewJob(conf)
+	var errs ffuf.Multierror
+	job.Input, errs = input.NewInputProvider(conf)
+	// We only have http runner right now
+	job.Runner, err = runner.NewRunnerByName("http", conf, false)
+	if err != nil {
+		errs.Add(err)
+	} else if len(conf.ReplayProxyURL) > 0 {
+		// The replay runner is set up from the same configuration, so it fails only if the main runner does
+		job.ReplayRunner, _ = runner.NewRunnerByName("http", conf, true)
+	}
+	job.Output, err = output.NewOutputProvider(conf)
+	if err != nil {
+		errs.Add(err)
+	}
+
+	// Initialize scraper
+	newscraper, scraper_err := scraper.FromDir(ffuf.SCRAPERDIR, conf.Scrapers)
+	if scraper_err.ErrorOrNil() != nil {
+		errs.Add(scraper_err.ErrorOrNil())
+	}
+	job.Scraper = newscraper
+	if conf.ScraperFile != "" {
+		err = job.Scraper.AppendFromFile(conf.ScraperFile)
+		if err != nil {
+			errs.Add(err)
+		}
+	}
+	return job, errs.ErrorOrNil()
+}
+
+// SetupFilters adds the matchers and filters of the options to the configuration. isSet tells if a matcher was set
+// explicitly by its flag name, eg. "mc" or "mr": the default status code matcher is left out if other matchers are
+// set, unless it was set explicitly too.
+func SetupFilters(parseOpts *ffuf.ConfigOptions, conf *ffuf.Config, isSet func(name string) bool) error {
+	errs := ffuf.NewMultierror()
+	conf.MatcherManager = filter.NewMatcherManager()
+	// If any other matcher is set, ignore -mc default value
+	statusSet := isSet("mc")
+	matcherSet := isSet("ms") || isSet("ml") || isSet("mr") || isSet("mt") || isSet("mw")
+	// Only set default matchers if no
+	if (statusSet || !matcherSet) && parseOpts.Matcher.Status != "" {
+		if err := conf.MatcherManager.AddMatcher("status", parseOpts.Matcher.Status); err != nil {
+			errs.Add(err)
+		}
+	}
+
+	if parseOpts.Filter.Status != "" {
+		if err := conf.MatcherManager.AddFilter("status", parseOpts.Filter.Status, false); err != nil {
+			errs.Add(err)
+		}
+	}
+	if parseOpts.Filter.Size != "" {
+		if err := conf.MatcherManager.AddFilter("size", parseOpts.Filter.Size, false); err != nil {
+			errs.Add(err)
+		}
+	}
+	if parseOpts.Filter.Regexp != "" {
+		if err := conf.MatcherManager.AddFilter("regexp", parseOpts.Filter.Regexp, false); err != nil {
+			errs.Add(err)
+		}
+	}
+	if parseOpts.Filter.Words != "" {
+		if err := conf.MatcherManager.AddFilter("word", parseOpts.Filter.Words, false); err != nil {
+			errs.Add(err)
+		}
+	}
+	if parseOpts.Filter.Lines != "" {
+		if err := conf.MatcherManager.AddFilter("line", parseOpts.Filter.Lines, false); err != nil {
+			errs.Add(err)
+		}
+	}
+	if parseOpts.Filter.Time != "" {
+		if err := conf.MatcherManager.AddFilter("time", parseOpts.Filter.Time, false); err != nil {
+			errs.Add(err)
+		}
+	}
+	if parseOpts.Filter.Similarity != "" {
+		if err := conf.MatcherManager.AddFilter("similarity", parseOpts.Filter.Similarity, false); err != nil {
+			errs.Add(err)
+		}
+	}
+	if parseOpts.Matcher.Size != "" {
+		if err := conf.MatcherManager.AddMatcher("size", parseOpts.Matcher.Size); err != nil {
+			errs.Add(err)
+		}
+	}
+	if parseOpts.Matcher.Regexp != "" {
+		if err := conf.MatcherManager.AddMatcher("regexp", parseOpts.Matcher.Regexp); err != nil {
+			errs.Add(err)
+		}
+	}
+	if parseOpts.Matcher.Words != "" {
+		if err := conf.MatcherManager.AddMatcher("word", parseOpts.Matcher.Words); err != nil {
+			errs.Add(err)
+		}
+	}
+	if parseOpts.Matcher.Lines != "" {
+		if err := conf.MatcherManager.AddMatcher("line", parseOpts.Matcher.Lines); err != nil {
+			errs.Add(err)
+		}
+	}
+	if parseOpts.Matcher.Time != "" {
+		if err := conf.MatcherManager.AddMatcher("time", parseOpts.Matcher.Time); err != nil {
+			errs.Add(err)
+		}
+	}
+	return errs.ErrorOrNil()
+}
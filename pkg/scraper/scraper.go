@@ -48,6 +48,10 @@ func FromDir(dirname string, activestr string) (ffuf.Scraper, ffuf.Multierror) {
 	errs := ffuf.NewMultierror()
 	activegrps := parseActiveGroups(activestr)
 	all_files, err := os.ReadDir(ffuf.SCRAPERDIR)
+	if os.IsNotExist(err) {
+		// The configuration directory has not been created, eg. when ffuf is used as a library
+		return &scr, errs
+	}
 	if err != nil {
 		errs.Add(err)
 		return &scr, errs
@@ -134,6 +138,45 @@ func (r *ScraperRule) init() error {
 	return err
 }
 
+// NewRule creates a regexp or query rule matching against the response headers and body
+func NewRule(ruletype string, rule string) (*ScraperRule, error) {
+	r := ScraperRule{Rule: rule, Type: ruletype, Target: "all"}
+	if ruletype != "regexp" && ruletype != "query" {
+		return &r, fmt.Errorf("unknown rule type %s, expected regexp or query", ruletype)
+	}
+	return &r, r.init()
+}
+
+// Value returns the first value found by the rule from the response. A regexp returns its first capture group if
+// it has one, and a query returns the value attribute of an element without text, eg. a hidden input field.
+func (r *ScraperRule) Value(resp *ffuf.Response) (string, bool) {
+	if r.Type == "query" {
+		doc, err := goquery.NewDocumentFromReader(strings.NewReader(string(resp.Data)))
+		if err != nil {
+			return "", false
+		}
+		sel := doc.Find(r.Rule).First()
+		if sel.Length() == 0 {
+			return "", false
+		}
+		if text := strings.TrimSpace(sel.Text()); text != "" {
+			return text, true
+		}
+		return sel.AttrOr("value", sel.AttrOr("content", "")), true
+	}
+	if r.compiledRule == nil {
+		return "", false
+	}
+	match := r.compiledRule.FindStringSubmatch(headerString(resp.Headers) + string(resp.Data))
+	if match == nil {
+		return "", false
+	}
+	if len(match) > 1 {
+		return match[1], true
+	}
+	return match[0], true
+}
+
 func (r *ScraperRule) Check(data string) []string {
 	if r.Type == "regexp" {
 		return r.checkRegexp(data)
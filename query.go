@@ -0,0 +1,119 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/ffuf/ffuf/v2/pkg/output"
+)
+
+// runQuery prints the results stored by the sqlite output provider, and returns the exit code
+func runQuery(args []string) int {
+	flags := flag.NewFlagSet("ffuf query", flag.ExitOnError)
+	db := flags.String("db", "", "The sqlite database written with -output-provider sqlite:FILE")
+	listRuns := flags.Bool("runs", false, "List the runs stored in the database")
+	jsonOut := flags.Bool("json", false, "Print the results as newline-delimited JSON records")
+	q := output.QueryOptions{}
+	flags.Int64Var(&q.Run, "run", 0, "Only the results of the run with this id")
+	flags.StringVar(&q.Host, "host", "", "Only the results of this host")
+	flags.StringVar(&q.Url, "url", "", "Only the results with an URL containing this string")
+	flags.StringVar(&q.MatchStatus, "mc", "", "Match HTTP status codes, eg. 200-299,403")
+	flags.StringVar(&q.MatchSize, "ms", "", "Match HTTP response size")
+	flags.StringVar(&q.MatchWords, "mw", "", "Match amount of words in response")
+	flags.StringVar(&q.MatchLines, "ml", "", "Match amount of lines in response")
+	flags.StringVar(&q.FilterStatus, "fc", "", "Filter HTTP status codes from response")
+	flags.StringVar(&q.FilterSize, "fs", "", "Filter HTTP response size")
+	flags.StringVar(&q.FilterWords, "fw", "", "Filter by amount of words in response")
+	flags.StringVar(&q.FilterLines, "fl", "", "Filter by amount of lines in the response")
+	flags.IntVar(&q.Limit, "limit", 0, "Maximum number of results to print")
+	_ = flags.Parse(args)
+	if *db == "" {
+		fmt.Fprintf(os.Stderr, "Usage: ffuf query -db results.db [options]\n")
+		flags.PrintDefaults()
+		return 1
+	}
+	if _, err := os.Stat(*db); err != nil {
+		fmt.Fprintf(os.Stderr, "Could not open the database: %s\n", err)
+		return 1
+	}
+	var err error
+	if *listRuns {
+		err = printRuns(os.Stdout, *db, *jsonOut)
+	} else {
+		err = printQuery(os.Stdout, *db, q, *jsonOut)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Encountered error(s): %s\n", err)
+		return 1
+	}
+	return 0
+}
+
+func printRuns(w io.Writer, db string, jsonOut bool) error {
+	runs, err := output.ListSQLiteRuns(db)
+	if err != nil {
+		return err
+	}
+	for _, r := range runs {
+		if jsonOut {
+			line, err := json.Marshal(r)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(w, "%s\n", line)
+			continue
+		}
+		finished := r.Finished
+		if finished == "" {
+			finished = "unfinished"
+		}
+		fmt.Fprintf(w, "[run %d] %s - %s, %d results: %s\n", r.ID, r.Started, finished, r.Results, r.CommandLine)
+	}
+	return nil
+}
+
+// queryResult prints the inputs as strings, the same way as the JSON output of ffuf
+type queryResult struct {
+	output.StoredResult
+	Input map[string]string `json:"input"`
+}
+
+func newQueryResult(r output.StoredResult) queryResult {
+	inputs := make(map[string]string)
+	for k, v := range r.Input {
+		inputs[k] = string(v)
+	}
+	return queryResult{StoredResult: r, Input: inputs}
+}
+
+func printQuery(w io.Writer, db string, q output.QueryOptions, jsonOut bool) error {
+	results, err := output.QuerySQLite(db, q)
+	if err != nil {
+		return err
+	}
+	for _, r := range results {
+		if jsonOut {
+			line, err := json.Marshal(newQueryResult(r))
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(w, "%s\n", line)
+			continue
+		}
+		inputs := make([]string, 0)
+		for k, v := range r.Input {
+			if k != "FFUFHASH" {
+				inputs = append(inputs, fmt.Sprintf("%s: %s", k, v))
+			}
+		}
+		sort.Strings(inputs)
+		fmt.Fprintf(w, "[run %d] %s [Status: %d, Size: %d, Words: %d, Lines: %d, Duration: %dms] %s\n", r.RunID, r.Url,
+			r.StatusCode, r.ContentLength, r.ContentWords, r.ContentLines, r.Duration.Milliseconds(), strings.Join(inputs, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/ffuf/ffuf/v2/pkg/ffuf"
+	"github.com/ffuf/ffuf/v2/pkg/scanner"
+)
+
+// runWorker runs the tasks of a coordinator started with -coordinator, and returns the exit code
+func runWorker(args []string) int {
+	hostname, _ := os.Hostname()
+	flags := flag.NewFlagSet("ffuf worker", flag.ExitOnError)
+	coordinator := flags.String("coordinator", "", "URL of the coordinator, eg. http://10.0.0.1:8700")
+	name := flags.String("name", fmt.Sprintf("%s-%d", hostname, os.Getpid()), "Name of the worker, shown in the messages of the coordinator")
+	secret := flags.String("secret", os.Getenv(ffuf.COORDINATOR_SECRET_ENV), "Shared secret of the coordinator, defaults to the value of "+ffuf.COORDINATOR_SECRET_ENV)
+	_ = flags.Parse(args)
+	if *coordinator == "" || *secret == "" {
+		fmt.Fprintf(os.Stderr, "Usage: ffuf worker -coordinator http://host:port [-secret secret] [-name name]\n")
+		flags.PrintDefaults()
+		return 1
+	}
+	if !strings.HasPrefix(*coordinator, "http://") && !strings.HasPrefix(*coordinator, "https://") {
+		*coordinator = "http://" + *coordinator
+	}
+	log.SetOutput(io.Discard)
+	worker := ffuf.NewWorker(strings.TrimSuffix(*coordinator, "/"), *name, newWorkerJob)
+	worker.Secret = *secret
+	fmt.Fprintf(os.Stderr, "Worker %s running the tasks of %s\n", *name, *coordinator)
+	if err := worker.Run(); err != nil {
+		fmt.Fprintf(os.Stderr, "Encountered error(s): %s\n", err)
+		return 1
+	}
+	return 0
+}
+
+// newWorkerJob sets up a job from the options sent by the coordinator, the same way as for a job from the command line
+func newWorkerJob(opts *ffuf.ConfigOptions) (*ffuf.Job, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	conf, err := ffuf.ConfigFromOptions(opts, ctx, cancel)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	job, err := scanner.PrepareJob(conf)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	// The options of the coordinator already tell which matchers are in use
+	if err := scanner.SetupFilters(opts, conf, func(string) bool { return false }); err != nil {
+		cancel()
+		return nil, err
+	}
+	return job, nil
+}